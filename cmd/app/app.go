@@ -1,15 +1,25 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/arielsrv/fxf/internal/features/messages/commands"
 	"github.com/arielsrv/fxf/internal/features/messages/delivery/http"
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/outbox"
 	"github.com/arielsrv/fxf/internal/features/messages/queries"
 	"github.com/arielsrv/fxf/internal/features/messages/repository"
 	"github.com/arielsrv/fxf/internal/features/messages/service"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
 	"github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/arielsrv/fxf/pkg/idempotency"
 	"github.com/arielsrv/fxf/pkg/logger"
 	"github.com/arielsrv/fxf/pkg/mediator"
 	"github.com/arielsrv/fxf/pkg/telemetry"
+	"github.com/arielsrv/fxf/pkg/tenant"
 
 	"go.uber.org/fx"
 )
@@ -17,13 +27,21 @@ import (
 func main() {
 	app := fx.New(
 		// Pkg Modules
+		// auth, tenant and idempotency install their middleware before the rate
+		// limiter and the routes of fiber.Module.
+		auth.Module,
+		tenant.Module,
+		idempotency.Module,
 		fiber.Module,
+		clock.Module,
 		mediator.Module,
 		logger.Module,
 		telemetry.Module,
 
 		// Feature Modules
 		repository.Module,
+		events.Module,
+		outbox.Module,
 		commands.Module,
 		queries.Module,
 		service.Module,
@@ -31,4 +49,10 @@ func main() {
 	)
 
 	app.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := telemetry.Shutdown(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to shut down tracer provider", slog.String("err", err.Error()))
+	}
 }
@@ -0,0 +1,40 @@
+// Command migrate applies the pending migrations of the SQLite message store
+// configured by the FXF_MESSAGES_SQLITE_* environment variables, e.g. before a
+// deployment that starts the application with FXF_MESSAGES_SQLITE_MIGRATE=false.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+)
+
+func main() {
+	ctx := context.Background()
+	if err := run(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to migrate the database", slog.String("err", err.Error()))
+		os.Exit(1)
+	}
+}
+
+// run applies the migrations, closing the database before returning so that its
+// write-ahead log is checkpointed whether or not they succeed.
+func run(ctx context.Context) error {
+	cfg := repository.NewStoreConfig().SQLite
+
+	db, err := repository.OpenSQLite(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to open the database: %w", err)
+	}
+	defer db.Close()
+
+	applied, err := repository.Migrate(ctx, db)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "database is up to date", slog.String("path", cfg.Path), slog.Int("applied", applied))
+	return nil
+}
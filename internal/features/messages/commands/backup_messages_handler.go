@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// BackupMessagesCommandHandler is the handler for BackupMessagesCommand.
+type BackupMessagesCommandHandler struct {
+	repo  repository.IMessageRepository
+	clock clock.Clock
+	dir   string
+}
+
+// NewBackupMessagesCommandHandler creates a new BackupMessagesCommandHandler writing
+// the backups to the backup directory of cfg.
+func NewBackupMessagesCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+	cfg repository.StoreConfig,
+) interfaces.IBackupMessagesCommandHandler {
+	return &BackupMessagesCommandHandler{repo: repo, clock: clock, dir: cfg.BackupDir}
+}
+
+// Handle handles the BackupMessagesCommand. The backup is written to a temporary
+// file that is renamed once complete, so that the directory never holds a partial
+// backup. It fails with models.ErrBackupUnsupported when the store cannot be backed
+// up online.
+func (h *BackupMessagesCommandHandler) Handle(
+	ctx context.Context,
+	_ *dtos.BackupMessagesCommand,
+) (*dtos.BackupMessagesCommandResponse, error) {
+	backup, ok := h.repo.(repository.IBackupRepository)
+	if !ok {
+		return nil, models.ErrBackupUnsupported
+	}
+
+	if err := os.MkdirAll(h.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	file, err := os.CreateTemp(h.dir, ".messages-*.partial")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	size, err := backup.Backup(ctx, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	now := h.clock.Now()
+	path := filepath.Join(h.dir, "messages-"+now.Format("20060102T150405.000000000Z")+".backup")
+	if err := os.Rename(file.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	return &dtos.BackupMessagesCommandResponse{Path: path, Size: size, CreatedAt: now}, nil
+}
+
+// registerBackupMessagesCommandHandler registers the command handler with MediatR.
+func registerBackupMessagesCommandHandler(handler interfaces.IBackupMessagesCommandHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.BackupMessagesCommand, *dtos.BackupMessagesCommandResponse](handler)
+}
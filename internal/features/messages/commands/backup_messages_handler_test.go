@@ -0,0 +1,65 @@
+package commands_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/commands"
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupMessagesCommandHandler_Handle(t *testing.T) {
+	t.Run("should write the backup to the backup directory", func(t *testing.T) {
+		// Arrange
+		repo, err := repository.NewBoltMessageRepository(repository.BoltConfig{
+			Path:        filepath.Join(t.TempDir(), "messages.bolt"),
+			OpenTimeout: time.Second,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { repo.Close() })
+		_, err = repo.CreateMessage(t.Context(), &models.Message{Text: "hello"})
+		require.NoError(t, err)
+
+		dir := filepath.Join(t.TempDir(), "backups")
+		handler := commands.NewBackupMessagesCommandHandler(
+			repo,
+			clock.Fixed(now),
+			repository.StoreConfig{BackupDir: dir},
+		)
+
+		// Act
+		result, err := handler.Handle(t.Context(), &dtos.BackupMessagesCommand{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, now, result.CreatedAt)
+		assert.Equal(t, dir, filepath.Dir(result.Path))
+		info, err := os.Stat(result.Path)
+		require.NoError(t, err)
+		assert.Equal(t, result.Size, info.Size())
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("should fail when the store cannot be backed up", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		handler := commands.NewBackupMessagesCommandHandler(
+			repository.NewInMemoryMessageRepository(), clock.Fixed(now), repository.StoreConfig{BackupDir: dir})
+
+		// Act
+		result, err := handler.Handle(t.Context(), &dtos.BackupMessagesCommand{})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrBackupUnsupported)
+		assert.Nil(t, result)
+	})
+}
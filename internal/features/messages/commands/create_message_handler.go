@@ -7,6 +7,8 @@ import (
 	"github.com/arielsrv/fxf/internal/features/messages/models"
 	"github.com/arielsrv/fxf/internal/features/messages/repository"
 	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
 	"github.com/mehdihadeli/go-mediatr"
 	"go.uber.org/fx"
 )
@@ -14,26 +16,56 @@ import (
 // Module exports the command handler functionality.
 var Module = fx.Options(
 	fx.Provide(NewCreateMessageCommandHandler),
+	fx.Provide(NewCreateMessagesBatchCommandHandler),
+	fx.Provide(NewUpdateMessageCommandHandler),
+	fx.Provide(NewDeleteMessageCommandHandler),
+	fx.Provide(NewRestoreMessageCommandHandler),
+	fx.Provide(NewBackupMessagesCommandHandler),
+	fx.Provide(NewRebuildProjectionsCommandHandler),
 	fx.Invoke(registerCreateMessageCommandHandler),
+	fx.Invoke(registerCreateMessagesBatchCommandHandler),
+	fx.Invoke(registerUpdateMessageCommandHandler),
+	fx.Invoke(registerDeleteMessageCommandHandler),
+	fx.Invoke(registerRestoreMessageCommandHandler),
+	fx.Invoke(registerBackupMessagesCommandHandler),
+	fx.Invoke(registerRebuildProjectionsCommandHandler),
 )
 
 // CreateMessageCommandHandler is the handler for CreateMessageCommand.
 type CreateMessageCommandHandler struct {
-	repo repository.IMessageRepository
+	repo  repository.IMessageRepository
+	clock clock.Clock
 }
 
 // NewCreateMessageCommandHandler creates a new CreateMessageCommandHandler.
-func NewCreateMessageCommandHandler(repo repository.IMessageRepository) interfaces.ICreateMessageCommandHandler {
-	return &CreateMessageCommandHandler{repo: repo}
+func NewCreateMessageCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+) interfaces.ICreateMessageCommandHandler {
+	return &CreateMessageCommandHandler{repo: repo, clock: clock}
 }
 
-// Handle handles the CreateMessageCommand.
+// Handle handles the CreateMessageCommand. An invalid command gets a
+// *dtos.ValidationError with the errors of its fields. The authenticated caller, if any, becomes
+// the author of the message, and a MessageCreatedEvent is published once it is stored,
+// through the outbox of the repository if it has one.
 func (h *CreateMessageCommandHandler) Handle(
 	ctx context.Context,
 	cmd *dtos.CreateMessageCommand,
 ) (*dtos.CreateMessageCommandResponse, error) {
+	if errs := cmd.Validate(); len(errs) > 0 {
+		return nil, &dtos.ValidationError{Errors: errs}
+	}
+
+	authorID, _ := auth.SubjectFromContext(ctx)
+	now := h.clock.Now()
+
 	message := &models.Message{
-		Text: cmd.Text,
+		Text:      cmd.Text,
+		Tags:      cmd.Tags,
+		AuthorID:  authorID,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 
 	createdMessage, err := h.repo.CreateMessage(ctx, message)
@@ -41,6 +73,8 @@ func (h *CreateMessageCommandHandler) Handle(
 		return nil, err
 	}
 
+	publishMessagesCreated(ctx, h.repo, createdMessage)
+
 	return &dtos.CreateMessageCommandResponse{ID: createdMessage.ID}, nil
 }
 
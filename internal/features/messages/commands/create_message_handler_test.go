@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/commands"
 	"github.com/arielsrv/fxf/internal/features/messages/dtos"
 	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +31,17 @@ func (m *MockMessageRepository) CreateMessage(ctx context.Context, message *mode
 	return args.Get(0).(*models.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	args := m.Called(ctx, messages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
 func (m *MockMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -35,12 +50,50 @@ func (m *MockMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID
 	return args.Get(0).(*models.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	args := m.Called(ctx, message, expectedVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) ListMessages(
+	ctx context.Context,
+	opts repository.ListMessagesOptions,
+) (*repository.ListMessagesResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ListMessagesResult), args.Error(1)
+}
+
+var now = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
 func TestCreateMessageCommandHandler_Handle(t *testing.T) {
 	t.Run("should create message successfully", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		mockRepo := new(MockMessageRepository)
-		handler := commands.NewCreateMessageCommandHandler(mockRepo)
+		handler := commands.NewCreateMessageCommandHandler(mockRepo, clock.Fixed(now))
 
 		cmd := &dtos.CreateMessageCommand{
 			Text: "test message",
@@ -69,7 +122,7 @@ func TestCreateMessageCommandHandler_Handle(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		mockRepo := new(MockMessageRepository)
-		handler := commands.NewCreateMessageCommandHandler(mockRepo)
+		handler := commands.NewCreateMessageCommandHandler(mockRepo, clock.Fixed(now))
 
 		cmd := &dtos.CreateMessageCommand{
 			Text: "test message",
@@ -88,43 +141,111 @@ func TestCreateMessageCommandHandler_Handle(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("should handle empty text", func(t *testing.T) {
+	t.Run("should reject an invalid message with the errors of its fields", func(t *testing.T) {
 		// Arrange
 		ctx := context.Background()
 		mockRepo := new(MockMessageRepository)
-		handler := commands.NewCreateMessageCommandHandler(mockRepo)
+		handler := commands.NewCreateMessageCommandHandler(mockRepo, clock.Fixed(now))
 
 		cmd := &dtos.CreateMessageCommand{
-			Text: "",
+			Text: " ",
+			Tags: make([]string, dtos.MaxTags+1),
 		}
 
-		expectedMessage := &models.Message{
-			ID:   uuid.New(),
-			Text: "",
-		}
+		// Act
+		result, err := handler.Handle(ctx, cmd)
+
+		// Assert
+		require.Nil(t, result)
+		var validationErr *dtos.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "text", validationErr.Errors[0].Field)
+		assert.Equal(t, "tags", validationErr.Errors[1].Field)
+		mockRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should set the author and the timestamps", func(t *testing.T) {
+		// Arrange
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewCreateMessageCommandHandler(mockRepo, clock.Fixed(now))
 
 		mockRepo.On("CreateMessage", ctx, mock.MatchedBy(func(msg *models.Message) bool {
-			return msg.Text == ""
-		})).Return(expectedMessage, nil)
+			return msg.AuthorID == "user-1" && msg.CreatedAt.Equal(now) && msg.UpdatedAt.Equal(now)
+		})).Return(&models.Message{ID: uuid.New()}, nil)
 
 		// Act
-		result, err := handler.Handle(ctx, cmd)
+		_, err := handler.Handle(ctx, &dtos.CreateMessageCommand{Text: "test message"})
 
 		// Assert
 		require.NoError(t, err)
-		require.NotNil(t, result)
-		assert.Equal(t, expectedMessage.ID, result.ID)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
+func TestAuthorizeMutation(t *testing.T) {
+	message := &models.Message{ID: uuid.New(), AuthorID: "user-1"}
+
+	t.Run("should allow the author", func(t *testing.T) {
+		// Arrange
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+
+		// Act
+		err := commands.AuthorizeMutation(ctx, message)
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("should allow admins", func(t *testing.T) {
+		// Arrange
+		ctx := auth.NewContext(context.Background(), &auth.Principal{
+			Subject: "admin-1",
+			Scopes:  []string{dtos.ScopeMessagesAdmin},
+		})
+
+		// Act
+		err := commands.AuthorizeMutation(ctx, message)
+
+		// Assert
+		require.NoError(t, err)
+	})
+
+	t.Run("should deny other authors and anonymous callers", func(t *testing.T) {
+		// Arrange
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-2"})
+
+		// Act
+		errOther := commands.AuthorizeMutation(ctx, message)
+		errAnonymous := commands.AuthorizeMutation(context.Background(), message)
+
+		// Assert
+		require.ErrorIs(t, errOther, auth.ErrForbidden)
+		require.ErrorIs(t, errAnonymous, auth.ErrUnauthenticated)
+	})
+
+	t.Run("should allow anonymous callers to modify anonymous messages only", func(t *testing.T) {
+		// Arrange
+		anonymous := &models.Message{ID: uuid.New()}
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+
+		// Act
+		errAnonymous := commands.AuthorizeMutation(context.Background(), anonymous)
+		errPrincipal := commands.AuthorizeMutation(ctx, anonymous)
+
+		// Assert
+		require.NoError(t, errAnonymous)
+		require.ErrorIs(t, errPrincipal, auth.ErrForbidden)
+	})
+}
+
 func TestNewCreateMessageCommandHandler(t *testing.T) {
 	t.Run("should create handler with repository", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockMessageRepository)
 
 		// Act
-		handler := commands.NewCreateMessageCommandHandler(mockRepo)
+		handler := commands.NewCreateMessageCommandHandler(mockRepo, clock.Fixed(now))
 
 		// Assert
 		require.NotNil(t, handler)
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// CreateMessagesBatchCommandHandler is the handler for CreateMessagesBatchCommand.
+type CreateMessagesBatchCommandHandler struct {
+	repo  repository.IMessageRepository
+	clock clock.Clock
+}
+
+// NewCreateMessagesBatchCommandHandler creates a new CreateMessagesBatchCommandHandler.
+func NewCreateMessagesBatchCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+) interfaces.ICreateMessagesBatchCommandHandler {
+	return &CreateMessagesBatchCommandHandler{repo: repo, clock: clock}
+}
+
+// Handle handles the CreateMessagesBatchCommand. The valid items are persisted in a
+// single repository operation, and a MessageCreatedEvent is published for each of
+// them. Invalid items are not an error: they are reported in the response.
+func (h *CreateMessagesBatchCommandHandler) Handle(
+	ctx context.Context,
+	cmd *dtos.CreateMessagesBatchCommand,
+) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	mode := cmd.Mode
+	if mode == "" {
+		mode = dtos.BatchModeAllOrNothing
+	}
+	if mode != dtos.BatchModeAllOrNothing && mode != dtos.BatchModeBestEffort {
+		return nil, fmt.Errorf("%w: unknown mode %q", models.ErrInvalidBatch, mode)
+	}
+	if len(cmd.Items) == 0 || len(cmd.Items) > dtos.MaxCreateBatchSize {
+		return nil, fmt.Errorf(
+			"%w: between 1 and %d items are required",
+			models.ErrInvalidBatch,
+			dtos.MaxCreateBatchSize,
+		)
+	}
+
+	authorID, _ := auth.SubjectFromContext(ctx)
+	now := h.clock.Now()
+
+	response := &dtos.CreateMessagesBatchCommandResponse{
+		Results: make([]*dtos.CreateMessagesBatchItemResult, len(cmd.Items)),
+	}
+	messages := make([]*models.Message, 0, len(cmd.Items))
+	pending := make([]*dtos.CreateMessagesBatchItemResult, 0, len(cmd.Items))
+	for i, item := range cmd.Items {
+		result := &dtos.CreateMessagesBatchItemResult{Index: i}
+		response.Results[i] = result
+
+		if item == nil {
+			item = &dtos.CreateMessageCommand{}
+		}
+		if errs := item.Validate(); len(errs) > 0 {
+			result.Status = dtos.BatchItemInvalid
+			result.Errors = errs
+			response.Failed++
+			continue
+		}
+
+		messages = append(messages, &models.Message{
+			Text:      item.Text,
+			Tags:      item.Tags,
+			AuthorID:  authorID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		pending = append(pending, result)
+	}
+
+	if response.Failed > 0 && mode == dtos.BatchModeAllOrNothing {
+		for _, result := range pending {
+			result.Status = dtos.BatchItemAborted
+		}
+		response.Failed = len(cmd.Items)
+		return response, nil
+	}
+	if len(messages) == 0 {
+		return response, nil
+	}
+
+	created, err := h.repo.CreateMessages(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, message := range created {
+		id := message.ID
+		pending[i].ID = &id
+		pending[i].Status = dtos.BatchItemCreated
+		response.Created++
+	}
+	publishMessagesCreated(ctx, h.repo, created...)
+	return response, nil
+}
+
+// registerCreateMessagesBatchCommandHandler registers the command handler with MediatR.
+func registerCreateMessagesBatchCommandHandler(handler interfaces.ICreateMessagesBatchCommandHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.CreateMessagesBatchCommand, *dtos.CreateMessagesBatchCommandResponse](
+		handler,
+	)
+}
@@ -0,0 +1,182 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/commands"
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/google/uuid"
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder records the events published through MediatR.
+type eventRecorder struct {
+	events []*events.MessageCreatedEvent
+}
+
+func (r *eventRecorder) Handle(_ context.Context, event *events.MessageCreatedEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func recordEvents(t *testing.T) *eventRecorder {
+	t.Helper()
+	recorder := &eventRecorder{}
+	mediatr.ClearNotificationRegistrations()
+	t.Cleanup(mediatr.ClearNotificationRegistrations)
+	require.NoError(t, mediatr.RegisterNotificationHandler[*events.MessageCreatedEvent](recorder))
+	return recorder
+}
+
+func TestCreateMessagesBatchCommandHandler_Handle(t *testing.T) {
+	t.Run("should create every item and publish an event per message", func(t *testing.T) {
+		// Arrange
+		recorder := recordEvents(t)
+		repo := repository.NewInMemoryMessageRepository()
+		handler := commands.NewCreateMessagesBatchCommandHandler(repo, clock.Fixed(now))
+
+		cmd := &dtos.CreateMessagesBatchCommand{Items: []*dtos.CreateMessageCommand{{Text: "first"}, {Text: "second"}}}
+
+		// Act
+		result, err := handler.Handle(t.Context(), cmd)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Equal(t, 0, result.Failed)
+		require.Len(t, result.Results, 2)
+		for i, item := range result.Results {
+			assert.Equal(t, i, item.Index)
+			assert.Equal(t, dtos.BatchItemCreated, item.Status)
+			require.NotNil(t, item.ID)
+
+			stored, err := repo.GetMessageByID(t.Context(), *item.ID)
+			require.NoError(t, err)
+			assert.Equal(t, cmd.Items[i].Text, stored.Text)
+			assert.Equal(t, now, stored.CreatedAt)
+		}
+		require.Len(t, recorder.events, 2)
+		assert.Equal(t, *result.Results[0].ID, recorder.events[0].ID)
+	})
+
+	t.Run("should leave the events to the outbox of the repository", func(t *testing.T) {
+		// Arrange
+		recorder := recordEvents(t)
+		repo, err := repository.NewBoltMessageRepository(repository.BoltConfig{
+			Path: filepath.Join(t.TempDir(), "messages.bolt"),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { repo.Close() })
+		handler := commands.NewCreateMessagesBatchCommandHandler(repo, clock.Fixed(now))
+
+		cmd := &dtos.CreateMessagesBatchCommand{Items: []*dtos.CreateMessageCommand{{Text: "first"}, {Text: "second"}}}
+
+		// Act
+		result, err := handler.Handle(t.Context(), cmd)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Created)
+		assert.Empty(t, recorder.events)
+		pending, err := repo.PendingEvents(t.Context(), now, 10)
+		require.NoError(t, err)
+		assert.Len(t, pending, 2)
+	})
+
+	t.Run("should create nothing in all-or-nothing mode when an item is invalid", func(t *testing.T) {
+		// Arrange
+		recorder := recordEvents(t)
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewCreateMessagesBatchCommandHandler(mockRepo, clock.Fixed(now))
+
+		cmd := &dtos.CreateMessagesBatchCommand{Items: []*dtos.CreateMessageCommand{{Text: "valid"}, {Text: ""}, nil}}
+
+		// Act
+		result, err := handler.Handle(t.Context(), cmd)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 0, result.Created)
+		assert.Equal(t, 3, result.Failed)
+		assert.Equal(t, dtos.BatchItemAborted, result.Results[0].Status)
+		assert.Equal(t, dtos.BatchItemInvalid, result.Results[1].Status)
+		assert.Equal(t, "text", result.Results[1].Errors[0].Field)
+		assert.Equal(t, dtos.BatchItemInvalid, result.Results[2].Status)
+		assert.Empty(t, recorder.events)
+		mockRepo.AssertNotCalled(t, "CreateMessages", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should create the valid items in best-effort mode", func(t *testing.T) {
+		// Arrange
+		recordEvents(t)
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewCreateMessagesBatchCommandHandler(mockRepo, clock.Fixed(now))
+
+		id := uuid.New()
+		mockRepo.On("CreateMessages", mock.Anything, mock.MatchedBy(func(messages []*models.Message) bool {
+			return len(messages) == 1 && messages[0].Text == "valid"
+		})).Return([]*models.Message{{ID: id, Text: "valid"}}, nil)
+
+		cmd := &dtos.CreateMessagesBatchCommand{
+			Mode:  dtos.BatchModeBestEffort,
+			Items: []*dtos.CreateMessageCommand{{Text: ""}, {Text: "valid"}},
+		}
+
+		// Act
+		result, err := handler.Handle(t.Context(), cmd)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, dtos.BatchItemInvalid, result.Results[0].Status)
+		assert.Equal(t, dtos.BatchItemCreated, result.Results[1].Status)
+		assert.Equal(t, id, *result.Results[1].ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should reject an unknown mode and empty or oversized batches", func(t *testing.T) {
+		// Arrange
+		handler := commands.NewCreateMessagesBatchCommandHandler(new(MockMessageRepository), clock.Fixed(now))
+		oversized := make([]*dtos.CreateMessageCommand, dtos.MaxCreateBatchSize+1)
+
+		for _, cmd := range []*dtos.CreateMessagesBatchCommand{
+			{Mode: "sometimes", Items: []*dtos.CreateMessageCommand{{Text: "hello"}}},
+			{},
+			{Items: oversized},
+		} {
+			// Act
+			_, err := handler.Handle(t.Context(), cmd)
+
+			// Assert
+			require.ErrorIs(t, err, models.ErrInvalidBatch)
+		}
+	})
+
+	t.Run("should return the repository error", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewCreateMessagesBatchCommandHandler(mockRepo, clock.Fixed(now))
+
+		mockRepo.On("CreateMessages", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		// Act
+		result, err := handler.Handle(t.Context(), &dtos.CreateMessagesBatchCommand{
+			Items: []*dtos.CreateMessageCommand{{Text: "hello"}},
+		})
+
+		// Assert
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// DeleteMessageCommandHandler is the handler for DeleteMessageCommand.
+type DeleteMessageCommandHandler struct {
+	repo  repository.IMessageRepository
+	clock clock.Clock
+}
+
+// NewDeleteMessageCommandHandler creates a new DeleteMessageCommandHandler.
+func NewDeleteMessageCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+) interfaces.IDeleteMessageCommandHandler {
+	return &DeleteMessageCommandHandler{repo: repo, clock: clock}
+}
+
+// Handle handles the DeleteMessageCommand. The message is soft deleted: it can be
+// restored until it is purged.
+func (h *DeleteMessageCommandHandler) Handle(
+	ctx context.Context,
+	cmd *dtos.DeleteMessageCommand,
+) (*dtos.DeleteMessageCommandResponse, error) {
+	current, err := h.repo.GetMessageByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := AuthorizeMutation(ctx, current); err != nil {
+		return nil, err
+	}
+	if current.IsDeleted() {
+		return nil, fmt.Errorf("%w: message with ID %s is already deleted", models.ErrMessageNotFound, cmd.ID)
+	}
+	if cmd.ExpectedVersion != 0 && cmd.ExpectedVersion != current.Version {
+		return nil, fmt.Errorf("%w: message %s is at version %d, not %d",
+			models.ErrPreconditionFailed, cmd.ID, current.Version, cmd.ExpectedVersion)
+	}
+
+	deleted := *current
+	deleted.DeletedAt = h.clock.Now()
+	deleted.UpdatedAt = deleted.DeletedAt
+
+	message, err := h.repo.UpdateMessage(ctx, &deleted, current.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.DeleteMessageCommandResponse{
+		ID:        message.ID,
+		DeletedAt: message.DeletedAt,
+		Version:   message.Version,
+	}, nil
+}
+
+// registerDeleteMessageCommandHandler registers the command handler with MediatR.
+func registerDeleteMessageCommandHandler(handler interfaces.IDeleteMessageCommandHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.DeleteMessageCommand, *dtos.DeleteMessageCommandResponse](handler)
+}
@@ -0,0 +1,131 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/commands"
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteMessageCommandHandler_Handle(t *testing.T) {
+	author := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+
+	t.Run("should soft delete the message", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewDeleteMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := &models.Message{ID: uuid.New(), AuthorID: "user-1", Version: 2}
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+		mockRepo.On("UpdateMessage", author, mock.MatchedBy(func(msg *models.Message) bool {
+			return msg.DeletedAt.Equal(now)
+		}), int64(2)).Return(&models.Message{ID: stored.ID, DeletedAt: now, Version: 3}, nil)
+
+		// Act
+		result, err := handler.Handle(author, &dtos.DeleteMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, now, result.DeletedAt)
+		assert.Equal(t, int64(3), result.Version)
+		assert.False(t, stored.IsDeleted())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should not find a message that is already deleted", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewDeleteMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := &models.Message{ID: uuid.New(), AuthorID: "user-1", Version: 2, DeletedAt: now}
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+
+		// Act
+		_, err := handler.Handle(author, &dtos.DeleteMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+	})
+
+	t.Run("should forbid deleting the message of another author", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewDeleteMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := &models.Message{ID: uuid.New(), AuthorID: "user-2", Version: 2}
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+
+		// Act
+		_, err := handler.Handle(author, &dtos.DeleteMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrForbidden)
+	})
+
+	t.Run("should forbid deleting a deleted message of another author", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewDeleteMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := &models.Message{ID: uuid.New(), AuthorID: "user-2", Version: 2, DeletedAt: now}
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+
+		// Act
+		_, err := handler.Handle(author, &dtos.DeleteMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrForbidden)
+		require.NotErrorIs(t, err, models.ErrMessageNotFound)
+	})
+}
+
+func TestRestoreMessageCommandHandler_Handle(t *testing.T) {
+	author := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+
+	t.Run("should clear the deletion of the message", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		later := now.Add(time.Hour)
+		handler := commands.NewRestoreMessageCommandHandler(mockRepo, clock.Fixed(later))
+		stored := &models.Message{ID: uuid.New(), AuthorID: "user-1", Version: 3, DeletedAt: now}
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+		mockRepo.On("UpdateMessage", author, mock.MatchedBy(func(msg *models.Message) bool {
+			return !msg.IsDeleted() && msg.UpdatedAt.Equal(later)
+		}), int64(3)).Return(&models.Message{ID: stored.ID, Version: 4}, nil)
+
+		// Act
+		result, err := handler.Handle(author, &dtos.RestoreMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), result.Version)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should leave a message that is not deleted unchanged", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewRestoreMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := &models.Message{ID: uuid.New(), AuthorID: "user-1", Version: 3}
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+
+		// Act
+		result, err := handler.Handle(author, &dtos.RestoreMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), result.Version)
+		mockRepo.AssertNotCalled(t, "UpdateMessage", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/auth"
+)
+
+// AuthorizeMutation checks that the caller may modify message: only its author can,
+// unless the caller holds the messages admin scope. Anonymous callers, who only reach
+// the handlers when the authentication is optional, may modify the messages created
+// anonymously. It returns errors wrapping auth.ErrUnauthenticated or auth.ErrForbidden.
+func AuthorizeMutation(ctx context.Context, message *models.Message) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		if message.AuthorID == "" {
+			return nil
+		}
+		return fmt.Errorf("%w: message %s can only be modified by its author", auth.ErrUnauthenticated, message.ID)
+	}
+	if principal.HasScope(dtos.ScopeMessagesAdmin) || message.IsAuthoredBy(principal.Subject) {
+		return nil
+	}
+	return fmt.Errorf("%w: message %s belongs to another author", auth.ErrForbidden, message.ID)
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+)
+
+// publishMessagesCreated publishes a MessageCreatedEvent for each of the messages,
+// unless repo recorded them in its outbox, from which the relay publishes them.
+// Failures are logged: the messages are created anyway.
+func publishMessagesCreated(ctx context.Context, repo repository.IMessageRepository, messages ...*models.Message) {
+	if _, ok := repo.(repository.IOutboxRepository); ok {
+		return
+	}
+	for _, message := range messages {
+		if err := events.NewMessageCreatedEvent(message).Publish(ctx); err != nil {
+			slog.ErrorContext(ctx, "failed to publish message created event",
+				slog.String("message_id", message.ID.String()),
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// RebuildProjectionsCommandHandler is the handler for RebuildProjectionsCommand.
+type RebuildProjectionsCommandHandler struct {
+	repo  repository.IMessageRepository
+	clock clock.Clock
+}
+
+// NewRebuildProjectionsCommandHandler creates a new RebuildProjectionsCommandHandler.
+func NewRebuildProjectionsCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+) interfaces.IRebuildProjectionsCommandHandler {
+	return &RebuildProjectionsCommandHandler{repo: repo, clock: clock}
+}
+
+// Handle handles the RebuildProjectionsCommand. It fails with
+// models.ErrProjectionsUnsupported when the store is not event sourced.
+func (h *RebuildProjectionsCommandHandler) Handle(
+	ctx context.Context,
+	_ *dtos.RebuildProjectionsCommand,
+) (*dtos.RebuildProjectionsCommandResponse, error) {
+	projections, ok := h.repo.(repository.IProjectionRepository)
+	if !ok {
+		return nil, models.ErrProjectionsUnsupported
+	}
+
+	stats, err := projections.RebuildProjections(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.RebuildProjectionsCommandResponse{
+		RebuiltAt: h.clock.Now(),
+		Streams:   stats.Streams,
+		Events:    stats.Events,
+	}, nil
+}
+
+// registerRebuildProjectionsCommandHandler registers the command handler with MediatR.
+func registerRebuildProjectionsCommandHandler(handler interfaces.IRebuildProjectionsCommandHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.RebuildProjectionsCommand, *dtos.RebuildProjectionsCommandResponse](
+		handler,
+	)
+}
@@ -0,0 +1,56 @@
+package commands_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/commands"
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebuildProjectionsCommandHandler_Handle(t *testing.T) {
+	t.Run("should rebuild the projections of the event-sourced store", func(t *testing.T) {
+		// Arrange
+		repo, err := repository.NewEventSourcedMessageRepository(repository.EventSourcedConfig{
+			Path:        filepath.Join(t.TempDir(), "events.bolt"),
+			OpenTimeout: time.Second,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { repo.Close() })
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "hello"})
+		require.NoError(t, err)
+		created.Text = "edited"
+		_, err = repo.UpdateMessage(t.Context(), created, 1)
+		require.NoError(t, err)
+
+		handler := commands.NewRebuildProjectionsCommandHandler(repo, clock.Fixed(now))
+
+		// Act
+		result, err := handler.Handle(t.Context(), &dtos.RebuildProjectionsCommand{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &dtos.RebuildProjectionsCommandResponse{RebuiltAt: now, Streams: 1, Events: 2}, result)
+	})
+
+	t.Run("should fail when the store is not event sourced", func(t *testing.T) {
+		// Arrange
+		handler := commands.NewRebuildProjectionsCommandHandler(
+			repository.NewInMemoryMessageRepository(),
+			clock.Fixed(now),
+		)
+
+		// Act
+		result, err := handler.Handle(t.Context(), &dtos.RebuildProjectionsCommand{})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrProjectionsUnsupported)
+		assert.Nil(t, result)
+	})
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// RestoreMessageCommandHandler is the handler for RestoreMessageCommand.
+type RestoreMessageCommandHandler struct {
+	repo  repository.IMessageRepository
+	clock clock.Clock
+}
+
+// NewRestoreMessageCommandHandler creates a new RestoreMessageCommandHandler.
+func NewRestoreMessageCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+) interfaces.IRestoreMessageCommandHandler {
+	return &RestoreMessageCommandHandler{repo: repo, clock: clock}
+}
+
+// Handle handles the RestoreMessageCommand. Restoring a message that is not deleted
+// leaves it unchanged.
+func (h *RestoreMessageCommandHandler) Handle(
+	ctx context.Context,
+	cmd *dtos.RestoreMessageCommand,
+) (*dtos.RestoreMessageCommandResponse, error) {
+	current, err := h.repo.GetMessageByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := AuthorizeMutation(ctx, current); err != nil {
+		return nil, err
+	}
+
+	message := current
+	if current.IsDeleted() {
+		restored := *current
+		restored.DeletedAt = time.Time{}
+		restored.UpdatedAt = h.clock.Now()
+
+		message, err = h.repo.UpdateMessage(ctx, &restored, current.Version)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &dtos.RestoreMessageCommandResponse{
+		ID:        message.ID,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		Version:   message.Version,
+	}, nil
+}
+
+// registerRestoreMessageCommandHandler registers the command handler with MediatR.
+func registerRestoreMessageCommandHandler(handler interfaces.IRestoreMessageCommandHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.RestoreMessageCommand, *dtos.RestoreMessageCommandResponse](handler)
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// UpdateMessageCommandHandler is the handler for UpdateMessageCommand.
+type UpdateMessageCommandHandler struct {
+	repo  repository.IMessageRepository
+	clock clock.Clock
+}
+
+// NewUpdateMessageCommandHandler creates a new UpdateMessageCommandHandler.
+func NewUpdateMessageCommandHandler(
+	repo repository.IMessageRepository,
+	clock clock.Clock,
+) interfaces.IUpdateMessageCommandHandler {
+	return &UpdateMessageCommandHandler{repo: repo, clock: clock}
+}
+
+// Handle handles the UpdateMessageCommand. It fails with models.ErrPreconditionFailed
+// when the message is not at the expected version, and with models.ErrVersionConflict
+// when it changed while being updated.
+func (h *UpdateMessageCommandHandler) Handle(
+	ctx context.Context,
+	cmd *dtos.UpdateMessageCommand,
+) (*dtos.UpdateMessageCommandResponse, error) {
+	current, err := h.repo.GetMessageByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := AuthorizeMutation(ctx, current); err != nil {
+		return nil, err
+	}
+	if current.IsDeleted() {
+		return nil, fmt.Errorf("%w: message with ID %s is deleted", models.ErrMessageNotFound, cmd.ID)
+	}
+	if cmd.ExpectedVersion != 0 && cmd.ExpectedVersion != current.Version {
+		return nil, fmt.Errorf("%w: message %s is at version %d, not %d",
+			models.ErrPreconditionFailed, cmd.ID, current.Version, cmd.ExpectedVersion)
+	}
+
+	updated := *current
+	if cmd.Text != nil {
+		updated.Text = *cmd.Text
+	}
+	updated.UpdatedAt = h.clock.Now()
+
+	message, err := h.repo.UpdateMessage(ctx, &updated, current.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.UpdateMessageCommandResponse{
+		ID:        message.ID,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		Version:   message.Version,
+	}, nil
+}
+
+// registerUpdateMessageCommandHandler registers the command handler with MediatR.
+func registerUpdateMessageCommandHandler(handler interfaces.IUpdateMessageCommandHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.UpdateMessageCommand, *dtos.UpdateMessageCommandResponse](handler)
+}
@@ -0,0 +1,138 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/commands"
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMessageCommandHandler_Handle(t *testing.T) {
+	author := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+	text := "updated"
+
+	newStored := func() *models.Message {
+		return &models.Message{ID: uuid.New(), Text: "original", AuthorID: "user-1", Version: 3, CreatedAt: now}
+	}
+
+	t.Run("should update the message at its current version", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		later := now.Add(time.Hour)
+		handler := commands.NewUpdateMessageCommandHandler(mockRepo, clock.Fixed(later))
+		stored := newStored()
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+		mockRepo.On("UpdateMessage", author, mock.MatchedBy(func(msg *models.Message) bool {
+			return msg.Text == text && msg.UpdatedAt.Equal(later) && msg.CreatedAt.Equal(now)
+		}), int64(3)).Return(&models.Message{ID: stored.ID, Text: text, Version: 4, UpdatedAt: later}, nil)
+
+		// Act
+		result, err := handler.Handle(
+			author,
+			&dtos.UpdateMessageCommand{ID: stored.ID, Text: &text, ExpectedVersion: 3},
+		)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), result.Version)
+		assert.Equal(t, text, result.Text)
+		assert.Equal(t, "original", stored.Text)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should keep the fields missing from the command", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewUpdateMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := newStored()
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+		mockRepo.On("UpdateMessage", author, mock.MatchedBy(func(msg *models.Message) bool {
+			return msg.Text == "original"
+		}), int64(3)).Return(&models.Message{ID: stored.ID, Text: "original", Version: 4}, nil)
+
+		// Act
+		_, err := handler.Handle(author, &dtos.UpdateMessageCommand{ID: stored.ID})
+
+		// Assert
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should fail the precondition when the message is at another version", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewUpdateMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := newStored()
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+
+		// Act
+		_, err := handler.Handle(author, &dtos.UpdateMessageCommand{ID: stored.ID, Text: &text, ExpectedVersion: 2})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrPreconditionFailed)
+		mockRepo.AssertNotCalled(t, "UpdateMessage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should forbid updating the message of another author", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewUpdateMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := newStored()
+		other := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-2"})
+
+		mockRepo.On("GetMessageByID", other, stored.ID).Return(stored, nil)
+
+		// Act
+		_, err := handler.Handle(other, &dtos.UpdateMessageCommand{ID: stored.ID, Text: &text})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrForbidden)
+		mockRepo.AssertNotCalled(t, "UpdateMessage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should forbid updating a deleted message of another author", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewUpdateMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := newStored()
+		stored.DeletedAt = now
+		other := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-2"})
+
+		mockRepo.On("GetMessageByID", other, stored.ID).Return(stored, nil)
+
+		// Act
+		_, err := handler.Handle(other, &dtos.UpdateMessageCommand{ID: stored.ID, Text: &text})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrForbidden)
+		require.NotErrorIs(t, err, models.ErrMessageNotFound)
+	})
+
+	t.Run("should return the conflict of a concurrent update", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := commands.NewUpdateMessageCommandHandler(mockRepo, clock.Fixed(now))
+		stored := newStored()
+
+		mockRepo.On("GetMessageByID", author, stored.ID).Return(stored, nil)
+		mockRepo.On("UpdateMessage", author, mock.Anything, int64(3)).Return(nil, models.ErrVersionConflict)
+
+		// Act
+		_, err := handler.Handle(author, &dtos.UpdateMessageCommand{ID: stored.ID, Text: &text})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrVersionConflict)
+	})
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"strconv"
+	"strings"
+)
+
+// etag returns the strong entity tag of a message version.
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch returns the version required by an If-Match header: 0 for "*" or an
+// absent header, which match any version. It returns false when the header names no
+// version of a message.
+func parseIfMatch(header string) (int64, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	// Only a single tag can match the one version a message is at.
+	tag, isStrong := strings.CutPrefix(header, `"`)
+	tag, isQuoted := strings.CutSuffix(tag, `"`)
+	if !isStrong || !isQuoted {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
+// matchesIfNoneMatch reports whether the entity tag of version is listed in an
+// If-None-Match header. Weak comparison is used, as RFC 9110 requires.
+func matchesIfNoneMatch(header string, version int64) bool {
+	current := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,27 @@
 package http
 
 import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
 	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/recovery"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"go.uber.org/fx"
 )
 
+// mergePatchContentType is the media type of JSON Merge Patch documents.
+const mergePatchContentType = "application/merge-patch+json"
+
 // Module exports the HTTP handlers functionality.
 var Module = fx.Options(
 	fx.Provide(NewMessageHandlers),
@@ -28,10 +41,20 @@ func NewMessageHandlers(service interfaces.IMessageService) *MessageHandlers {
 // RegisterRoutes registers the message routes to the Fiber app.
 func RegisterRoutes(app *fiber.App, handlers *MessageHandlers) {
 	app.Post("/messages", handlers.CreateMessage)
+	app.Post("/messages\\:batch", handlers.CreateMessagesBatch)
+	app.Get("/messages", handlers.ListMessages)
 	app.Get("/messages/:id", handlers.GetMessageByID)
+	app.Put("/messages/:id", handlers.ReplaceMessage)
+	app.Patch("/messages/:id", handlers.PatchMessage)
+	app.Delete("/messages/:id", handlers.DeleteMessage)
+	app.Post("/messages/:id\\:restore", handlers.RestoreMessage)
+	app.Post("/admin/messages/backup", auth.RequireScopes(dtos.ScopeMessagesAdmin), handlers.BackupMessages)
+	app.Post("/admin/messages/projections/rebuild",
+		auth.RequireScopes(dtos.ScopeMessagesAdmin), handlers.RebuildProjections)
 }
 
-// CreateMessage handles the creation of a new message.
+// CreateMessage handles the creation of a new message. It answers 422 with the errors
+// of the fields of an invalid message.
 func (h *MessageHandlers) CreateMessage(c *fiber.Ctx) error {
 	cmd := new(dtos.CreateMessageCommand)
 	if err := c.BodyParser(cmd); err != nil {
@@ -40,16 +63,50 @@ func (h *MessageHandlers) CreateMessage(c *fiber.Ctx) error {
 		})
 	}
 
-	result, err := h.service.CreateMessage(c.Context(), cmd)
+	result, err := h.service.CreateMessage(c.UserContext(), cmd)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return writeError(c, err, fiber.StatusInternalServerError)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(result)
 }
 
+// RebuildProjections handles rebuilding the projections of the event-sourced store.
+func (h *MessageHandlers) RebuildProjections(c *fiber.Ctx) error {
+	result, err := h.service.RebuildProjections(c.UserContext(), &dtos.RebuildProjectionsCommand{})
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// CreateMessagesBatch handles the creation of several messages at once. It answers
+// 201 when every item was created, 422 when none was and 207 otherwise, with the
+// result of each item.
+func (h *MessageHandlers) CreateMessagesBatch(c *fiber.Ctx) error {
+	cmd := new(dtos.CreateMessagesBatchCommand)
+	if err := c.BodyParser(cmd); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "cannot parse request body",
+		})
+	}
+
+	result, err := h.service.CreateMessagesBatch(c.UserContext(), cmd)
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	status := fiber.StatusMultiStatus
+	switch {
+	case result.Failed == 0:
+		status = fiber.StatusCreated
+	case result.Created == 0:
+		status = fiber.StatusUnprocessableEntity
+	}
+	return c.Status(status).JSON(result)
+}
+
 // GetMessageByID handles retrieving a message by its ID.
 func (h *MessageHandlers) GetMessageByID(c *fiber.Ctx) error {
 	idParam := c.Params("id")
@@ -60,14 +117,253 @@ func (h *MessageHandlers) GetMessageByID(c *fiber.Ctx) error {
 		})
 	}
 
-	query := &dtos.GetMessageByIDQuery{ID: id}
+	query := &dtos.GetMessageByIDQuery{ID: id, IncludeDeleted: c.QueryBool("include_deleted")}
+
+	result, err := h.service.GetMessageByID(c.UserContext(), query)
+	if err != nil {
+		return writeError(c, err, fiber.StatusNotFound)
+	}
+
+	c.Set(fiber.HeaderETag, etag(result.Version))
+	if matchesIfNoneMatch(c.Get(fiber.HeaderIfNoneMatch), result.Version) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// ListMessages handles listing the messages a page at a time, or retrieving a batch
+// of messages when the ids parameter is given.
+func (h *MessageHandlers) ListMessages(c *fiber.Ctx) error {
+	if c.Query("ids") != "" {
+		return h.getMessagesByIDs(c)
+	}
+
+	query := &dtos.ListMessagesQuery{
+		Cursor:         c.Query("cursor"),
+		Sort:           c.Query("sort"),
+		AuthorID:       c.Query("author"),
+		IncludeDeleted: c.QueryBool("include_deleted"),
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid limit",
+			})
+		}
+		query.Limit = limit
+	}
+
+	for param, target := range map[string]*time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+	} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "invalid " + param + ", expected an RFC 3339 time",
+				})
+			}
+			*target = t
+		}
+	}
+
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			query.Tags = append(query.Tags, tag)
+		}
+	}
+
+	result, err := h.service.ListMessages(c.UserContext(), query)
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+func (h *MessageHandlers) getMessagesByIDs(c *fiber.Ctx) error {
+	query := &dtos.GetMessagesByIDsQuery{IncludeDeleted: c.QueryBool("include_deleted")}
+
+	for _, value := range strings.Split(c.Query("ids"), ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid UUID format",
+			})
+		}
+		query.IDs = append(query.IDs, id)
+	}
+
+	result, err := h.service.GetMessagesByIDs(c.UserContext(), query)
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// ReplaceMessage handles the full replacement of a message.
+func (h *MessageHandlers) ReplaceMessage(c *fiber.Ctx) error {
+	var body struct {
+		Text *string `json:"text"`
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "cannot parse request body",
+		})
+	}
+	if body.Text == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "text is required",
+		})
+	}
+
+	return h.updateMessage(c, body.Text)
+}
+
+// PatchMessage handles a JSON Merge Patch (RFC 7386) of a message.
+func (h *MessageHandlers) PatchMessage(c *fiber.Ctx) error {
+	if !c.Is("json") && !strings.HasPrefix(c.Get(fiber.HeaderContentType), mergePatchContentType) {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": "expected " + mergePatchContentType,
+		})
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &patch); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "cannot parse request body",
+		})
+	}
+
+	var text *string
+	if raw, ok := patch["text"]; ok {
+		if err := json.Unmarshal(raw, &text); err != nil || text == nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "text must be a string",
+			})
+		}
+	}
+
+	return h.updateMessage(c, text)
+}
+
+// DeleteMessage handles the soft deletion of a message.
+func (h *MessageHandlers) DeleteMessage(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid UUID format",
+		})
+	}
+
+	version, ok := parseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if !ok {
+		return problem.Write(c, fiber.StatusPreconditionFailed, "If-Match does not match the message version")
+	}
 
-	result, err := h.service.GetMessageByID(c.Context(), query)
+	cmd := &dtos.DeleteMessageCommand{ID: id, ExpectedVersion: version}
+
+	if _, err := h.service.DeleteMessage(c.UserContext(), cmd); err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreMessage handles the restoration of a soft deleted message.
+func (h *MessageHandlers) RestoreMessage(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": err.Error(),
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid UUID format",
 		})
 	}
 
+	cmd := &dtos.RestoreMessageCommand{ID: id}
+
+	result, err := h.service.RestoreMessage(c.UserContext(), cmd)
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderETag, etag(result.Version))
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// BackupMessages handles writing a backup of the message store on the server.
+func (h *MessageHandlers) BackupMessages(c *fiber.Ctx) error {
+	result, err := h.service.BackupMessages(c.UserContext(), &dtos.BackupMessagesCommand{})
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+func (h *MessageHandlers) updateMessage(c *fiber.Ctx, text *string) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid UUID format",
+		})
+	}
+
+	version, ok := parseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if !ok {
+		return problem.Write(c, fiber.StatusPreconditionFailed, "If-Match does not match the message version")
+	}
+
+	cmd := &dtos.UpdateMessageCommand{ID: id, Text: text, ExpectedVersion: version}
+
+	result, err := h.service.UpdateMessage(c.UserContext(), cmd)
+	if err != nil {
+		return writeError(c, err, fiber.StatusInternalServerError)
+	}
+
+	c.Set(fiber.HeaderETag, etag(result.Version))
 	return c.Status(fiber.StatusOK).JSON(result)
 }
+
+// writeError answers with status and the error message, unless err is a failure that
+// is not specific to the route, which is mapped to a problem details response.
+func writeError(c *fiber.Ctx, err error, status int) error {
+	var (
+		panicErr      *recovery.PanicError
+		validationErr *dtos.ValidationError
+	)
+	switch {
+	case errors.As(err, &panicErr):
+		return problem.Write(c, fiber.StatusInternalServerError, "internal server error")
+	case errors.As(err, &validationErr):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":  err.Error(),
+			"errors": validationErr.Errors,
+		})
+	case errors.Is(err, auth.ErrUnauthenticated), errors.Is(err, auth.ErrForbidden):
+		return auth.WriteError(c, err)
+	case errors.Is(err, models.ErrPreconditionFailed):
+		return problem.Write(c, fiber.StatusPreconditionFailed, "If-Match does not match the message version")
+	case errors.Is(err, models.ErrVersionConflict):
+		return problem.Write(c, fiber.StatusConflict, "the message was modified concurrently")
+	case errors.Is(err, models.ErrMessageAlreadyExists):
+		return problem.Write(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, models.ErrBackupUnsupported), errors.Is(err, models.ErrProjectionsUnsupported):
+		return problem.Write(c, fiber.StatusNotImplemented, err.Error())
+	case errors.Is(err, models.ErrMessageNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, models.ErrInvalidQuery), errors.Is(err, models.ErrInvalidBatch):
+		status = fiber.StatusBadRequest
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
@@ -5,12 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	http2 "net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/delivery/http"
 	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/auth"
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/recovery"
+	"github.com/arielsrv/fxf/pkg/requestid"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +42,17 @@ func (m *MockMessageService) CreateMessage(
 	return args.Get(0).(*dtos.CreateMessageCommandResponse), args.Error(1)
 }
 
+func (m *MockMessageService) CreateMessagesBatch(
+	ctx context.Context,
+	cmd *dtos.CreateMessagesBatchCommand,
+) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.CreateMessagesBatchCommandResponse), args.Error(1)
+}
+
 func (m *MockMessageService) GetMessageByID(
 	ctx context.Context,
 	query *dtos.GetMessageByIDQuery,
@@ -45,6 +64,83 @@ func (m *MockMessageService) GetMessageByID(
 	return args.Get(0).(*dtos.GetMessageByIDQueryResponse), args.Error(1)
 }
 
+func (m *MockMessageService) GetMessagesByIDs(
+	ctx context.Context,
+	query *dtos.GetMessagesByIDsQuery,
+) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.GetMessagesByIDsQueryResponse), args.Error(1)
+}
+
+func (m *MockMessageService) ListMessages(
+	ctx context.Context,
+	query *dtos.ListMessagesQuery,
+) (*dtos.ListMessagesQueryResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.ListMessagesQueryResponse), args.Error(1)
+}
+
+func (m *MockMessageService) UpdateMessage(
+	ctx context.Context,
+	cmd *dtos.UpdateMessageCommand,
+) (*dtos.UpdateMessageCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.UpdateMessageCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) DeleteMessage(
+	ctx context.Context,
+	cmd *dtos.DeleteMessageCommand,
+) (*dtos.DeleteMessageCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.DeleteMessageCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) BackupMessages(
+	ctx context.Context,
+	cmd *dtos.BackupMessagesCommand,
+) (*dtos.BackupMessagesCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.BackupMessagesCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) RebuildProjections(
+	ctx context.Context,
+	cmd *dtos.RebuildProjectionsCommand,
+) (*dtos.RebuildProjectionsCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.RebuildProjectionsCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) RestoreMessage(
+	ctx context.Context,
+	cmd *dtos.RestoreMessageCommand,
+) (*dtos.RestoreMessageCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.RestoreMessageCommandResponse), args.Error(1)
+}
+
 func TestMessageHandlers_CreateMessage(t *testing.T) {
 	t.Run("should create message successfully", func(t *testing.T) {
 		// Arrange
@@ -60,8 +156,7 @@ func TestMessageHandlers_CreateMessage(t *testing.T) {
 			ID: uuid.New(),
 		}
 
-		// Use mock.AnythingOfType for context since Fiber passes its own context type
-		mockService.On("CreateMessage", mock.AnythingOfType("*fasthttp.RequestCtx"), createMessageCmd).
+		mockService.On("CreateMessage", mock.Anything, createMessageCmd).
 			Return(expectedResponse, nil)
 
 		http.RegisterRoutes(app, handlers)
@@ -109,6 +204,39 @@ func TestMessageHandlers_CreateMessage(t *testing.T) {
 		assert.Equal(t, "cannot parse request body", responseBody["error"])
 	})
 
+	t.Run("should return unprocessable entity with the field errors of an invalid message", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		handlers := http.NewMessageHandlers(mockService)
+
+		createMessageCmd := &dtos.CreateMessageCommand{Text: ""}
+		mockService.On("CreateMessage", mock.Anything, createMessageCmd).
+			Return(nil, &dtos.ValidationError{Errors: []dtos.FieldError{{Field: "text", Message: "text is required"}}})
+
+		http.RegisterRoutes(app, handlers)
+
+		body, _ := json.Marshal(createMessageCmd)
+		req := httptest.NewRequest(http2.MethodPost, "/messages", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+		var responseBody struct {
+			Errors []dtos.FieldError `json:"errors"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&responseBody)
+		require.NoError(t, err)
+		assert.Equal(t, []dtos.FieldError{{Field: "text", Message: "text is required"}}, responseBody.Errors)
+
+		mockService.AssertExpectations(t)
+	})
+
 	t.Run("should return internal server error when service fails", func(t *testing.T) {
 		// Arrange
 		app := fiber.New()
@@ -120,7 +248,7 @@ func TestMessageHandlers_CreateMessage(t *testing.T) {
 		}
 
 		expectedError := errors.New("service error")
-		mockService.On("CreateMessage", mock.AnythingOfType("*fasthttp.RequestCtx"), createMessageCmd).
+		mockService.On("CreateMessage", mock.Anything, createMessageCmd).
 			Return(nil, expectedError)
 
 		http.RegisterRoutes(app, handlers)
@@ -145,6 +273,38 @@ func TestMessageHandlers_CreateMessage(t *testing.T) {
 	})
 }
 
+func TestMessageHandlers_RequestID(t *testing.T) {
+	t.Run("should pass the request ID to the service", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Use(fiberpkg.RequestID())
+		mockService := new(MockMessageService)
+		handlers := http.NewMessageHandlers(mockService)
+
+		messageID := uuid.New()
+		query := &dtos.GetMessageByIDQuery{ID: messageID}
+
+		mockService.On("GetMessageByID", mock.MatchedBy(func(ctx context.Context) bool {
+			id, ok := requestid.FromContext(ctx)
+			return ok && id == "req-42"
+		}), query).Return(&dtos.GetMessageByIDQueryResponse{ID: messageID}, nil)
+
+		http.RegisterRoutes(app, handlers)
+
+		req := httptest.NewRequest(http2.MethodGet, "/messages/"+messageID.String(), nil)
+		req.Header.Set(requestid.Header, "req-42")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "req-42", resp.Header.Get(requestid.Header))
+		mockService.AssertExpectations(t)
+	})
+}
+
 func TestMessageHandlers_GetMessageByID(t *testing.T) {
 	t.Run("should get message successfully", func(t *testing.T) {
 		// Arrange
@@ -162,7 +322,7 @@ func TestMessageHandlers_GetMessageByID(t *testing.T) {
 			Text: "test message",
 		}
 
-		mockService.On("GetMessageByID", mock.AnythingOfType("*fasthttp.RequestCtx"), query).
+		mockService.On("GetMessageByID", mock.Anything, query).
 			Return(expectedResponse, nil)
 
 		http.RegisterRoutes(app, handlers)
@@ -220,7 +380,7 @@ func TestMessageHandlers_GetMessageByID(t *testing.T) {
 		}
 
 		expectedError := errors.New("message not found")
-		mockService.On("GetMessageByID", mock.AnythingOfType("*fasthttp.RequestCtx"), query).Return(nil, expectedError)
+		mockService.On("GetMessageByID", mock.Anything, query).Return(nil, expectedError)
 
 		http.RegisterRoutes(app, handlers)
 
@@ -242,6 +402,94 @@ func TestMessageHandlers_GetMessageByID(t *testing.T) {
 	})
 }
 
+func TestMessageHandlers_Panic(t *testing.T) {
+	t.Run("should return a problem response when a handler panicked", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		handlers := http.NewMessageHandlers(mockService)
+
+		createMessageCmd := &dtos.CreateMessageCommand{Text: "test message"}
+		mockService.On("CreateMessage", mock.Anything, createMessageCmd).
+			Return(nil, fmt.Errorf("pipeline error: %w", &recovery.PanicError{Value: "boom"}))
+
+		http.RegisterRoutes(app, handlers)
+
+		body, _ := json.Marshal(createMessageCmd)
+		req := httptest.NewRequest(http2.MethodPost, "/messages", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+
+		var responseBody problem.Problem
+		err = json.NewDecoder(resp.Body).Decode(&responseBody)
+		require.NoError(t, err)
+		assert.NotContains(t, responseBody.Detail, "boom")
+
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestMessageHandlers_Authorization(t *testing.T) {
+	t.Run("should map a missing scope to a forbidden problem response", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		handlers := http.NewMessageHandlers(mockService)
+
+		createMessageCmd := &dtos.CreateMessageCommand{Text: "test message"}
+		mockService.On("CreateMessage", mock.Anything, createMessageCmd).
+			Return(nil, fmt.Errorf("pipeline error: %w", auth.ErrForbidden))
+
+		http.RegisterRoutes(app, handlers)
+
+		body, _ := json.Marshal(createMessageCmd)
+		req := httptest.NewRequest(http2.MethodPost, "/messages", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should map an anonymous caller to an unauthorized problem response", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		handlers := http.NewMessageHandlers(mockService)
+
+		messageID := uuid.New()
+		mockService.On("GetMessageByID", mock.Anything, &dtos.GetMessageByIDQuery{ID: messageID}).
+			Return(nil, auth.ErrUnauthenticated)
+
+		http.RegisterRoutes(app, handlers)
+
+		req := httptest.NewRequest(http2.MethodGet, "/messages/"+messageID.String(), nil)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "Bearer", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+
+		mockService.AssertExpectations(t)
+	})
+}
+
 func TestNewMessageHandlers(t *testing.T) {
 	t.Run("should create handlers with service", func(t *testing.T) {
 		// Arrange
@@ -255,3 +503,604 @@ func TestNewMessageHandlers(t *testing.T) {
 		assert.IsType(t, &http.MessageHandlers{}, handlers)
 	})
 }
+
+func TestMessageHandlers_ETag(t *testing.T) {
+	t.Run("should emit the ETag of the message version", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		messageID := uuid.New()
+		mockService.On("GetMessageByID", mock.Anything, &dtos.GetMessageByIDQuery{ID: messageID}).
+			Return(&dtos.GetMessageByIDQueryResponse{ID: messageID, Version: 3}, nil)
+
+		// Act
+		resp, err := app.Test(httptest.NewRequest(http2.MethodGet, "/messages/"+messageID.String(), nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"3"`, resp.Header.Get(fiber.HeaderETag))
+	})
+
+	t.Run("should answer not modified when If-None-Match lists the ETag", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		messageID := uuid.New()
+		mockService.On("GetMessageByID", mock.Anything, &dtos.GetMessageByIDQuery{ID: messageID}).
+			Return(&dtos.GetMessageByIDQueryResponse{ID: messageID, Version: 3}, nil)
+
+		req := httptest.NewRequest(http2.MethodGet, "/messages/"+messageID.String(), nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, `"2", W/"3"`)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	})
+}
+
+func TestMessageHandlers_UpdateMessage(t *testing.T) {
+	text := "updated"
+	messageID := uuid.New()
+	updated := &dtos.UpdateMessageCommandResponse{ID: messageID, Text: text, Version: 4}
+
+	send := func(app *fiber.App, method, contentType, ifMatch, body string) *http2.Response {
+		req := httptest.NewRequest(method, "/messages/"+messageID.String(), bytes.NewReader([]byte(body)))
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		if ifMatch != "" {
+			req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("should replace the message at the If-Match version", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		cmd := &dtos.UpdateMessageCommand{ID: messageID, Text: &text, ExpectedVersion: 3}
+		mockService.On("UpdateMessage", mock.Anything, cmd).Return(updated, nil)
+
+		// Act
+		resp := send(app, http2.MethodPut, fiber.MIMEApplicationJSON, `"3"`, `{"text":"updated"}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"4"`, resp.Header.Get(fiber.HeaderETag))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should require the text of a replacement", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		http.RegisterRoutes(app, http.NewMessageHandlers(new(MockMessageService)))
+
+		// Act
+		resp := send(app, http2.MethodPut, fiber.MIMEApplicationJSON, "", `{}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should apply a merge patch", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("UpdateMessage", mock.Anything, &dtos.UpdateMessageCommand{ID: messageID, Text: &text}).
+			Return(updated, nil)
+
+		// Act
+		resp := send(app, http2.MethodPatch, "application/merge-patch+json", "", `{"text":"updated","unknown":1}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should refuse a merge patch removing the text", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		http.RegisterRoutes(app, http.NewMessageHandlers(new(MockMessageService)))
+
+		// Act
+		resp := send(app, http2.MethodPatch, "application/merge-patch+json", "", `{"text":null}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should refuse other patch formats", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		http.RegisterRoutes(app, http.NewMessageHandlers(new(MockMessageService)))
+
+		// Act
+		resp := send(app, http2.MethodPatch, "application/json-patch+json", "", `[]`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusUnsupportedMediaType, resp.StatusCode)
+	})
+
+	t.Run("should answer precondition failed for a malformed or stale If-Match", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("UpdateMessage", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("pipeline error: %w", models.ErrPreconditionFailed))
+
+		// Act
+		malformed := send(app, http2.MethodPut, fiber.MIMEApplicationJSON, `W/"3"`, `{"text":"updated"}`)
+		stale := send(app, http2.MethodPut, fiber.MIMEApplicationJSON, `"2"`, `{"text":"updated"}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusPreconditionFailed, malformed.StatusCode)
+		assert.Equal(t, fiber.StatusPreconditionFailed, stale.StatusCode)
+		assert.Equal(t, problem.ContentType, stale.Header.Get(fiber.HeaderContentType))
+	})
+
+	t.Run("should answer conflict for a concurrent update", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("UpdateMessage", mock.Anything, mock.Anything).Return(nil, models.ErrVersionConflict)
+
+		// Act
+		resp := send(app, http2.MethodPut, fiber.MIMEApplicationJSON, "", `{"text":"updated"}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("should answer not found for an unknown message", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("UpdateMessage", mock.Anything, mock.Anything).Return(nil, models.ErrMessageNotFound)
+
+		// Act
+		resp := send(app, http2.MethodPut, fiber.MIMEApplicationJSON, "", `{"text":"updated"}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestMessageHandlers_DeleteAndRestore(t *testing.T) {
+	messageID := uuid.New()
+
+	t.Run("should soft delete the message at the If-Match version", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("DeleteMessage", mock.Anything, &dtos.DeleteMessageCommand{ID: messageID, ExpectedVersion: 2}).
+			Return(&dtos.DeleteMessageCommandResponse{ID: messageID, Version: 3}, nil)
+
+		req := httptest.NewRequest(http2.MethodDelete, "/messages/"+messageID.String(), nil)
+		req.Header.Set(fiber.HeaderIfMatch, `"2"`)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should restore the message", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("RestoreMessage", mock.Anything, &dtos.RestoreMessageCommand{ID: messageID}).
+			Return(&dtos.RestoreMessageCommandResponse{ID: messageID, Version: 4}, nil)
+
+		req := httptest.NewRequest(http2.MethodPost, "/messages/"+messageID.String()+":restore", nil)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, `"4"`, resp.Header.Get(fiber.HeaderETag))
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should ask for deleted messages", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("GetMessageByID", mock.Anything, &dtos.GetMessageByIDQuery{ID: messageID, IncludeDeleted: true}).
+			Return(&dtos.GetMessageByIDQueryResponse{ID: messageID, Version: 3}, nil)
+
+		req := httptest.NewRequest(http2.MethodGet, "/messages/"+messageID.String()+"?include_deleted=true", nil)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestMessageHandlers_ListMessages(t *testing.T) {
+	t.Run("should pass the filters and return the page", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		expected := &dtos.ListMessagesQuery{
+			Limit:         10,
+			Sort:          dtos.SortCreatedAtAsc,
+			Cursor:        "abc",
+			AuthorID:      "user-1",
+			Tags:          []string{"news", "sports"},
+			CreatedAfter:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBefore: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		}
+		mockService.On("ListMessages", mock.Anything, expected).
+			Return(&dtos.ListMessagesQueryResponse{
+				Items: []*dtos.ListMessagesQueryItem{{Text: "hello"}},
+				Next:  "next",
+			}, nil)
+
+		req := httptest.NewRequest(http2.MethodGet, "/messages?limit=10&sort=created_at&cursor=abc&author=user-1"+
+			"&tags=news,sports&created_after=2024-01-01T00:00:00Z&created_before=2024-02-01T00:00:00Z", nil)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var responseBody dtos.ListMessagesQueryResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&responseBody))
+		assert.Equal(t, "next", responseBody.Next)
+		require.Len(t, responseBody.Items, 1)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for invalid parameters", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("ListMessages", mock.Anything, mock.Anything).Return(nil, models.ErrInvalidQuery)
+
+		for _, target := range []string{
+			"/messages?limit=ten", "/messages?created_after=yesterday", "/messages?limit=1000",
+		} {
+			// Act
+			resp, err := app.Test(httptest.NewRequest(http2.MethodGet, target, nil))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, target)
+		}
+	})
+}
+
+func TestMessageHandlers_GetMessagesByIDs(t *testing.T) {
+	t.Run("should return the batch when ids are given", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		found, missing := uuid.New(), uuid.New()
+		query := &dtos.GetMessagesByIDsQuery{IDs: []uuid.UUID{found, missing}}
+		mockService.On("GetMessagesByIDs", mock.Anything, query).
+			Return(&dtos.GetMessagesByIDsQueryResponse{
+				Items:   []*dtos.GetMessageByIDQueryResponse{{ID: found, Text: "hello"}},
+				Missing: []uuid.UUID{missing},
+			}, nil)
+
+		req := httptest.NewRequest(http2.MethodGet, "/messages?ids="+found.String()+","+missing.String(), nil)
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var responseBody dtos.GetMessagesByIDsQueryResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&responseBody))
+		require.Len(t, responseBody.Items, 1)
+		assert.Equal(t, []uuid.UUID{missing}, responseBody.Missing)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return bad request for an invalid ID or batch", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("GetMessagesByIDs", mock.Anything, mock.Anything).Return(nil, models.ErrInvalidQuery)
+
+		for _, target := range []string{"/messages?ids=not-a-uuid", "/messages?ids=" + uuid.NewString()} {
+			// Act
+			resp, err := app.Test(httptest.NewRequest(http2.MethodGet, target, nil))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, target)
+		}
+	})
+}
+
+func TestMessageHandlers_CreateMessagesBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *dtos.CreateMessagesBatchCommandResponse
+		expected int
+	}{
+		{
+			name:     "should return created when every item was created",
+			result:   &dtos.CreateMessagesBatchCommandResponse{Created: 2},
+			expected: fiber.StatusCreated,
+		},
+		{
+			name:     "should return multi-status when some items failed",
+			result:   &dtos.CreateMessagesBatchCommandResponse{Created: 1, Failed: 1},
+			expected: fiber.StatusMultiStatus,
+		},
+		{
+			name:     "should return unprocessable entity when no item was created",
+			result:   &dtos.CreateMessagesBatchCommandResponse{Failed: 2},
+			expected: fiber.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			app := fiber.New()
+			mockService := new(MockMessageService)
+			http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+			expected := &dtos.CreateMessagesBatchCommand{
+				Mode:  dtos.BatchModeBestEffort,
+				Items: []*dtos.CreateMessageCommand{{Text: "first"}, {Text: "second"}},
+			}
+			mockService.On("CreateMessagesBatch", mock.Anything, expected).Return(tt.result, nil)
+
+			body := `{"mode":"best_effort","items":[{"text":"first"},{"text":"second"}]}`
+			req := httptest.NewRequest(http2.MethodPost, "/messages:batch", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			// Act
+			resp, err := app.Test(req)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, resp.StatusCode)
+			mockService.AssertExpectations(t)
+		})
+	}
+
+	t.Run("should return bad request for an invalid batch", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("CreateMessagesBatch", mock.Anything, mock.Anything).Return(nil, models.ErrInvalidBatch)
+
+		req := httptest.NewRequest(http2.MethodPost, "/messages:batch", bytes.NewBufferString(`{"items":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should return conflict for a message that already exists", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		mockService := new(MockMessageService)
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+
+		mockService.On("CreateMessagesBatch", mock.Anything, mock.Anything).
+			Return(nil, fmt.Errorf("%w: message with ID 1 already exists", models.ErrMessageAlreadyExists))
+
+		body := bytes.NewBufferString(`{"items":[{"text":"a"}]}`)
+		req := httptest.NewRequest(http2.MethodPost, "/messages:batch", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	})
+}
+
+func TestMessageHandlers_BackupMessages(t *testing.T) {
+	// newApp returns an app authenticated as principal if set.
+	newApp := func(mockService *MockMessageService, principal *auth.Principal) *fiber.App {
+		app := fiber.New()
+		if principal != nil {
+			app.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(auth.NewContext(c.UserContext(), principal))
+				return c.Next()
+			})
+		}
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+		return app
+	}
+	admin := &auth.Principal{Subject: "admin-1", Scopes: []string{dtos.ScopeMessagesAdmin}}
+
+	t.Run("should return created with the backup", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockMessageService)
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockService.On("BackupMessages", mock.Anything, &dtos.BackupMessagesCommand{}).
+			Return(&dtos.BackupMessagesCommandResponse{
+				CreatedAt: createdAt,
+				Path:      "data/backups/messages.backup",
+				Size:      32768,
+			}, nil)
+
+		req := httptest.NewRequest(http2.MethodPost, "/admin/messages/backup", nil)
+
+		// Act
+		resp, err := newApp(mockService, admin).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		var result dtos.BackupMessagesCommandResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, int64(32768), result.Size)
+		assert.Equal(t, createdAt, result.CreatedAt)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not implemented when the store cannot be backed up", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockMessageService)
+		mockService.On("BackupMessages", mock.Anything, mock.Anything).Return(nil, models.ErrBackupUnsupported)
+
+		req := httptest.NewRequest(http2.MethodPost, "/admin/messages/backup", nil)
+
+		// Act
+		resp, err := newApp(mockService, admin).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotImplemented, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	})
+
+	t.Run("should reject callers without the admin scope before the service", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockMessageService)
+		req := httptest.NewRequest(http2.MethodPost, "/admin/messages/backup", nil)
+
+		// Act
+		anonymous, err := newApp(mockService, nil).Test(req)
+		require.NoError(t, err)
+		writer, err := newApp(
+			mockService,
+			&auth.Principal{Subject: "user-1", Scopes: []string{dtos.ScopeMessagesWrite}},
+		).
+			Test(httptest.NewRequest(http2.MethodPost, "/admin/messages/backup", nil))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusUnauthorized, anonymous.StatusCode)
+		assert.Equal(t, fiber.StatusForbidden, writer.StatusCode)
+		mockService.AssertNotCalled(t, "BackupMessages", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMessageHandlers_RebuildProjections(t *testing.T) {
+	// newApp returns an app authenticated as principal if set.
+	newApp := func(mockService *MockMessageService, principal *auth.Principal) *fiber.App {
+		app := fiber.New()
+		if principal != nil {
+			app.Use(func(c *fiber.Ctx) error {
+				c.SetUserContext(auth.NewContext(c.UserContext(), principal))
+				return c.Next()
+			})
+		}
+		http.RegisterRoutes(app, http.NewMessageHandlers(mockService))
+		return app
+	}
+	admin := &auth.Principal{Subject: "admin-1", Scopes: []string{dtos.ScopeMessagesAdmin}}
+
+	t.Run("should return ok with what was replayed", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockMessageService)
+		rebuiltAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		mockService.On("RebuildProjections", mock.Anything, &dtos.RebuildProjectionsCommand{}).
+			Return(&dtos.RebuildProjectionsCommandResponse{RebuiltAt: rebuiltAt, Streams: 3, Events: 12}, nil)
+
+		req := httptest.NewRequest(http2.MethodPost, "/admin/messages/projections/rebuild", nil)
+
+		// Act
+		resp, err := newApp(mockService, admin).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var result dtos.RebuildProjectionsCommandResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, 3, result.Streams)
+		assert.Equal(t, 12, result.Events)
+		assert.Equal(t, rebuiltAt, result.RebuiltAt)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("should return not implemented when the store is not event sourced", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockMessageService)
+		mockService.On("RebuildProjections", mock.Anything, mock.Anything).Return(nil, models.ErrProjectionsUnsupported)
+
+		req := httptest.NewRequest(http2.MethodPost, "/admin/messages/projections/rebuild", nil)
+
+		// Act
+		resp, err := newApp(mockService, admin).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusNotImplemented, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	})
+
+	t.Run("should reject callers without the admin scope before the service", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockMessageService)
+		req := httptest.NewRequest(http2.MethodPost, "/admin/messages/projections/rebuild", nil)
+
+		// Act
+		anonymous, err := newApp(mockService, nil).Test(req)
+		require.NoError(t, err)
+		writer, err := newApp(
+			mockService,
+			&auth.Principal{Subject: "user-1", Scopes: []string{dtos.ScopeMessagesWrite}},
+		).
+			Test(httptest.NewRequest(http2.MethodPost, "/admin/messages/projections/rebuild", nil))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusUnauthorized, anonymous.StatusCode)
+		assert.Equal(t, fiber.StatusForbidden, writer.StatusCode)
+		mockService.AssertNotCalled(t, "RebuildProjections", mock.Anything, mock.Anything)
+	})
+}
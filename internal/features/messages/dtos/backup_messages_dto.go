@@ -0,0 +1,19 @@
+package dtos
+
+import "time"
+
+// BackupMessagesCommand is the command for writing a backup of the message store to
+// the backup directory.
+type BackupMessagesCommand struct{}
+
+// RequiredScopes returns the scopes a caller needs to back up the messages.
+func (c *BackupMessagesCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesAdmin}
+}
+
+// BackupMessagesCommandResponse is the response for BackupMessagesCommand.
+type BackupMessagesCommandResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+}
@@ -1,10 +1,75 @@
 package dtos
 
-import "github.com/google/uuid"
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Limits of the fields of CreateMessageCommand.
+const (
+	MaxTextLength = 4096
+	MaxTags       = 10
+	MaxTagLength  = 64
+)
+
+// FieldError describes why the value of a field is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when the fields of a command are invalid.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		fields = append(fields, err.Field)
+	}
+	return "invalid fields: " + strings.Join(fields, ", ")
+}
 
 // CreateMessageCommand is the command for creating a new message.
 type CreateMessageCommand struct {
-	Text string `json:"text"`
+	Text string   `json:"text"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// RequiredScopes returns the scopes a caller needs to create a message.
+func (c *CreateMessageCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesWrite}
+}
+
+// Validate returns the errors of the fields of the command, if any.
+func (c *CreateMessageCommand) Validate() []FieldError {
+	var errs []FieldError
+	switch {
+	case strings.TrimSpace(c.Text) == "":
+		errs = append(errs, FieldError{Field: "text", Message: "text is required"})
+	case utf8.RuneCountInString(c.Text) > MaxTextLength:
+		errs = append(
+			errs,
+			FieldError{Field: "text", Message: fmt.Sprintf("text exceeds %d characters", MaxTextLength)},
+		)
+	}
+
+	if len(c.Tags) > MaxTags {
+		errs = append(errs, FieldError{Field: "tags", Message: fmt.Sprintf("at most %d tags are allowed", MaxTags)})
+	}
+	for i, tag := range c.Tags {
+		if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+			errs = append(errs, FieldError{
+				Field:   fmt.Sprintf("tags[%d]", i),
+				Message: fmt.Sprintf("tags must have between 1 and %d characters", MaxTagLength),
+			})
+		}
+	}
+	return errs
 }
 
 // CreateMessageCommandResponse is the response for CreateMessageCommand.
@@ -1,6 +1,7 @@
 package dtos_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/arielsrv/fxf/internal/features/messages/dtos"
@@ -82,3 +83,74 @@ func TestCreateMessageCommandResponse(t *testing.T) {
 		assert.Equal(t, id, resp.ID)
 	})
 }
+
+func TestCreateMessageCommand_RequiredScopes(t *testing.T) {
+	t.Run("should require the messages write scope", func(t *testing.T) {
+		// Arrange
+		cmd := &dtos.CreateMessageCommand{}
+
+		// Act
+		scopes := cmd.RequiredScopes()
+
+		// Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesWrite}, scopes)
+	})
+}
+
+func TestCreateMessageCommand_Validate(t *testing.T) {
+	t.Run("should accept a valid command", func(t *testing.T) {
+		// Arrange
+		cmd := &dtos.CreateMessageCommand{Text: "hello", Tags: []string{"news"}}
+
+		// Act
+		errs := cmd.Validate()
+
+		// Assert
+		assert.Empty(t, errs)
+	})
+
+	t.Run("should report every invalid field", func(t *testing.T) {
+		// Arrange
+		cmd := &dtos.CreateMessageCommand{Text: "  ", Tags: []string{"news", ""}}
+
+		// Act
+		errs := cmd.Validate()
+
+		// Assert
+		require.Len(t, errs, 2)
+		assert.Equal(t, "text", errs[0].Field)
+		assert.Equal(t, "tags[1]", errs[1].Field)
+	})
+
+	t.Run("should enforce the length limits", func(t *testing.T) {
+		// Arrange
+		cmd := &dtos.CreateMessageCommand{
+			Text: strings.Repeat("a", dtos.MaxTextLength+1),
+			Tags: make([]string, dtos.MaxTags+1),
+		}
+		for i := range cmd.Tags {
+			cmd.Tags[i] = "tag"
+		}
+
+		// Act
+		errs := cmd.Validate()
+
+		// Assert
+		require.Len(t, errs, 2)
+		assert.Equal(t, "text", errs[0].Field)
+		assert.Equal(t, "tags", errs[1].Field)
+	})
+}
+
+func TestValidationError_Error(t *testing.T) {
+	t.Run("should name the invalid fields", func(t *testing.T) {
+		// Arrange
+		err := &dtos.ValidationError{Errors: []dtos.FieldError{{Field: "text"}, {Field: "tags[1]"}}}
+
+		// Act
+		message := err.Error()
+
+		// Assert
+		assert.Equal(t, "invalid fields: text, tags[1]", message)
+	})
+}
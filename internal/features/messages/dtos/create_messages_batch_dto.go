@@ -0,0 +1,51 @@
+package dtos
+
+import "github.com/google/uuid"
+
+// MaxCreateBatchSize is the maximum number of items of a CreateMessagesBatchCommand.
+const MaxCreateBatchSize = 1000
+
+// Modes of CreateMessagesBatchCommand.
+const (
+	// BatchModeAllOrNothing creates no message when any item is invalid.
+	BatchModeAllOrNothing = "all_or_nothing"
+	// BatchModeBestEffort creates the valid items and reports the invalid ones.
+	BatchModeBestEffort = "best_effort"
+)
+
+// Statuses of CreateMessagesBatchItemResult.
+const (
+	BatchItemCreated = "created"
+	BatchItemInvalid = "invalid"
+	// BatchItemAborted is the status of a valid item that was not created because
+	// another item of an all-or-nothing batch is invalid.
+	BatchItemAborted = "aborted"
+)
+
+// CreateMessagesBatchCommand is the command for creating several messages at once.
+type CreateMessagesBatchCommand struct {
+	// Mode is BatchModeAllOrNothing, the default, or BatchModeBestEffort.
+	Mode  string                  `json:"mode,omitempty"`
+	Items []*CreateMessageCommand `json:"items"`
+}
+
+// RequiredScopes returns the scopes a caller needs to create messages.
+func (c *CreateMessagesBatchCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesWrite}
+}
+
+// CreateMessagesBatchItemResult is the outcome of an item of CreateMessagesBatchCommand.
+type CreateMessagesBatchItemResult struct {
+	ID     *uuid.UUID   `json:"id,omitempty"`
+	Status string       `json:"status"`
+	Errors []FieldError `json:"errors,omitempty"`
+	Index  int          `json:"index"`
+}
+
+// CreateMessagesBatchCommandResponse is the response for CreateMessagesBatchCommand,
+// with a result per item in the order of the command.
+type CreateMessagesBatchCommandResponse struct {
+	Results []*CreateMessagesBatchItemResult `json:"results"`
+	Created int                              `json:"created"`
+	Failed  int                              `json:"failed"`
+}
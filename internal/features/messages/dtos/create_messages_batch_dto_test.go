@@ -0,0 +1,21 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMessagesBatchCommand_RequiredScopes(t *testing.T) {
+	t.Run("should require the messages write scope", func(t *testing.T) {
+		// Arrange
+		cmd := &dtos.CreateMessagesBatchCommand{}
+
+		// Act
+		scopes := cmd.RequiredScopes()
+
+		// Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesWrite}, scopes)
+	})
+}
@@ -0,0 +1,47 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeleteMessageCommand is the command for soft deleting a message.
+type DeleteMessageCommand struct {
+	ID uuid.UUID
+	// ExpectedVersion is the version the caller last read, or 0 to delete whatever
+	// the current version is.
+	ExpectedVersion int64
+}
+
+// RequiredScopes returns the scopes a caller needs to delete a message.
+func (c *DeleteMessageCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesWrite}
+}
+
+// DeleteMessageCommandResponse is the response for DeleteMessageCommand.
+type DeleteMessageCommandResponse struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	Version   int64     `json:"version"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// RestoreMessageCommand is the command for restoring a soft deleted message.
+type RestoreMessageCommand struct {
+	ID uuid.UUID
+}
+
+// RequiredScopes returns the scopes a caller needs to restore a message.
+func (c *RestoreMessageCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesWrite}
+}
+
+// RestoreMessageCommandResponse is the response for RestoreMessageCommand.
+type RestoreMessageCommandResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Text      string    `json:"text"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Version   int64     `json:"version"`
+	ID        uuid.UUID `json:"id"`
+}
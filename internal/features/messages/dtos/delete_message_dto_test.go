@@ -0,0 +1,16 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteAndRestoreMessageCommand_RequiredScopes(t *testing.T) {
+	t.Run("should require the messages write scope", func(t *testing.T) {
+		// Act & Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesWrite}, (&dtos.DeleteMessageCommand{}).RequiredScopes())
+		assert.Equal(t, []string{dtos.ScopeMessagesWrite}, (&dtos.RestoreMessageCommand{}).RequiredScopes())
+	})
+}
@@ -1,14 +1,55 @@
 package dtos
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // GetMessageByIDQuery is the query for retrieving a message by its ID.
 type GetMessageByIDQuery struct {
 	ID uuid.UUID
+	// IncludeDeleted returns soft deleted messages to callers holding the messages
+	// admin scope. It is ignored for everyone else.
+	IncludeDeleted bool
+}
+
+// RequiredScopes returns the scopes a caller needs to read a message.
+func (q *GetMessageByIDQuery) RequiredScopes() []string {
+	return []string{ScopeMessagesRead}
+}
+
+// MaxBatchSize is the maximum number of IDs of a GetMessagesByIDsQuery.
+const MaxBatchSize = 100
+
+// GetMessagesByIDsQuery is the query for retrieving several messages by their IDs.
+type GetMessagesByIDsQuery struct {
+	IDs []uuid.UUID
+	// IncludeDeleted returns soft deleted messages to callers holding the messages
+	// admin scope. It is ignored for everyone else.
+	IncludeDeleted bool
+}
+
+// RequiredScopes returns the scopes a caller needs to read messages.
+func (q *GetMessagesByIDsQuery) RequiredScopes() []string {
+	return []string{ScopeMessagesRead}
+}
+
+// GetMessagesByIDsQueryResponse is the response for GetMessagesByIDsQuery. Items
+// follow the order of the requested IDs, and Missing lists the IDs not found.
+type GetMessagesByIDsQueryResponse struct {
+	Items   []*GetMessageByIDQueryResponse `json:"items"`
+	Missing []uuid.UUID                    `json:"missing"`
 }
 
 // GetMessageByIDQueryResponse is the response for GetMessageByIDQuery.
 type GetMessageByIDQueryResponse struct {
-	Text string    `json:"text"`
-	ID   uuid.UUID `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Text      string     `json:"text"`
+	AuthorID  string     `json:"author_id,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	Version   int64      `json:"version"`
+	ID        uuid.UUID  `json:"id"`
 }
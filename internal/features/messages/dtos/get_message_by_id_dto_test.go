@@ -91,3 +91,29 @@ func TestGetMessageByIDQueryResponse(t *testing.T) {
 		assert.Equal(t, text, resp.Text)
 	})
 }
+
+func TestGetMessageByIDQuery_RequiredScopes(t *testing.T) {
+	t.Run("should require the messages read scope", func(t *testing.T) {
+		// Arrange
+		query := &dtos.GetMessageByIDQuery{}
+
+		// Act
+		scopes := query.RequiredScopes()
+
+		// Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesRead}, scopes)
+	})
+}
+
+func TestGetMessagesByIDsQuery_RequiredScopes(t *testing.T) {
+	t.Run("should require the messages read scope", func(t *testing.T) {
+		// Arrange
+		query := &dtos.GetMessagesByIDsQuery{}
+
+		// Act
+		scopes := query.RequiredScopes()
+
+		// Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesRead}, scopes)
+	})
+}
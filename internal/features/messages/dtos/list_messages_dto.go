@@ -0,0 +1,59 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Page sizes of ListMessagesQuery.
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Sort orders of ListMessagesQuery.
+const (
+	SortCreatedAtAsc  = "created_at"
+	SortCreatedAtDesc = "-created_at"
+)
+
+// ListMessagesQuery is the query for listing the messages of the tenant, a page at a time.
+type ListMessagesQuery struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Cursor is the next or prev cursor of a previous page, or empty for the first page.
+	Cursor   string
+	Sort     string
+	AuthorID string
+	Tags     []string
+	Limit    int
+	// IncludeDeleted lists soft deleted messages to callers holding the messages
+	// admin scope. It is ignored for everyone else.
+	IncludeDeleted bool
+}
+
+// RequiredScopes returns the scopes a caller needs to list messages.
+func (q *ListMessagesQuery) RequiredScopes() []string {
+	return []string{ScopeMessagesRead}
+}
+
+// ListMessagesQueryItem is a message of ListMessagesQueryResponse.
+type ListMessagesQueryItem struct {
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Text      string     `json:"text"`
+	AuthorID  string     `json:"author_id,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	Version   int64      `json:"version"`
+	ID        uuid.UUID  `json:"id"`
+}
+
+// ListMessagesQueryResponse is the response for ListMessagesQuery. Next and Prev are
+// opaque cursors of the adjacent pages, empty when there is none.
+type ListMessagesQueryResponse struct {
+	Next  string                   `json:"next,omitempty"`
+	Prev  string                   `json:"prev,omitempty"`
+	Items []*ListMessagesQueryItem `json:"items"`
+}
@@ -0,0 +1,21 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListMessagesQuery_RequiredScopes(t *testing.T) {
+	t.Run("should require the read scope", func(t *testing.T) {
+		// Arrange
+		query := &dtos.ListMessagesQuery{}
+
+		// Act
+		scopes := query.RequiredScopes()
+
+		// Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesRead}, scopes)
+	})
+}
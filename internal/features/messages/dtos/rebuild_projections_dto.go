@@ -0,0 +1,21 @@
+package dtos
+
+import "time"
+
+// RebuildProjectionsCommand is the command for rebuilding the projections of the
+// event-sourced message store from its event streams.
+type RebuildProjectionsCommand struct{}
+
+// RequiredScopes returns the scopes a caller needs to rebuild the projections.
+func (c *RebuildProjectionsCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesAdmin}
+}
+
+// RebuildProjectionsCommandResponse is the response for RebuildProjectionsCommand.
+type RebuildProjectionsCommandResponse struct {
+	RebuiltAt time.Time `json:"rebuilt_at"`
+	// Streams is the number of message streams replayed.
+	Streams int `json:"streams"`
+	// Events is the number of events replayed.
+	Events int `json:"events"`
+}
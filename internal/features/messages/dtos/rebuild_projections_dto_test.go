@@ -0,0 +1,21 @@
+package dtos_test
+
+import (
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildProjectionsCommand_RequiredScopes(t *testing.T) {
+	t.Run("should require the messages admin scope", func(t *testing.T) {
+		// Arrange
+		cmd := &dtos.RebuildProjectionsCommand{}
+
+		// Act
+		scopes := cmd.RequiredScopes()
+
+		// Assert
+		assert.Equal(t, []string{dtos.ScopeMessagesAdmin}, scopes)
+	})
+}
@@ -0,0 +1,9 @@
+package dtos
+
+// Scopes required by the message requests.
+const (
+	ScopeMessagesRead  = "messages:read"
+	ScopeMessagesWrite = "messages:write"
+	// ScopeMessagesAdmin allows mutating the messages of other authors.
+	ScopeMessagesAdmin = "messages:admin"
+)
@@ -0,0 +1,32 @@
+package dtos
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UpdateMessageCommand is the command for updating a message. Nil fields are left
+// unchanged, so that it can carry both a full replacement and a merge patch.
+type UpdateMessageCommand struct {
+	Text *string
+	ID   uuid.UUID
+	// ExpectedVersion is the version the caller last read, or 0 to update whatever
+	// the current version is.
+	ExpectedVersion int64
+}
+
+// RequiredScopes returns the scopes a caller needs to update a message.
+func (c *UpdateMessageCommand) RequiredScopes() []string {
+	return []string{ScopeMessagesWrite}
+}
+
+// UpdateMessageCommandResponse is the response for UpdateMessageCommand.
+type UpdateMessageCommandResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Text      string    `json:"text"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Version   int64     `json:"version"`
+	ID        uuid.UUID `json:"id"`
+}
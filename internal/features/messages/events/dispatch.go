@@ -0,0 +1,26 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownEvent is returned by Dispatch for an event type it cannot decode.
+var ErrUnknownEvent = errors.New("unknown event type")
+
+// Dispatch decodes the JSON payload of an event of the given type, as stored in an
+// outbox, and publishes it to the handlers registered with MediatR.
+func Dispatch(ctx context.Context, eventType string, payload []byte) error {
+	switch eventType {
+	case MessageCreatedType:
+		var event MessageCreatedEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return fmt.Errorf("failed to decode %s event: %w", eventType, err)
+		}
+		return event.Publish(ctx)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownEvent, eventType)
+	}
+}
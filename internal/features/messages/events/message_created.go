@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/google/uuid"
+	"github.com/mehdihadeli/go-mediatr"
+	"go.uber.org/fx"
+)
+
+// Module exports the domain event handlers.
+var Module = fx.Options(
+	fx.Invoke(registerLogHandlers),
+)
+
+// MessageCreatedType is the type of MessageCreatedEvent in an outbox.
+const MessageCreatedType = "message.created"
+
+// MessageCreatedEvent is published once a message has been persisted.
+type MessageCreatedEvent struct {
+	CreatedAt time.Time `json:"created_at"`
+	TenantID  string    `json:"tenant_id"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// NewMessageCreatedEvent returns the event of the creation of message.
+func NewMessageCreatedEvent(message *models.Message) *MessageCreatedEvent {
+	return &MessageCreatedEvent{
+		ID:        message.ID,
+		TenantID:  message.TenantID,
+		AuthorID:  message.AuthorID,
+		CreatedAt: message.CreatedAt,
+	}
+}
+
+// Publish publishes the event to the handlers registered with MediatR.
+func (e *MessageCreatedEvent) Publish(ctx context.Context) error {
+	return mediatr.Publish[*MessageCreatedEvent](ctx, e)
+}
+
+// logHandler logs the events it receives at debug level.
+type logHandler struct{}
+
+func (logHandler) Handle(ctx context.Context, event *MessageCreatedEvent) error {
+	slog.DebugContext(ctx, "message created",
+		slog.String("message_id", event.ID.String()),
+		slog.String("tenant_id", event.TenantID),
+	)
+	return nil
+}
+
+// registerLogHandlers registers the event handlers with MediatR.
+func registerLogHandlers() error {
+	return mediatr.RegisterNotificationHandler[*MessageCreatedEvent](logHandler{})
+}
@@ -0,0 +1,28 @@
+package models
+
+import "errors"
+
+var (
+	// ErrMessageNotFound is returned when a message does not exist in the tenant.
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrMessageAlreadyExists is returned when a message is created with the ID of a
+	// message of the tenant, or of another message of the same batch.
+	ErrMessageAlreadyExists = errors.New("message already exists")
+	// ErrVersionConflict is returned when a message changed since it was read.
+	ErrVersionConflict = errors.New("message version conflict")
+	// ErrPreconditionFailed is returned when a message is not at the version the
+	// caller expected.
+	ErrPreconditionFailed = errors.New("message version precondition failed")
+	// ErrInvalidQuery is returned when a listing is asked with an invalid cursor,
+	// page size or sort order.
+	ErrInvalidQuery = errors.New("invalid message query")
+	// ErrInvalidBatch is returned when a batch is empty, too large or has an
+	// unknown mode.
+	ErrInvalidBatch = errors.New("invalid message batch")
+	// ErrBackupUnsupported is returned when the configured store cannot be backed up
+	// while it serves requests.
+	ErrBackupUnsupported = errors.New("the message store does not support online backups")
+	// ErrProjectionsUnsupported is returned when the configured store does not serve
+	// the messages from projections of an event store.
+	ErrProjectionsUnsupported = errors.New("the message store is not event sourced")
+)
@@ -1,8 +1,39 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Message struct {
-	Text string
-	ID   uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// DeletedAt is set when the message is soft deleted, until it is restored or purged.
+	DeletedAt time.Time
+	Text      string
+	AuthorID  string
+	TenantID  string
+	Tags      []string
+	// Version starts at 1 and is incremented by every update.
+	Version int64
+	ID      uuid.UUID
+}
+
+// IsAuthoredBy reports whether subject is the author of the message.
+func (m *Message) IsAuthoredBy(subject string) bool {
+	return m.AuthorID != "" && m.AuthorID == subject
+}
+
+// IsDeleted reports whether the message is soft deleted.
+func (m *Message) IsDeleted() bool {
+	return !m.DeletedAt.IsZero()
+}
+
+// Clone returns a deep copy of the message, which shares no memory with it.
+func (m *Message) Clone() *Message {
+	clone := *m
+	clone.Tags = slices.Clone(m.Tags)
+	return &clone
 }
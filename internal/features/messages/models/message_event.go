@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MessageEventType is the type of a MessageEvent.
+type MessageEventType string
+
+// Types of MessageEvent.
+const (
+	MessageCreated  MessageEventType = "created"
+	MessageEdited   MessageEventType = "edited"
+	MessageDeleted  MessageEventType = "deleted"
+	MessageRestored MessageEventType = "restored"
+)
+
+// MessageEvent is a change of a message, in the event stream of the message. Each type
+// sets only the fields it changes: all of them for MessageCreated, the content for
+// MessageEdited and DeletedAt for MessageDeleted.
+type MessageEvent struct {
+	CreatedAt time.Time        `json:"created_at,omitzero"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	DeletedAt time.Time        `json:"deleted_at,omitzero"`
+	Type      MessageEventType `json:"type"`
+	Text      string           `json:"text,omitempty"`
+	AuthorID  string           `json:"author_id,omitempty"`
+	Tags      []string         `json:"tags,omitempty"`
+	// Version is the version of the message once the event is applied.
+	Version int64 `json:"version"`
+}
+
+// NewMessageCreated returns the event creating message.
+func NewMessageCreated(message *Message) *MessageEvent {
+	return &MessageEvent{
+		Type:      MessageCreated,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		DeletedAt: message.DeletedAt,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		Tags:      slices.Clone(message.Tags),
+		Version:   message.Version,
+	}
+}
+
+// MessageChanges returns the events turning stored into updated: a MessageEdited
+// event unless only the deletion changed, followed by a MessageDeleted or
+// MessageRestored event if it did. The events are at the version of updated.
+func MessageChanges(stored, updated *Message) []*MessageEvent {
+	deletion := !stored.DeletedAt.Equal(updated.DeletedAt)
+	edited := stored.Text != updated.Text || stored.AuthorID != updated.AuthorID ||
+		!slices.Equal(stored.Tags, updated.Tags)
+
+	var events []*MessageEvent
+	if edited || !deletion {
+		events = append(events, &MessageEvent{
+			Type:      MessageEdited,
+			UpdatedAt: updated.UpdatedAt,
+			Text:      updated.Text,
+			AuthorID:  updated.AuthorID,
+			Tags:      slices.Clone(updated.Tags),
+			Version:   updated.Version,
+		})
+	}
+	switch {
+	case deletion && updated.IsDeleted():
+		events = append(events, &MessageEvent{
+			Type:      MessageDeleted,
+			UpdatedAt: updated.UpdatedAt,
+			DeletedAt: updated.DeletedAt,
+			Version:   updated.Version,
+		})
+	case deletion:
+		events = append(events, &MessageEvent{
+			Type:      MessageRestored,
+			UpdatedAt: updated.UpdatedAt,
+			Version:   updated.Version,
+		})
+	}
+	return events
+}
+
+// Apply changes the message as told by the event. The message is the aggregate of
+// its event stream: applying the stream in order to a message with only its ID and
+// tenant set yields its current state.
+func (m *Message) Apply(event *MessageEvent) error {
+	switch event.Type {
+	case MessageCreated:
+		m.CreatedAt = event.CreatedAt
+		m.DeletedAt = event.DeletedAt
+		m.Text = event.Text
+		m.AuthorID = event.AuthorID
+		m.Tags = slices.Clone(event.Tags)
+	case MessageEdited:
+		m.Text = event.Text
+		m.AuthorID = event.AuthorID
+		m.Tags = slices.Clone(event.Tags)
+	case MessageDeleted:
+		m.DeletedAt = event.DeletedAt
+	case MessageRestored:
+		m.DeletedAt = time.Time{}
+	default:
+		return fmt.Errorf("unknown message event type %q", event.Type)
+	}
+	m.UpdatedAt = event.UpdatedAt
+	m.Version = event.Version
+	return nil
+}
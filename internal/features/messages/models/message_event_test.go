@@ -0,0 +1,118 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var created = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// replay applies the events to a message with only an ID and a tenant.
+func replay(t *testing.T, events ...*models.MessageEvent) *models.Message {
+	t.Helper()
+	message := &models.Message{ID: uuid.New(), TenantID: "acme"}
+	for _, event := range events {
+		require.NoError(t, message.Apply(event))
+	}
+	return message
+}
+
+func TestMessageEvents(t *testing.T) {
+	t.Run("should replay the creation of a message", func(t *testing.T) {
+		// Arrange
+		original := &models.Message{
+			Text: "hello", AuthorID: "user-1", Tags: []string{"news"},
+			CreatedAt: created, UpdatedAt: created, Version: 1,
+		}
+
+		// Act
+		message := replay(t, models.NewMessageCreated(original))
+
+		// Assert
+		assert.Equal(t, "hello", message.Text)
+		assert.Equal(t, "user-1", message.AuthorID)
+		assert.Equal(t, []string{"news"}, message.Tags)
+		assert.Equal(t, created, message.CreatedAt)
+		assert.Equal(t, int64(1), message.Version)
+		assert.False(t, message.IsDeleted())
+	})
+
+	t.Run("should record edits, deletions and restorations", func(t *testing.T) {
+		// Arrange
+		v1 := &models.Message{Text: "v1", CreatedAt: created, UpdatedAt: created, Version: 1}
+		v2 := &models.Message{
+			Text:      "v2",
+			Tags:      []string{"news"},
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Hour),
+			Version:   2,
+		}
+		v3 := v2.Clone()
+		v3.DeletedAt, v3.UpdatedAt, v3.Version = created.Add(2*time.Hour), created.Add(2*time.Hour), 3
+		v4 := v3.Clone()
+		v4.DeletedAt, v4.UpdatedAt, v4.Version = time.Time{}, created.Add(3*time.Hour), 4
+
+		// Act
+		edited := models.MessageChanges(v1, v2)
+		deleted := models.MessageChanges(v2, v3)
+		restored := models.MessageChanges(v3, v4)
+
+		// Assert
+		require.Len(t, edited, 1)
+		assert.Equal(t, models.MessageEdited, edited[0].Type)
+		require.Len(t, deleted, 1)
+		assert.Equal(t, models.MessageDeleted, deleted[0].Type)
+		require.Len(t, restored, 1)
+		assert.Equal(t, models.MessageRestored, restored[0].Type)
+
+		message := replay(t, append(append(append([]*models.MessageEvent{models.NewMessageCreated(v1)},
+			edited...), deleted...), restored...)...)
+		assert.Equal(t, "v2", message.Text)
+		assert.Equal(t, []string{"news"}, message.Tags)
+		assert.False(t, message.IsDeleted())
+		assert.Equal(t, created, message.CreatedAt)
+		assert.Equal(t, created.Add(3*time.Hour), message.UpdatedAt)
+		assert.Equal(t, int64(4), message.Version)
+	})
+
+	t.Run("should record an edit and a deletion made at once", func(t *testing.T) {
+		// Arrange
+		stored := &models.Message{Text: "v1", CreatedAt: created, Version: 1}
+		updated := &models.Message{Text: "v2", CreatedAt: created, DeletedAt: created.Add(time.Hour), Version: 2}
+
+		// Act
+		events := models.MessageChanges(stored, updated)
+
+		// Assert
+		require.Len(t, events, 2)
+		assert.Equal(t, models.MessageEdited, events[0].Type)
+		assert.Equal(t, models.MessageDeleted, events[1].Type)
+		assert.Equal(t, int64(2), events[1].Version)
+	})
+
+	t.Run("should record an update changing nothing as an edit", func(t *testing.T) {
+		// Arrange
+		stored := &models.Message{Text: "v1", Version: 1}
+		updated := &models.Message{Text: "v1", Version: 2}
+
+		// Act
+		events := models.MessageChanges(stored, updated)
+
+		// Assert
+		require.Len(t, events, 1)
+		assert.Equal(t, models.MessageEdited, events[0].Type)
+	})
+
+	t.Run("should refuse an unknown event type", func(t *testing.T) {
+		// Act
+		err := (&models.Message{}).Apply(&models.MessageEvent{Type: "archived"})
+
+		// Assert
+		require.Error(t, err)
+	})
+}
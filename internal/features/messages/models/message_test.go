@@ -2,6 +2,7 @@ package models_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/models"
 	"github.com/google/uuid"
@@ -97,3 +98,52 @@ func TestMessageStructure(t *testing.T) {
 		assert.IsType(t, "", message.Text)
 	})
 }
+
+func TestMessage_IsAuthoredBy(t *testing.T) {
+	t.Run("should match the author only", func(t *testing.T) {
+		// Arrange
+		message := &models.Message{AuthorID: "user-1"}
+
+		// Act & Assert
+		assert.True(t, message.IsAuthoredBy("user-1"))
+		assert.False(t, message.IsAuthoredBy("user-2"))
+	})
+
+	t.Run("should not match anyone for anonymous messages", func(t *testing.T) {
+		// Arrange
+		message := &models.Message{}
+
+		// Act & Assert
+		assert.False(t, message.IsAuthoredBy(""))
+	})
+}
+
+func TestMessage_IsDeleted(t *testing.T) {
+	t.Run("should report the soft deletion", func(t *testing.T) {
+		// Arrange
+		live := &models.Message{}
+		deleted := &models.Message{DeletedAt: time.Now()}
+
+		// Act & Assert
+		assert.False(t, live.IsDeleted())
+		assert.True(t, deleted.IsDeleted())
+	})
+}
+
+func TestMessage_Clone(t *testing.T) {
+	t.Run("should copy the message without sharing its tags", func(t *testing.T) {
+		// Arrange
+		message := &models.Message{ID: uuid.New(), Text: "hello", Tags: []string{"news"}, Version: 2}
+
+		// Act
+		clone := message.Clone()
+		clone.Text = "changed"
+		clone.Tags[0] = "changed"
+
+		// Assert
+		assert.Equal(t, message.ID, clone.ID)
+		assert.Equal(t, int64(2), clone.Version)
+		assert.Equal(t, "hello", message.Text)
+		assert.Equal(t, []string{"news"}, message.Tags)
+	})
+}
@@ -0,0 +1,267 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/arielsrv/fxf/pkg/config"
+	"github.com/arielsrv/fxf/pkg/tenant"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/fx"
+)
+
+// Module exports the outbox relay.
+var Module = fx.Options(
+	fx.Provide(NewConfig),
+	fx.Invoke(RegisterRelay),
+)
+
+var (
+	eventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "fxf",
+		Subsystem: "outbox",
+		Name:      "events_total",
+		Help:      "Number of outbox delivery attempts, by event type and outcome.",
+	}, []string{"type", "outcome"})
+
+	lagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "outbox",
+		Name:      "lag_seconds",
+		Help:      "Age of the oldest due outbox event at the last poll.",
+	})
+
+	deliveryLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "fxf",
+		Subsystem: "outbox",
+		Name:      "delivery_latency_seconds",
+		Help:      "Time from the recording of an outbox event to its delivery.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 15),
+	})
+)
+
+// Outcomes of a delivery attempt.
+const (
+	outcomeDelivered    = "delivered"
+	outcomeRetried      = "retried"
+	outcomeDeadLettered = "dead_lettered"
+)
+
+// Config configures the outbox relay.
+type Config struct {
+	// PollInterval is how often the outbox is read. Zero disables the relay, and the
+	// events stay in the outbox.
+	PollInterval time.Duration
+	// BatchSize is the number of events read at once.
+	BatchSize int
+	// MaxAttempts is the number of failed deliveries after which an event is moved to
+	// the dead letters.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled after each failed
+	// attempt up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// NewConfig reads the relay configuration from FXF_OUTBOX_* environment variables.
+func NewConfig() Config {
+	return Config{
+		PollInterval:   config.Duration("FXF_OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:      config.Int("FXF_OUTBOX_BATCH_SIZE", 100),
+		MaxAttempts:    config.Int("FXF_OUTBOX_MAX_ATTEMPTS", 10),
+		InitialBackoff: config.Duration("FXF_OUTBOX_INITIAL_BACKOFF", time.Second),
+		MaxBackoff:     config.Duration("FXF_OUTBOX_MAX_BACKOFF", 5*time.Minute),
+	}
+}
+
+// Validate reports the first setting that would keep the relay from making progress:
+// a batch size or a number of attempts under one, or a backoff that is not positive.
+func (c Config) Validate() error {
+	switch {
+	case c.BatchSize < 1:
+		return errors.New("outbox batch size must be at least 1")
+	case c.MaxAttempts < 1:
+		return errors.New("outbox max attempts must be at least 1")
+	case c.InitialBackoff <= 0:
+		return errors.New("outbox initial backoff must be positive")
+	case c.MaxBackoff < c.InitialBackoff:
+		return errors.New("outbox max backoff must be at least the initial backoff")
+	}
+	return nil
+}
+
+// Dispatcher delivers an outbox event to its subscribers.
+type Dispatcher func(ctx context.Context, event *repository.OutboxEvent) error
+
+// Dispatch publishes the event to the handlers registered with MediatR, in the
+// context of its tenant, request and trace.
+func Dispatch(ctx context.Context, event *repository.OutboxEvent) error {
+	return events.Dispatch(eventContext(ctx, event), event.Type, event.Payload)
+}
+
+// eventContext returns ctx with the tenant, the request ID and the trace the event
+// was raised in.
+func eventContext(ctx context.Context, event *repository.OutboxEvent) context.Context {
+	return tenant.NewContext(event.Context(ctx), event.TenantID)
+}
+
+// Relay delivers the events of an outbox at least once: an event is acknowledged
+// only after its dispatch succeeded, so it is dispatched again if the process dies in
+// between. A failed event is retried with exponential backoff without holding back
+// the next ones, until it is moved to the dead letters.
+type Relay struct {
+	outbox   repository.IOutboxRepository
+	clock    clock.Clock
+	dispatch Dispatcher
+	cfg      Config
+}
+
+// NewRelay creates a Relay of outbox.
+func NewRelay(outbox repository.IOutboxRepository, clock clock.Clock, cfg Config, dispatch Dispatcher) *Relay {
+	return &Relay{outbox: outbox, clock: clock, dispatch: dispatch, cfg: cfg}
+}
+
+// RegisterRelay relays the outbox of the message repository every poll interval
+// while the application runs, and fails the startup if cfg is invalid. Repositories
+// without an outbox publish their events themselves, at most once.
+func RegisterRelay(lc fx.Lifecycle, repo repository.IMessageRepository, clock clock.Clock, cfg Config) error {
+	outbox, ok := repo.(repository.IOutboxRepository)
+	if !ok {
+		slog.Info("the message store has no outbox: events are published at most once",
+			slog.String("store", fmt.Sprintf("%T", repo)))
+		return nil
+	}
+	if cfg.PollInterval <= 0 {
+		return nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	relay := NewRelay(outbox, clock, cfg, Dispatch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				relay.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+	return nil
+}
+
+// Run relays the pending events every poll interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to relay outbox events", slog.String("err", err.Error()))
+			}
+		}
+	}
+}
+
+// RelayPending dispatches the due events, a batch at a time until none is left, and
+// returns how many were delivered.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		now := r.clock.Now()
+		pending, err := r.outbox.PendingEvents(ctx, now, r.cfg.BatchSize)
+		if err != nil {
+			return delivered, err
+		}
+		if len(pending) > 0 {
+			lagSeconds.Set(now.Sub(pending[0].CreatedAt).Seconds())
+		} else {
+			lagSeconds.Set(0)
+		}
+
+		for _, event := range pending {
+			ok, err := r.deliver(ctx, event)
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(pending) < r.cfg.BatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// deliver dispatches the event and acknowledges it, or records the failed attempt.
+// It reports whether the event was delivered.
+func (r *Relay) deliver(ctx context.Context, event *repository.OutboxEvent) (bool, error) {
+	ctx = eventContext(ctx, event)
+	dispatchErr := r.dispatch(ctx, event)
+	if dispatchErr == nil {
+		if err := r.outbox.AckEvent(ctx, event.Sequence); err != nil {
+			return false, err
+		}
+		eventsTotal.WithLabelValues(event.Type, outcomeDelivered).Inc()
+		deliveryLatency.Observe(r.clock.Now().Sub(event.CreatedAt).Seconds())
+		return true, nil
+	}
+
+	attrs := []any{
+		slog.String("event_id", event.ID.String()),
+		slog.String("type", event.Type),
+		slog.Int("attempts", event.Attempts+1),
+		slog.String("err", dispatchErr.Error()),
+	}
+	if event.Attempts+1 >= r.cfg.MaxAttempts {
+		if err := r.outbox.DeadLetterEvent(ctx, event.Sequence, dispatchErr.Error()); err != nil {
+			return false, err
+		}
+		eventsTotal.WithLabelValues(event.Type, outcomeDeadLettered).Inc()
+		slog.ErrorContext(ctx, "moved outbox event to the dead letters", attrs...)
+		return false, nil
+	}
+
+	nextAttemptAt := r.clock.Now().Add(r.backoff(event.Attempts + 1))
+	if err := r.outbox.RetryEvent(ctx, event.Sequence, nextAttemptAt, dispatchErr.Error()); err != nil {
+		return false, err
+	}
+	eventsTotal.WithLabelValues(event.Type, outcomeRetried).Inc()
+	slog.WarnContext(ctx, "failed to deliver outbox event", attrs...)
+	return false, nil
+}
+
+// backoff returns the delay before the next attempt after the given number of failed
+// attempts.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.InitialBackoff
+	for i := 1; i < attempts && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.cfg.MaxBackoff)
+}
@@ -0,0 +1,305 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/outbox"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/arielsrv/fxf/pkg/requestid"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx/fxtest"
+)
+
+var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var config = outbox.Config{
+	BatchSize:      2,
+	MaxAttempts:    4,
+	InitialBackoff: time.Second,
+	MaxBackoff:     3 * time.Second,
+}
+
+func newRepository(t *testing.T) *repository.BoltMessageRepository {
+	t.Helper()
+	repo, err := repository.NewBoltMessageRepository(repository.BoltConfig{
+		Path: filepath.Join(t.TempDir(), "messages.bolt"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// create creates messages with the given texts and returns their IDs.
+func create(t *testing.T, repo repository.IMessageRepository, texts ...string) []uuid.UUID {
+	t.Helper()
+	ids := make([]uuid.UUID, 0, len(texts))
+	for _, text := range texts {
+		message, err := repo.CreateMessage(t.Context(), &models.Message{Text: text, CreatedAt: now})
+		require.NoError(t, err)
+		ids = append(ids, message.ID)
+	}
+	return ids
+}
+
+// dispatcher records the IDs of the messages of the events it is given, and fails
+// for the messages in failing.
+type dispatcher struct {
+	failing    map[uuid.UUID]bool
+	dispatched []uuid.UUID
+}
+
+func (d *dispatcher) dispatch(_ context.Context, event *repository.OutboxEvent) error {
+	id := messageID(event)
+	d.dispatched = append(d.dispatched, id)
+	if d.failing[id] {
+		return errors.New("subscriber unavailable")
+	}
+	return nil
+}
+
+func messageID(event *repository.OutboxEvent) uuid.UUID {
+	var payload events.MessageCreatedEvent
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		panic(err)
+	}
+	return payload.ID
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	t.Run("should deliver every due event in batches and acknowledge it", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		ids := create(t, repo, "a", "b", "c")
+		d := &dispatcher{}
+		relay := outbox.NewRelay(repo, clock.NewManual(now), config, d.dispatch)
+
+		// Act
+		delivered, err := relay.RelayPending(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 3, delivered)
+		assert.Equal(t, ids, d.dispatched)
+		pending, err := repo.PendingEvents(t.Context(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+	})
+
+	t.Run("should retry a failed event with exponential backoff", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		ids := create(t, repo, "a")
+		manual := clock.NewManual(now)
+		d := &dispatcher{failing: map[uuid.UUID]bool{ids[0]: true}}
+		relay := outbox.NewRelay(repo, manual, outbox.Config{
+			BatchSize: 2, MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second,
+		}, d.dispatch)
+
+		// Act
+		var delays []time.Duration
+		for range 4 {
+			_, err := relay.RelayPending(t.Context())
+			require.NoError(t, err)
+
+			pending, err := repo.PendingEvents(t.Context(), now.Add(time.Hour), 10)
+			require.NoError(t, err)
+			require.Len(t, pending, 1)
+			delays = append(delays, pending[0].NextAttemptAt.Sub(manual.Now()))
+			manual.Set(pending[0].NextAttemptAt)
+		}
+
+		// Assert
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}, delays)
+		assert.Len(t, d.dispatched, 4)
+	})
+
+	t.Run("should not hold back the events after a failed one", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		ids := create(t, repo, "a", "b")
+		d := &dispatcher{failing: map[uuid.UUID]bool{ids[0]: true}}
+		relay := outbox.NewRelay(repo, clock.NewManual(now), config, d.dispatch)
+
+		// Act
+		delivered, err := relay.RelayPending(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, ids, d.dispatched)
+		pending, err := repo.PendingEvents(t.Context(), now.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, ids[0], messageID(pending[0]))
+	})
+
+	t.Run("should move an event to the dead letters after the last attempt", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		ids := create(t, repo, "a")
+		manual := clock.NewManual(now)
+		d := &dispatcher{failing: map[uuid.UUID]bool{ids[0]: true}}
+		relay := outbox.NewRelay(repo, manual, config, d.dispatch)
+
+		// Act
+		for range config.MaxAttempts {
+			_, err := relay.RelayPending(t.Context())
+			require.NoError(t, err)
+			manual.Advance(config.MaxBackoff)
+		}
+
+		// Assert
+		assert.Len(t, d.dispatched, config.MaxAttempts)
+		pending, err := repo.PendingEvents(t.Context(), manual.Now().Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		dead, err := repo.DeadLetters(t.Context(), 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, ids[0], messageID(dead[0]))
+		assert.Equal(t, config.MaxAttempts, dead[0].Attempts)
+		assert.Equal(t, "subscriber unavailable", dead[0].LastError)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("should accept a valid configuration", func(t *testing.T) {
+		// Act & Assert
+		require.NoError(t, config.Validate())
+	})
+
+	t.Run("should reject the settings that keep the relay from making progress", func(t *testing.T) {
+		for name, change := range map[string]func(*outbox.Config){
+			"batch size":      func(c *outbox.Config) { c.BatchSize = 0 },
+			"max attempts":    func(c *outbox.Config) { c.MaxAttempts = -1 },
+			"initial backoff": func(c *outbox.Config) { c.InitialBackoff = 0 },
+			"max backoff":     func(c *outbox.Config) { c.MaxBackoff = c.InitialBackoff / 2 },
+		} {
+			// Arrange
+			invalid := config
+			change(&invalid)
+
+			// Act
+			err := invalid.Validate()
+
+			// Assert
+			require.Error(t, err, name)
+		}
+	})
+}
+
+func TestRegisterRelay(t *testing.T) {
+	t.Run("should fail the startup with an invalid configuration", func(t *testing.T) {
+		// Arrange
+		invalid := config
+		invalid.PollInterval = time.Second
+		invalid.BatchSize = 0
+
+		// Act
+		err := outbox.RegisterRelay(fxtest.NewLifecycle(t), newRepository(t), clock.NewManual(now), invalid)
+
+		// Assert
+		require.Error(t, err)
+	})
+
+	t.Run("should ignore the configuration of a disabled relay", func(t *testing.T) {
+		// Act
+		err := outbox.RegisterRelay(fxtest.NewLifecycle(t), newRepository(t), clock.NewManual(now), outbox.Config{})
+
+		// Assert
+		require.NoError(t, err)
+	})
+}
+
+// contextRecorder records the tenant, request ID and trace of the context of the events it handles.
+type contextRecorder struct {
+	tenants    []string
+	requestIDs []string
+	traceIDs   []string
+}
+
+func (r *contextRecorder) Handle(ctx context.Context, _ *events.MessageCreatedEvent) error {
+	r.tenants = append(r.tenants, tenant.IDFromContext(ctx))
+	requestID, _ := requestid.FromContext(ctx)
+	r.requestIDs = append(r.requestIDs, requestID)
+	r.traceIDs = append(r.traceIDs, trace.SpanContextFromContext(ctx).TraceID().String())
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	t.Run("should publish the event in the context of its tenant", func(t *testing.T) {
+		// Arrange
+		recorder := &contextRecorder{}
+		mediatr.ClearNotificationRegistrations()
+		t.Cleanup(mediatr.ClearNotificationRegistrations)
+		require.NoError(t, mediatr.RegisterNotificationHandler[*events.MessageCreatedEvent](recorder))
+
+		repo := newRepository(t)
+		_, err := repo.CreateMessage(tenant.NewContext(t.Context(), "acme"), &models.Message{Text: "a", CreatedAt: now})
+		require.NoError(t, err)
+		pending, err := repo.PendingEvents(t.Context(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		// Act
+		err = outbox.Dispatch(t.Context(), pending[0])
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"acme"}, recorder.tenants)
+	})
+
+	t.Run("should publish the event with the request ID and the trace of the creation", func(t *testing.T) {
+		// Arrange
+		recorder := &contextRecorder{}
+		mediatr.ClearNotificationRegistrations()
+		t.Cleanup(mediatr.ClearNotificationRegistrations)
+		require.NoError(t, mediatr.RegisterNotificationHandler[*events.MessageCreatedEvent](recorder))
+
+		traceID := trace.TraceID{
+			0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36,
+		}
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			TraceFlags: trace.FlagsSampled,
+		})
+		ctx := trace.ContextWithSpanContext(requestid.NewContext(t.Context(), "req-1"), spanContext)
+
+		repo := newRepository(t)
+		_, err := repo.CreateMessage(ctx, &models.Message{Text: "a", CreatedAt: now})
+		require.NoError(t, err)
+		pending, err := repo.PendingEvents(t.Context(), now, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		// Act
+		err = outbox.Dispatch(t.Context(), pending[0])
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"req-1"}, recorder.requestIDs)
+		assert.Equal(t, []string{traceID.String()}, recorder.traceIDs)
+	})
+
+	t.Run("should reject an unknown event type", func(t *testing.T) {
+		// Act
+		err := outbox.Dispatch(t.Context(), &repository.OutboxEvent{Type: "message.unknown"})
+
+		// Assert
+		require.ErrorIs(t, err, events.ErrUnknownEvent)
+	})
+}
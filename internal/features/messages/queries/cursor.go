@@ -0,0 +1,43 @@
+package queries
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+
+	"github.com/google/uuid"
+)
+
+// cursor is the decoded form of the opaque page cursors: the key of the message at
+// the page boundary, the direction to read from it and the sort order it belongs to.
+type cursor struct {
+	CreatedAt time.Time `json:"t"`
+	Sort      string    `json:"s"`
+	ID        uuid.UUID `json:"i"`
+	Prev      bool      `json:"p,omitempty"`
+}
+
+func newCursor(key repository.MessageKey, sort string, prev bool) string {
+	data, _ := json.Marshal(cursor{CreatedAt: key.CreatedAt, ID: key.ID, Sort: sort, Prev: prev})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", models.ErrInvalidQuery)
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil {
+		return nil, fmt.Errorf("%w: malformed cursor", models.ErrInvalidQuery)
+	}
+	return &c, nil
+}
+
+func (c *cursor) key() *repository.MessageKey {
+	return &repository.MessageKey{CreatedAt: c.CreatedAt, ID: c.ID}
+}
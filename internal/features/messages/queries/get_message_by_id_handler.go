@@ -2,10 +2,13 @@ package queries
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
 	"github.com/arielsrv/fxf/internal/features/messages/repository"
 	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/arielsrv/fxf/pkg/auth"
 	"github.com/mehdihadeli/go-mediatr"
 	"go.uber.org/fx"
 )
@@ -13,7 +16,11 @@ import (
 // Module exports the query handler functionality.
 var Module = fx.Options(
 	fx.Provide(NewGetMessageByIDQueryHandler),
+	fx.Provide(NewGetMessagesByIDsQueryHandler),
+	fx.Provide(NewListMessagesQueryHandler),
 	fx.Invoke(registerGetMessageByIDQueryHandler),
+	fx.Invoke(registerGetMessagesByIDsQueryHandler),
+	fx.Invoke(registerListMessagesQueryHandler),
 )
 
 // GetMessageByIDQueryHandler is the handler for GetMessageByIDQuery.
@@ -26,7 +33,8 @@ func NewGetMessageByIDQueryHandler(repo repository.IMessageRepository) interface
 	return &GetMessageByIDQueryHandler{repo: repo}
 }
 
-// Handle handles the GetMessageByIDQuery.
+// Handle handles the GetMessageByIDQuery. Soft deleted messages are not found, unless
+// an admin asks for them.
 func (h *GetMessageByIDQueryHandler) Handle(
 	ctx context.Context,
 	query *dtos.GetMessageByIDQuery,
@@ -35,11 +43,33 @@ func (h *GetMessageByIDQueryHandler) Handle(
 	if err != nil {
 		return nil, err
 	}
+	if message.IsDeleted() && !(query.IncludeDeleted && isAdmin(ctx)) {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, query.ID)
+	}
+
+	return newGetMessageByIDQueryResponse(message), nil
+}
+
+func newGetMessageByIDQueryResponse(message *models.Message) *dtos.GetMessageByIDQueryResponse {
+	response := &dtos.GetMessageByIDQueryResponse{
+		ID:        message.ID,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		Tags:      message.Tags,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		Version:   message.Version,
+	}
+	if message.IsDeleted() {
+		deletedAt := message.DeletedAt
+		response.DeletedAt = &deletedAt
+	}
+	return response
+}
 
-	return &dtos.GetMessageByIDQueryResponse{
-		ID:   message.ID,
-		Text: message.Text,
-	}, nil
+func isAdmin(ctx context.Context) bool {
+	principal, ok := auth.FromContext(ctx)
+	return ok && principal.HasScope(dtos.ScopeMessagesAdmin)
 }
 
 // registerGetMessageByIDQueryHandler registers the query handler with MediatR.
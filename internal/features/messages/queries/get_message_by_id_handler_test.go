@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/dtos"
 	"github.com/arielsrv/fxf/internal/features/messages/models"
 	"github.com/arielsrv/fxf/internal/features/messages/queries"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/auth"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -27,6 +30,17 @@ func (m *MockMessageRepository) CreateMessage(ctx context.Context, message *mode
 	return args.Get(0).(*models.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	args := m.Called(ctx, messages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
 func (m *MockMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -35,6 +49,42 @@ func (m *MockMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID
 	return args.Get(0).(*models.Message), args.Error(1)
 }
 
+func (m *MockMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	args := m.Called(ctx, message, expectedVersion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
+func (m *MockMessageRepository) ListMessages(
+	ctx context.Context,
+	opts repository.ListMessagesOptions,
+) (*repository.ListMessagesResult, error) {
+	args := m.Called(ctx, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repository.ListMessagesResult), args.Error(1)
+}
+
 func TestGetMessageByIDQueryHandler_Handle(t *testing.T) {
 	t.Run("should get message successfully", func(t *testing.T) {
 		// Arrange
@@ -47,9 +97,13 @@ func TestGetMessageByIDQueryHandler_Handle(t *testing.T) {
 			ID: messageID,
 		}
 
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
 		expectedMessage := &models.Message{
-			ID:   messageID,
-			Text: "test message",
+			ID:        messageID,
+			Text:      "test message",
+			AuthorID:  "user-1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt.Add(time.Minute),
 		}
 
 		mockRepo.On("GetMessageByID", ctx, messageID).Return(expectedMessage, nil)
@@ -62,6 +116,9 @@ func TestGetMessageByIDQueryHandler_Handle(t *testing.T) {
 		require.NotNil(t, result)
 		assert.Equal(t, expectedMessage.ID, result.ID)
 		assert.Equal(t, expectedMessage.Text, result.Text)
+		assert.Equal(t, expectedMessage.AuthorID, result.AuthorID)
+		assert.Equal(t, expectedMessage.CreatedAt, result.CreatedAt)
+		assert.Equal(t, expectedMessage.UpdatedAt, result.UpdatedAt)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -127,3 +184,45 @@ func TestNewGetMessageByIDQueryHandler(t *testing.T) {
 		assert.IsType(t, &queries.GetMessageByIDQueryHandler{}, handler)
 	})
 }
+
+func TestGetMessageByIDQueryHandler_Deleted(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	deleted := &models.Message{ID: uuid.New(), Text: "gone", DeletedAt: deletedAt}
+
+	t.Run("should not find deleted messages", func(t *testing.T) {
+		// Arrange
+		ctx := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+		mockRepo := new(MockMessageRepository)
+		handler := queries.NewGetMessageByIDQueryHandler(mockRepo)
+		mockRepo.On("GetMessageByID", ctx, deleted.ID).Return(deleted, nil)
+
+		// Act
+		result, err := handler.Handle(ctx, &dtos.GetMessageByIDQuery{ID: deleted.ID, IncludeDeleted: true})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should return deleted messages to admins who ask for them", func(t *testing.T) {
+		// Arrange
+		ctx := auth.NewContext(context.Background(), &auth.Principal{
+			Subject: "admin-1",
+			Scopes:  []string{dtos.ScopeMessagesAdmin},
+		})
+		mockRepo := new(MockMessageRepository)
+		handler := queries.NewGetMessageByIDQueryHandler(mockRepo)
+		mockRepo.On("GetMessageByID", ctx, deleted.ID).Return(deleted, nil)
+
+		// Act
+		hidden, errHidden := handler.Handle(ctx, &dtos.GetMessageByIDQuery{ID: deleted.ID})
+		result, err := handler.Handle(ctx, &dtos.GetMessageByIDQuery{ID: deleted.ID, IncludeDeleted: true})
+
+		// Assert
+		require.ErrorIs(t, errHidden, models.ErrMessageNotFound)
+		assert.Nil(t, hidden)
+		require.NoError(t, err)
+		require.NotNil(t, result.DeletedAt)
+		assert.Equal(t, deletedAt, *result.DeletedAt)
+	})
+}
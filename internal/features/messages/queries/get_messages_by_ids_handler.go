@@ -0,0 +1,74 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// GetMessagesByIDsQueryHandler is the handler for GetMessagesByIDsQuery.
+type GetMessagesByIDsQueryHandler struct {
+	repo repository.IMessageRepository
+}
+
+// NewGetMessagesByIDsQueryHandler creates a new GetMessagesByIDsQueryHandler.
+func NewGetMessagesByIDsQueryHandler(repo repository.IMessageRepository) interfaces.IGetMessagesByIDsQueryHandler {
+	return &GetMessagesByIDsQueryHandler{repo: repo}
+}
+
+// Handle handles the GetMessagesByIDsQuery. Duplicate IDs are answered once, and soft
+// deleted messages are reported missing, unless an admin asks for them.
+func (h *GetMessagesByIDsQueryHandler) Handle(
+	ctx context.Context,
+	query *dtos.GetMessagesByIDsQuery,
+) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	if len(query.IDs) == 0 || len(query.IDs) > dtos.MaxBatchSize {
+		return nil, fmt.Errorf("%w: between 1 and %d IDs are required", models.ErrInvalidQuery, dtos.MaxBatchSize)
+	}
+
+	ids := make([]uuid.UUID, 0, len(query.IDs))
+	seen := make(map[uuid.UUID]bool, len(query.IDs))
+	for _, id := range query.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	messages, err := h.repo.GetMessagesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	includeDeleted := query.IncludeDeleted && isAdmin(ctx)
+	found := make(map[uuid.UUID]*models.Message, len(messages))
+	for _, message := range messages {
+		if !message.IsDeleted() || includeDeleted {
+			found[message.ID] = message
+		}
+	}
+
+	response := &dtos.GetMessagesByIDsQueryResponse{
+		Items:   make([]*dtos.GetMessageByIDQueryResponse, 0, len(found)),
+		Missing: make([]uuid.UUID, 0, len(ids)-len(found)),
+	}
+	for _, id := range ids {
+		if message, ok := found[id]; ok {
+			response.Items = append(response.Items, newGetMessageByIDQueryResponse(message))
+		} else {
+			response.Missing = append(response.Missing, id)
+		}
+	}
+	return response, nil
+}
+
+// registerGetMessagesByIDsQueryHandler registers the query handler with MediatR.
+func registerGetMessagesByIDsQueryHandler(handler interfaces.IGetMessagesByIDsQueryHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.GetMessagesByIDsQuery, *dtos.GetMessagesByIDsQueryResponse](handler)
+}
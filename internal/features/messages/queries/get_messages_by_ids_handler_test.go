@@ -0,0 +1,85 @@
+package queries_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/queries"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMessagesByIDsQueryHandler_Handle(t *testing.T) {
+	t.Run("should return the found messages and the missing IDs", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		mockRepo := new(MockMessageRepository)
+		handler := queries.NewGetMessagesByIDsQueryHandler(mockRepo)
+
+		found, missing := uuid.New(), uuid.New()
+		mockRepo.On("GetMessagesByIDs", ctx, []uuid.UUID{missing, found}).
+			Return([]*models.Message{{ID: found, Text: "hello", Version: 1}}, nil)
+
+		// Act
+		result, err := handler.Handle(ctx, &dtos.GetMessagesByIDsQuery{IDs: []uuid.UUID{missing, found, missing}})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, result.Items, 1)
+		assert.Equal(t, found, result.Items[0].ID)
+		assert.Equal(t, "hello", result.Items[0].Text)
+		assert.Equal(t, []uuid.UUID{missing}, result.Missing)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should report deleted messages missing unless an admin asks for them", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := queries.NewGetMessagesByIDsQueryHandler(mockRepo)
+
+		id := uuid.New()
+		query := &dtos.GetMessagesByIDsQuery{IDs: []uuid.UUID{id}, IncludeDeleted: true}
+		admin := auth.NewContext(context.Background(), &auth.Principal{
+			Subject: "admin-1",
+			Scopes:  []string{dtos.ScopeMessagesAdmin},
+		})
+		user := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+		deleted := []*models.Message{{ID: id, DeletedAt: time.Now()}}
+		mockRepo.On("GetMessagesByIDs", admin, []uuid.UUID{id}).Return(deleted, nil)
+		mockRepo.On("GetMessagesByIDs", user, []uuid.UUID{id}).Return(deleted, nil)
+
+		// Act
+		forAdmin, errAdmin := handler.Handle(admin, query)
+		forUser, errUser := handler.Handle(user, query)
+
+		// Assert
+		require.NoError(t, errAdmin)
+		require.NoError(t, errUser)
+		require.Len(t, forAdmin.Items, 1)
+		assert.NotNil(t, forAdmin.Items[0].DeletedAt)
+		assert.Empty(t, forUser.Items)
+		assert.Equal(t, []uuid.UUID{id}, forUser.Missing)
+	})
+
+	t.Run("should reject empty and oversized batches", func(t *testing.T) {
+		// Arrange
+		handler := queries.NewGetMessagesByIDsQueryHandler(new(MockMessageRepository))
+		oversized := make([]uuid.UUID, dtos.MaxBatchSize+1)
+		for i := range oversized {
+			oversized[i] = uuid.New()
+		}
+
+		// Act
+		_, errEmpty := handler.Handle(context.Background(), &dtos.GetMessagesByIDsQuery{})
+		_, errOversized := handler.Handle(context.Background(), &dtos.GetMessagesByIDsQuery{IDs: oversized})
+
+		// Assert
+		require.ErrorIs(t, errEmpty, models.ErrInvalidQuery)
+		require.ErrorIs(t, errOversized, models.ErrInvalidQuery)
+	})
+}
@@ -0,0 +1,144 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/interfaces"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// ListMessagesQueryHandler is the handler for ListMessagesQuery.
+type ListMessagesQueryHandler struct {
+	repo repository.IMessageRepository
+}
+
+// NewListMessagesQueryHandler creates a new ListMessagesQueryHandler.
+func NewListMessagesQueryHandler(repo repository.IMessageRepository) interfaces.IListMessagesQueryHandler {
+	return &ListMessagesQueryHandler{repo: repo}
+}
+
+// Handle handles the ListMessagesQuery. Pages are delimited by cursors holding the
+// key of their boundary message, so that concurrent inserts neither shift nor repeat
+// the messages of the following pages.
+func (h *ListMessagesQueryHandler) Handle(
+	ctx context.Context,
+	query *dtos.ListMessagesQuery,
+) (*dtos.ListMessagesQueryResponse, error) {
+	limit := query.Limit
+	if limit == 0 {
+		limit = dtos.DefaultPageSize
+	}
+	if limit < 0 || limit > dtos.MaxPageSize {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", models.ErrInvalidQuery, dtos.MaxPageSize)
+	}
+
+	sort := query.Sort
+	if sort == "" {
+		sort = dtos.SortCreatedAtDesc
+	}
+	if sort != dtos.SortCreatedAtAsc && sort != dtos.SortCreatedAtDesc {
+		return nil, fmt.Errorf("%w: unknown sort %q", models.ErrInvalidQuery, sort)
+	}
+
+	opts := repository.ListMessagesOptions{
+		Limit:      limit,
+		Descending: sort == dtos.SortCreatedAtDesc,
+		Filter: repository.MessageFilter{
+			AuthorID:       query.AuthorID,
+			CreatedAfter:   query.CreatedAfter,
+			CreatedBefore:  query.CreatedBefore,
+			Tags:           query.Tags,
+			IncludeDeleted: query.IncludeDeleted && isAdmin(ctx),
+		},
+	}
+	if query.Cursor != "" {
+		c, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != sort {
+			return nil, fmt.Errorf("%w: the cursor belongs to another sort order", models.ErrInvalidQuery)
+		}
+		if c.Prev {
+			opts.Before = c.key()
+		} else {
+			opts.After = c.key()
+		}
+	}
+
+	result, err := h.repo.ListMessages(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.ListMessagesQueryResponse{Items: make([]*dtos.ListMessagesQueryItem, 0, len(result.Messages))}
+	for _, message := range result.Messages {
+		response.Items = append(response.Items, newListMessagesQueryItem(message))
+	}
+	response.Next, response.Prev = pageCursors(opts, result, sort)
+
+	return response, nil
+}
+
+// pageCursors returns the cursors of the pages around a result. A page read after a
+// cursor always has a previous page, and one read before a cursor a next page.
+func pageCursors(
+	opts repository.ListMessagesOptions,
+	result *repository.ListMessagesResult,
+	sort string,
+) (string, string) {
+	var next, prev string
+	if len(result.Messages) == 0 {
+		if opts.After != nil {
+			prev = newCursor(*opts.After, sort, true)
+		}
+		if opts.Before != nil {
+			next = newCursor(*opts.Before, sort, false)
+		}
+		return next, prev
+	}
+
+	first := repository.KeyOf(result.Messages[0])
+	last := repository.KeyOf(result.Messages[len(result.Messages)-1])
+	if opts.Before != nil {
+		next = newCursor(last, sort, false)
+		if result.HasMore {
+			prev = newCursor(first, sort, true)
+		}
+		return next, prev
+	}
+
+	if result.HasMore {
+		next = newCursor(last, sort, false)
+	}
+	if opts.After != nil {
+		prev = newCursor(first, sort, true)
+	}
+	return next, prev
+}
+
+func newListMessagesQueryItem(message *models.Message) *dtos.ListMessagesQueryItem {
+	item := &dtos.ListMessagesQueryItem{
+		ID:        message.ID,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		Tags:      message.Tags,
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		Version:   message.Version,
+	}
+	if message.IsDeleted() {
+		deletedAt := message.DeletedAt
+		item.DeletedAt = &deletedAt
+	}
+	return item
+}
+
+// registerListMessagesQueryHandler registers the query handler with MediatR.
+func registerListMessagesQueryHandler(handler interfaces.IListMessagesQueryHandler) error {
+	return mediatr.RegisterRequestHandler[*dtos.ListMessagesQuery, *dtos.ListMessagesQueryResponse](handler)
+}
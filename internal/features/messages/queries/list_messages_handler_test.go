@@ -0,0 +1,146 @@
+package queries_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/queries"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var listBase = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func createAt(t *testing.T, repo repository.IMessageRepository, text string, minute int) {
+	t.Helper()
+	_, err := repo.CreateMessage(t.Context(), &models.Message{
+		Text:      text,
+		CreatedAt: listBase.Add(time.Duration(minute) * time.Minute),
+	})
+	require.NoError(t, err)
+}
+
+func pageTexts(response *dtos.ListMessagesQueryResponse) string {
+	result := ""
+	for _, item := range response.Items {
+		result += item.Text
+	}
+	return result
+}
+
+func TestListMessagesQueryHandler_Handle(t *testing.T) {
+	t.Run("should page through the messages with next and prev cursors", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		handler := queries.NewListMessagesQueryHandler(repo)
+		for i, text := range []string{"a", "b", "c", "d", "e"} {
+			createAt(t, repo, text, i)
+		}
+		ctx := t.Context()
+
+		// Act
+		first, err := handler.Handle(ctx, &dtos.ListMessagesQuery{Limit: 2})
+		require.NoError(t, err)
+		second, err := handler.Handle(ctx, &dtos.ListMessagesQuery{Limit: 2, Cursor: first.Next})
+		require.NoError(t, err)
+		third, err := handler.Handle(ctx, &dtos.ListMessagesQuery{Limit: 2, Cursor: second.Next})
+		require.NoError(t, err)
+		back, err := handler.Handle(ctx, &dtos.ListMessagesQuery{Limit: 2, Cursor: second.Prev})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "ed", pageTexts(first))
+		assert.Empty(t, first.Prev)
+		assert.Equal(t, "cb", pageTexts(second))
+		assert.Equal(t, "a", pageTexts(third))
+		assert.Empty(t, third.Next)
+		assert.Equal(t, "ed", pageTexts(back))
+		assert.Empty(t, back.Prev)
+		assert.NotEmpty(t, back.Next)
+	})
+
+	t.Run("should stay stable under concurrent inserts", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		handler := queries.NewListMessagesQueryHandler(repo)
+		for i, text := range []string{"a", "b", "c", "d"} {
+			createAt(t, repo, text, i)
+		}
+		ctx := t.Context()
+		first, err := handler.Handle(ctx, &dtos.ListMessagesQuery{Limit: 2, Sort: dtos.SortCreatedAtAsc})
+		require.NoError(t, err)
+
+		// Act
+		createAt(t, repo, "x", -1)
+		createAt(t, repo, "y", 10)
+		second, err := handler.Handle(
+			ctx,
+			&dtos.ListMessagesQuery{Limit: 2, Sort: dtos.SortCreatedAtAsc, Cursor: first.Next},
+		)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "ab", pageTexts(first))
+		assert.Equal(t, "cd", pageTexts(second))
+		assert.NotEmpty(t, second.Next)
+	})
+
+	t.Run("should reject invalid limits, sorts and cursors", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		handler := queries.NewListMessagesQueryHandler(repo)
+		createAt(t, repo, "a", 0)
+		createAt(t, repo, "b", 1)
+		ctx := t.Context()
+		desc, err := handler.Handle(ctx, &dtos.ListMessagesQuery{Limit: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, desc.Next)
+
+		for _, query := range []*dtos.ListMessagesQuery{
+			{Limit: dtos.MaxPageSize + 1},
+			{Limit: -1},
+			{Sort: "text"},
+			{Cursor: "not a cursor"},
+			{Cursor: desc.Next, Sort: dtos.SortCreatedAtAsc},
+		} {
+			// Act
+			_, err := handler.Handle(ctx, query)
+
+			// Assert
+			require.ErrorIs(t, err, models.ErrInvalidQuery)
+		}
+	})
+
+	t.Run("should only list deleted messages to admins", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockMessageRepository)
+		handler := queries.NewListMessagesQueryHandler(mockRepo)
+		admin := auth.NewContext(context.Background(), &auth.Principal{
+			Subject: "admin-1",
+			Scopes:  []string{dtos.ScopeMessagesAdmin},
+		})
+		user := auth.NewContext(context.Background(), &auth.Principal{Subject: "user-1"})
+
+		mockRepo.On("ListMessages", admin, mock.MatchedBy(func(opts repository.ListMessagesOptions) bool {
+			return opts.Filter.IncludeDeleted
+		})).Return(&repository.ListMessagesResult{}, nil)
+		mockRepo.On("ListMessages", user, mock.MatchedBy(func(opts repository.ListMessagesOptions) bool {
+			return !opts.Filter.IncludeDeleted && opts.Limit == dtos.DefaultPageSize && opts.Descending
+		})).Return(&repository.ListMessagesResult{}, nil)
+
+		// Act
+		_, errAdmin := handler.Handle(admin, &dtos.ListMessagesQuery{IncludeDeleted: true})
+		_, errUser := handler.Handle(user, &dtos.ListMessagesQuery{IncludeDeleted: true})
+
+		// Assert
+		require.NoError(t, errAdmin)
+		require.NoError(t, errUser)
+		mockRepo.AssertExpectations(t)
+	})
+}
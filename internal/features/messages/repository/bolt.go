@@ -0,0 +1,515 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/tenant"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltConfig configures the bbolt message store.
+type BoltConfig struct {
+	// Path is the database file. Its directory is created if needed.
+	Path string
+	// OpenTimeout is how long to wait for the file lock held by another process.
+	OpenTimeout time.Duration
+}
+
+// Bucket names of the bbolt message store. The root bucket holds a bucket per
+// tenant, which holds the messages by ID and an index of their listing keys. The
+// outbox and dead letter buckets hold the events of every tenant by sequence.
+var (
+	tenantsBucket     = []byte("tenants")
+	messagesBucket    = []byte("messages")
+	createdBucket     = []byte("by_created")
+	outboxBucket      = []byte("outbox")
+	deadLettersBucket = []byte("outbox_dead_letters")
+)
+
+// boltMessage is the stored form of a message.
+type boltMessage struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+	Text      string    `json:"text"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Version   int64     `json:"version"`
+}
+
+// BoltMessageRepository is an IMessageRepository that stores the messages in an
+// embedded bbolt file, for single-node deployments without a database server. It is
+// also an IOutboxRepository.
+type BoltMessageRepository struct {
+	boltOutbox
+}
+
+// NewBoltMessageRepository opens the database file of cfg, creating it if needed.
+func NewBoltMessageRepository(cfg BoltConfig) (*BoltMessageRepository, error) {
+	if dir := filepath.Dir(cfg.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: cfg.OpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", cfg.Path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{tenantsBucket, outboxBucket, deadLettersBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize %s: %w", cfg.Path, err)
+	}
+	return &BoltMessageRepository{boltOutbox: boltOutbox{db: db}}, nil
+}
+
+// Close closes the database file.
+func (r *BoltMessageRepository) Close() error {
+	return r.db.Close()
+}
+
+// Backup writes a consistent copy of the database to w while it keeps serving
+// reads and writes, and returns the number of bytes written.
+func (r *BoltMessageRepository) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	var written int64
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		written, err = tx.WriteTo(&contextWriter{ctx: ctx, w: w})
+		return err
+	})
+	return written, err
+}
+
+// CreateMessage adds a new message to the bucket of the context tenant.
+func (r *BoltMessageRepository) CreateMessage(ctx context.Context, message *models.Message) (*models.Message, error) {
+	created := message.Clone()
+	err := r.update(ctx, func(b *tenantBucket) error {
+		return b.create(ctx, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateMessages adds the messages, and their events to the outbox, in a single
+// transaction.
+func (r *BoltMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	created := make([]*models.Message, 0, len(messages))
+	for _, message := range messages {
+		created = append(created, message.Clone())
+	}
+	err := r.update(ctx, func(b *tenantBucket) error {
+		for _, message := range created {
+			if err := b.create(ctx, message); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetMessageByID retrieves a message of the context tenant by its ID.
+func (r *BoltMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	var message *models.Message
+	err := r.view(ctx, func(b *tenantBucket) error {
+		var err error
+		message, err = b.get(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+	}
+	return message, nil
+}
+
+// GetMessagesByIDs retrieves the messages of the context tenant with the given IDs
+// in a single read transaction.
+func (r *BoltMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	messages := make([]*models.Message, 0, len(ids))
+	err := r.view(ctx, func(b *tenantBucket) error {
+		for _, id := range ids {
+			message, err := b.get(id)
+			if err != nil {
+				return err
+			}
+			if message != nil {
+				messages = append(messages, message)
+			}
+		}
+		return nil
+	})
+	return messages, err
+}
+
+// UpdateMessage replaces the message if it is still at expectedVersion. The creation
+// time of a message cannot change.
+func (r *BoltMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	updated := message.Clone()
+	err := r.update(ctx, func(b *tenantBucket) error {
+		stored, err := b.get(message.ID)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, message.ID)
+		}
+		if stored.Version != expectedVersion {
+			return fmt.Errorf("%w: message %s is at version %d, not %d",
+				models.ErrVersionConflict, message.ID, stored.Version, expectedVersion)
+		}
+
+		updated.TenantID = b.tenantID
+		updated.CreatedAt = stored.CreatedAt
+		updated.Version = expectedVersion + 1
+		return b.put(updated)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// PurgeDeletedMessages deletes the messages of every tenant soft deleted before
+// deletedBefore.
+func (r *BoltMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	purged := 0
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tenantsBucket).ForEachBucket(func(name []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			b := openTenantBucket(tx, string(name))
+
+			var expired []*models.Message
+			err := b.messages.ForEach(func(key, _ []byte) error {
+				id, err := uuid.FromBytes(key)
+				if err != nil {
+					return err
+				}
+				message, err := b.get(id)
+				if err != nil {
+					return err
+				}
+				if message.IsDeleted() && message.DeletedAt.Before(deletedBefore) {
+					expired = append(expired, message)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			// Keys are deleted after the iteration, which must not modify the bucket.
+			for _, message := range expired {
+				if err := b.remove(message); err != nil {
+					return err
+				}
+			}
+			purged += len(expired)
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// ListMessages walks the creation time index of the tenant bucket from the page
+// boundary, skipping the messages rejected by the filter.
+func (r *BoltMessageRepository) ListMessages(
+	ctx context.Context,
+	opts ListMessagesOptions,
+) (*ListMessagesResult, error) {
+	result := &ListMessagesResult{}
+	if opts.Limit <= 0 {
+		return result, nil
+	}
+
+	boundary := opts.After
+	if opts.Before != nil {
+		boundary = opts.Before
+	}
+	forward := opts.Descending == (opts.Before != nil)
+
+	err := r.view(ctx, func(b *tenantBucket) error {
+		if b.index == nil {
+			return nil
+		}
+		cursor := b.index.Cursor()
+
+		var key []byte
+		switch {
+		case boundary == nil && forward:
+			key, _ = cursor.First()
+		case boundary == nil:
+			key, _ = cursor.Last()
+		default:
+			seek := indexKey(*boundary)
+			key, _ = cursor.Seek(seek)
+			if forward && bytes.Equal(key, seek) {
+				key, _ = cursor.Next()
+			}
+			if !forward {
+				// Seek lands on the first key at or after the boundary, or past the end.
+				if key == nil {
+					key, _ = cursor.Last()
+				} else {
+					key, _ = cursor.Prev()
+				}
+			}
+		}
+
+		for ; key != nil; key = step(cursor, forward) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			id, err := uuid.FromBytes(key[len(key)-16:])
+			if err != nil {
+				return err
+			}
+			message, err := b.get(id)
+			if err != nil {
+				return err
+			}
+			if message == nil || !opts.Filter.Matches(message) {
+				continue
+			}
+			if len(result.Messages) == opts.Limit {
+				result.HasMore = true
+				break
+			}
+			result.Messages = append(result.Messages, message)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Before != nil {
+		// The page was read backward from its boundary: restore the listing order.
+		slices.Reverse(result.Messages)
+	}
+	return result, nil
+}
+
+func step(cursor *bolt.Cursor, forward bool) []byte {
+	if forward {
+		key, _ := cursor.Next()
+		return key
+	}
+	key, _ := cursor.Prev()
+	return key
+}
+
+// view runs fn in a read transaction on the bucket of the context tenant. The
+// buckets of fn are nil when the tenant has no message yet.
+func (r *BoltMessageRepository) view(ctx context.Context, fn func(*tenantBucket) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.db.View(func(tx *bolt.Tx) error {
+		return fn(openTenantBucket(tx, tenant.IDFromContext(ctx)))
+	})
+}
+
+// update runs fn in a write transaction on the bucket of the context tenant,
+// creating it if needed.
+func (r *BoltMessageRepository) update(ctx context.Context, fn func(*tenantBucket) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		b, err := createTenantBucket(tx, tenant.IDFromContext(ctx))
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+// tenantBucket holds the buckets of a tenant within a transaction.
+type tenantBucket struct {
+	messages *bolt.Bucket
+	index    *bolt.Bucket
+	// outbox is only set in write transactions.
+	outbox   *bolt.Bucket
+	tenantID string
+}
+
+func openTenantBucket(tx *bolt.Tx, tenantID string) *tenantBucket {
+	b := &tenantBucket{tenantID: tenantID}
+	if root := tx.Bucket(tenantsBucket).Bucket([]byte(tenantID)); root != nil {
+		b.messages = root.Bucket(messagesBucket)
+		b.index = root.Bucket(createdBucket)
+	}
+	return b
+}
+
+func createTenantBucket(tx *bolt.Tx, tenantID string) (*tenantBucket, error) {
+	root, err := tx.Bucket(tenantsBucket).CreateBucketIfNotExists([]byte(tenantID))
+	if err != nil {
+		return nil, err
+	}
+	b := &tenantBucket{tenantID: tenantID, outbox: tx.Bucket(outboxBucket)}
+	if b.messages, err = root.CreateBucketIfNotExists(messagesBucket); err != nil {
+		return nil, err
+	}
+	if b.index, err = root.CreateBucketIfNotExists(createdBucket); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// get returns the message with the ID, or nil when there is none.
+func (b *tenantBucket) get(id uuid.UUID) (*models.Message, error) {
+	if b.messages == nil {
+		return nil, nil
+	}
+	value := b.messages.Get(id[:])
+	if value == nil {
+		return nil, nil
+	}
+	message, err := decodeBoltMessage(value)
+	if err != nil {
+		return nil, err
+	}
+	message.ID = id
+	message.TenantID = b.tenantID
+	return message, nil
+}
+
+// create assigns the ID, tenant and first version of a new message and stores it
+// with its event.
+func (b *tenantBucket) create(ctx context.Context, message *models.Message) error {
+	if message.ID == uuid.Nil {
+		message.ID = uuid.New()
+	}
+	message.TenantID = b.tenantID
+	message.Version = 1
+	if stored, err := b.get(message.ID); err != nil {
+		return err
+	} else if stored != nil {
+		return errMessageExists(message.ID)
+	}
+	if err := b.put(message); err != nil {
+		return err
+	}
+
+	event, err := messageCreatedEvent(ctx, message)
+	if err != nil {
+		return err
+	}
+	return appendBoltEvent(b.outbox, event)
+}
+
+// put stores message and moves its index entry if its creation time changed.
+func (b *tenantBucket) put(message *models.Message) error {
+	if stored, err := b.get(message.ID); err != nil {
+		return err
+	} else if stored != nil {
+		if err := b.index.Delete(indexKey(KeyOf(stored))); err != nil {
+			return err
+		}
+	}
+
+	value, err := encodeBoltMessage(message)
+	if err != nil {
+		return err
+	}
+	if err := b.messages.Put(message.ID[:], value); err != nil {
+		return err
+	}
+	return b.index.Put(indexKey(KeyOf(message)), nil)
+}
+
+func (b *tenantBucket) remove(message *models.Message) error {
+	if err := b.messages.Delete(message.ID[:]); err != nil {
+		return err
+	}
+	return b.index.Delete(indexKey(KeyOf(message)))
+}
+
+// indexKey encodes key so that the byte order of the encoded keys is the listing
+// order: the fixed-width creation time followed by the ID.
+func indexKey(key MessageKey) []byte {
+	return append([]byte(formatTime(key.CreatedAt)), key.ID[:]...)
+}
+
+func encodeBoltMessage(message *models.Message) ([]byte, error) {
+	return json.Marshal(boltMessage{
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		DeletedAt: message.DeletedAt,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		Tags:      message.Tags,
+		Version:   message.Version,
+	})
+}
+
+func decodeBoltMessage(value []byte) (*models.Message, error) {
+	var stored boltMessage
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return nil, fmt.Errorf("invalid stored message: %w", err)
+	}
+	return &models.Message{
+		CreatedAt: stored.CreatedAt,
+		UpdatedAt: stored.UpdatedAt,
+		DeletedAt: stored.DeletedAt,
+		Text:      stored.Text,
+		AuthorID:  stored.AuthorID,
+		Tags:      stored.Tags,
+		Version:   stored.Version,
+	}, nil
+}
+
+// contextWriter stops a long write when its context is done.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (w *contextWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltOutboxEvent is the stored form of an outbox event, keyed by its sequence.
+type boltOutboxEvent struct {
+	CreatedAt     time.Time `json:"created_at"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	TenantID      string    `json:"tenant_id"`
+	Type          string    `json:"type"`
+	LastError     string    `json:"last_error,omitempty"`
+	RequestID     string    `json:"request_id,omitempty"`
+	TraceParent   string    `json:"traceparent,omitempty"`
+	Payload       []byte    `json:"payload"`
+	Attempts      int       `json:"attempts,omitempty"`
+	ID            uuid.UUID `json:"id"`
+}
+
+// boltOutbox is the IOutboxRepository of the bbolt stores, kept in the outbox and dead
+// letters buckets of their file.
+type boltOutbox struct {
+	db *bolt.DB
+}
+
+// PendingEvents scans the outbox for up to limit events due at now.
+func (r *boltOutbox) PendingEvents(ctx context.Context, now time.Time, limit int) ([]*OutboxEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var events []*OutboxEvent
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return scanBoltEvents(tx.Bucket(outboxBucket), func(event *OutboxEvent) bool {
+			if !event.NextAttemptAt.After(now) {
+				events = append(events, event)
+			}
+			return len(events) < limit
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// AckEvent deletes a delivered event from the outbox.
+func (r *boltOutbox) AckEvent(ctx context.Context, sequence uint64) error {
+	return r.updateEvent(ctx, sequence, func(tx *bolt.Tx, _ *OutboxEvent) error {
+		return tx.Bucket(outboxBucket).Delete(sequenceKey(sequence))
+	})
+}
+
+// RetryEvent counts a failed delivery attempt of an event and reschedules it.
+func (r *boltOutbox) RetryEvent(
+	ctx context.Context,
+	sequence uint64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	return r.updateEvent(ctx, sequence, func(tx *bolt.Tx, event *OutboxEvent) error {
+		event.Attempts++
+		event.NextAttemptAt = nextAttemptAt
+		event.LastError = lastError
+		return putBoltEvent(tx.Bucket(outboxBucket), event)
+	})
+}
+
+// DeadLetterEvent moves an event from the outbox to the dead letters in a single
+// transaction.
+func (r *boltOutbox) DeadLetterEvent(ctx context.Context, sequence uint64, lastError string) error {
+	return r.updateEvent(ctx, sequence, func(tx *bolt.Tx, event *OutboxEvent) error {
+		event.Attempts++
+		event.LastError = lastError
+		if err := putBoltEvent(tx.Bucket(deadLettersBucket), event); err != nil {
+			return err
+		}
+		return tx.Bucket(outboxBucket).Delete(sequenceKey(sequence))
+	})
+}
+
+// DeadLetters returns up to limit dead letters.
+func (r *boltOutbox) DeadLetters(ctx context.Context, limit int) ([]*OutboxEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var events []*OutboxEvent
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return scanBoltEvents(tx.Bucket(deadLettersBucket), func(event *OutboxEvent) bool {
+			events = append(events, event)
+			return len(events) < limit
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// updateEvent runs fn in a write transaction on the outbox event with the sequence,
+// if there is one.
+func (r *boltOutbox) updateEvent(
+	ctx context.Context,
+	sequence uint64,
+	fn func(*bolt.Tx, *OutboxEvent) error,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return r.db.Update(func(tx *bolt.Tx) error {
+		value := tx.Bucket(outboxBucket).Get(sequenceKey(sequence))
+		if value == nil {
+			return nil
+		}
+		event, err := decodeBoltEvent(sequence, value)
+		if err != nil {
+			return err
+		}
+		return fn(tx, event)
+	})
+}
+
+// scanBoltEvents calls fn with the events of bucket in sequence order, while it
+// returns true.
+func scanBoltEvents(bucket *bolt.Bucket, fn func(*OutboxEvent) bool) error {
+	cursor := bucket.Cursor()
+	for key, value := cursor.First(); key != nil; key, value = cursor.Next() {
+		event, err := decodeBoltEvent(binary.BigEndian.Uint64(key), value)
+		if err != nil {
+			return err
+		}
+		if !fn(event) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// appendBoltEvent assigns the next sequence of the outbox bucket to event and stores it.
+func appendBoltEvent(outbox *bolt.Bucket, event *OutboxEvent) error {
+	var err error
+	if event.Sequence, err = outbox.NextSequence(); err != nil {
+		return err
+	}
+	return putBoltEvent(outbox, event)
+}
+
+func putBoltEvent(bucket *bolt.Bucket, event *OutboxEvent) error {
+	value, err := json.Marshal(boltOutboxEvent{
+		CreatedAt:     event.CreatedAt,
+		NextAttemptAt: event.NextAttemptAt,
+		TenantID:      event.TenantID,
+		Type:          event.Type,
+		LastError:     event.LastError,
+		RequestID:     event.RequestID,
+		TraceParent:   event.TraceParent,
+		Payload:       event.Payload,
+		Attempts:      event.Attempts,
+		ID:            event.ID,
+	})
+	if err != nil {
+		return err
+	}
+	return bucket.Put(sequenceKey(event.Sequence), value)
+}
+
+func decodeBoltEvent(sequence uint64, value []byte) (*OutboxEvent, error) {
+	var stored boltOutboxEvent
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return nil, fmt.Errorf("invalid stored event %d: %w", sequence, err)
+	}
+	return &OutboxEvent{
+		CreatedAt:     stored.CreatedAt,
+		NextAttemptAt: stored.NextAttemptAt,
+		TenantID:      stored.TenantID,
+		Type:          stored.Type,
+		LastError:     stored.LastError,
+		RequestID:     stored.RequestID,
+		TraceParent:   stored.TraceParent,
+		Payload:       stored.Payload,
+		Attempts:      stored.Attempts,
+		Sequence:      sequence,
+		ID:            stored.ID,
+	}, nil
+}
+
+// sequenceKey encodes a sequence in big endian, so that the byte order of the keys is
+// the sequence order.
+func sequenceKey(sequence uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, sequence)
+}
@@ -0,0 +1,186 @@
+package repository_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBoltRepository(t *testing.T, path string) *repository.BoltMessageRepository {
+	t.Helper()
+	repo, err := repository.NewBoltMessageRepository(repository.BoltConfig{Path: path, OpenTimeout: time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestBoltMessageRepository(t *testing.T) {
+	t.Run("should keep the messages across reopenings", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "messages.bolt")
+		repo := newBoltRepository(t, path)
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "hello", Tags: []string{"news"}})
+		require.NoError(t, err)
+		require.NoError(t, repo.Close())
+
+		// Act
+		reopened := newBoltRepository(t, path)
+		retrieved, err := reopened.GetMessageByID(t.Context(), created.ID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "hello", retrieved.Text)
+		assert.Equal(t, []string{"news"}, retrieved.Tags)
+		assert.Equal(t, tenant.DefaultID, retrieved.TenantID)
+		assert.Equal(t, int64(1), retrieved.Version)
+	})
+
+	t.Run("should not find a message of another tenant", func(t *testing.T) {
+		// Arrange
+		repo := newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		created, err := repo.CreateMessage(tenant.NewContext(t.Context(), "acme"), &models.Message{Text: "secret"})
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.GetMessageByID(t.Context(), created.ID)
+		batch, batchErr := repo.GetMessagesByIDs(t.Context(), []uuid.UUID{created.ID})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		require.NoError(t, batchErr)
+		assert.Empty(t, batch)
+	})
+
+	t.Run("should update a message at the expected version only", func(t *testing.T) {
+		// Arrange
+		repo := newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "v1", CreatedAt: createdAt})
+		require.NoError(t, err)
+
+		// Act
+		updated, err := repo.UpdateMessage(t.Context(), &models.Message{ID: created.ID, Text: "v2"}, 1)
+		require.NoError(t, err)
+		_, conflict := repo.UpdateMessage(t.Context(), &models.Message{ID: created.ID, Text: "v3"}, 1)
+		_, missing := repo.UpdateMessage(t.Context(), &models.Message{ID: uuid.New()}, 1)
+
+		// Assert
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Equal(t, createdAt, updated.CreatedAt)
+		require.ErrorIs(t, conflict, models.ErrVersionConflict)
+		require.ErrorIs(t, missing, models.ErrMessageNotFound)
+	})
+
+	t.Run("should purge the messages deleted before a time and their index entries", func(t *testing.T) {
+		// Arrange
+		repo := newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		deletedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		_, err := repo.CreateMessages(tenant.NewContext(t.Context(), "acme"), []*models.Message{
+			{Text: "old", DeletedAt: deletedAt},
+			{Text: "recent", DeletedAt: deletedAt.Add(time.Hour)},
+		})
+		require.NoError(t, err)
+
+		// Act
+		purged, err := repo.PurgeDeletedMessages(t.Context(), deletedAt.Add(time.Minute))
+		listed, listErr := repo.ListMessages(tenant.NewContext(t.Context(), "acme"), repository.ListMessagesOptions{
+			Limit:  10,
+			Filter: repository.MessageFilter{IncludeDeleted: true},
+		})
+
+		// Assert
+		require.NoError(t, err)
+		require.NoError(t, listErr)
+		assert.Equal(t, 1, purged)
+		assert.Equal(t, "recent", texts(listed.Messages))
+	})
+
+	t.Run("should write a backup that opens as a database", func(t *testing.T) {
+		// Arrange
+		repo := newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "hello"})
+		require.NoError(t, err)
+		var backup bytes.Buffer
+
+		// Act
+		size, err := repo.Backup(t.Context(), &backup)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(backup.Len()), size)
+
+		path := filepath.Join(t.TempDir(), "backup.bolt")
+		require.NoError(t, os.WriteFile(path, backup.Bytes(), 0o600))
+		restored, err := newBoltRepository(t, path).GetMessageByID(t.Context(), created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", restored.Text)
+	})
+}
+
+func TestBoltMessageRepository_ListMessages(t *testing.T) {
+	t.Run("should page and filter like the in-memory repository", func(t *testing.T) {
+		// Arrange
+		bolt := newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		memory := repository.NewInMemoryMessageRepository()
+		seed(t, bolt, 6)
+		seed(t, memory, 6)
+		middle := base.Add(3 * time.Minute)
+
+		for _, opts := range []repository.ListMessagesOptions{
+			{Limit: 10},
+			{Limit: 2, Descending: true},
+			{Limit: 2, Filter: repository.MessageFilter{AuthorID: "user-2"}},
+			{Limit: 10, Filter: repository.MessageFilter{Tags: []string{"even"}}},
+			{Limit: 10, Filter: repository.MessageFilter{CreatedAfter: base.Add(time.Minute), CreatedBefore: middle}},
+		} {
+			// Act
+			fromBolt, err := bolt.ListMessages(t.Context(), opts)
+			require.NoError(t, err)
+			fromMemory, err := memory.ListMessages(t.Context(), opts)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, texts(fromMemory.Messages), texts(fromBolt.Messages))
+			assert.Equal(t, fromMemory.HasMore, fromBolt.HasMore)
+		}
+	})
+
+	t.Run("should read the pages after and before a key", func(t *testing.T) {
+		// Arrange
+		repo := newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		messages := seed(t, repo, 5)
+		key := repository.KeyOf(messages[2])
+		missing := repository.MessageKey{CreatedAt: base.Add(10 * time.Minute)}
+
+		// Act
+		after, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 1, After: &key})
+		require.NoError(t, err)
+		before, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 2, Before: &key})
+		require.NoError(t, err)
+		beforeDesc, err := repo.ListMessages(
+			t.Context(),
+			repository.ListMessagesOptions{Limit: 5, Before: &key, Descending: true},
+		)
+		require.NoError(t, err)
+		pastTheEnd, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 2, Before: &missing})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "d", texts(after.Messages))
+		assert.True(t, after.HasMore)
+		assert.Equal(t, "ab", texts(before.Messages))
+		assert.False(t, before.HasMore)
+		assert.Equal(t, "ed", texts(beforeDesc.Messages))
+		assert.Equal(t, "de", texts(pastTheEnd.Messages))
+		assert.True(t, pastTheEnd.HasMore)
+	})
+}
@@ -0,0 +1,40 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/features/messages/repository/repositorytest"
+)
+
+func TestContract(t *testing.T) {
+	implementations := map[string]repositorytest.Factory{
+		"memory": func(*testing.T) repository.IMessageRepository {
+			return repository.NewInMemoryMessageRepository()
+		},
+		"sharded": func(*testing.T) repository.IMessageRepository {
+			return repository.NewShardedMessageRepository(8)
+		},
+		"single shard": func(*testing.T) repository.IMessageRepository {
+			return repository.NewShardedMessageRepository(1)
+		},
+		"durable memory": func(t *testing.T) repository.IMessageRepository {
+			return openDurable(t, t.TempDir())
+		},
+		"sqlite": func(t *testing.T) repository.IMessageRepository {
+			return newSQLiteRepository(t)
+		},
+		"bolt": func(t *testing.T) repository.IMessageRepository {
+			return newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		},
+		"event sourced": func(t *testing.T) repository.IMessageRepository {
+			return newEventSourcedRepository(t, filepath.Join(t.TempDir(), "events.bolt"), 2)
+		},
+	}
+	for name, newRepository := range implementations {
+		t.Run(name, func(t *testing.T) {
+			repositorytest.RunContract(t, newRepository)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+)
+
+// OpenInMemoryMessageRepository creates an InMemoryMessageRepository that logs its
+// mutations to the write-ahead log in cfg.Dir, after rebuilding its messages from
+// the snapshot and the log found there. It flushes the log and takes snapshots in
+// the background until it is closed.
+func OpenInMemoryMessageRepository(cfg WALConfig) (*InMemoryMessageRepository, error) {
+	switch cfg.Fsync {
+	case FsyncAlways, FsyncInterval, FsyncNever:
+	default:
+		return nil, fmt.Errorf("unknown write-ahead log fsync policy %q", cfg.Fsync)
+	}
+
+	r := &InMemoryMessageRepository{partitions: make(map[string]*partition)}
+	wal, err := openWAL(cfg, r.apply)
+	if err != nil {
+		return nil, err
+	}
+	r.wal = wal
+	wal.start(r.Snapshot)
+	return r, nil
+}
+
+// Snapshot writes the messages to the snapshot of the write-ahead log and deletes
+// the log segments it replaces. Mutations only wait while the messages are copied.
+// It does nothing when the repository is not durable.
+func (r *InMemoryMessageRepository) Snapshot() error {
+	if r.wal == nil {
+		return nil
+	}
+	r.wal.snapshotMu.Lock()
+	defer r.wal.snapshotMu.Unlock()
+
+	// The read lock keeps out the mutations, which log under the write lock.
+	r.mu.RLock()
+	var messages []walMessage
+	for _, p := range r.partitions {
+		for _, key := range p.index {
+			messages = append(messages, newWALMessage(p.messages[key.ID]))
+		}
+	}
+	generation, err := r.wal.rotate()
+	r.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return r.wal.writeSnapshot(generation, messages)
+}
+
+// Close stops the background work of a durable repository, takes a last snapshot
+// and closes the write-ahead log.
+func (r *InMemoryMessageRepository) Close() error {
+	if r.wal == nil {
+		return nil
+	}
+	r.wal.stop()
+	if err := r.Snapshot(); err != nil {
+		return err
+	}
+	return r.wal.close()
+}
+
+// logPut logs that the messages are stored. The write lock must be held.
+func (r *InMemoryMessageRepository) logPut(messages ...*models.Message) error {
+	if r.wal == nil {
+		return nil
+	}
+	record := walRecord{Put: make([]walMessage, 0, len(messages))}
+	for _, message := range messages {
+		record.Put = append(record.Put, newWALMessage(message))
+	}
+	return r.wal.append(record)
+}
+
+// logDelete logs that the messages are deleted. The write lock must be held.
+func (r *InMemoryMessageRepository) logDelete(messages []*models.Message) error {
+	if r.wal == nil || len(messages) == 0 {
+		return nil
+	}
+	record := walRecord{Delete: make([]walKey, 0, len(messages))}
+	for _, message := range messages {
+		record.Delete = append(record.Delete, walKey{TenantID: message.TenantID, ID: message.ID})
+	}
+	return r.wal.append(record)
+}
+
+// apply replays a logged record while the repository is opened.
+func (r *InMemoryMessageRepository) apply(record walRecord) {
+	for _, logged := range record.Put {
+		r.partition(logged.TenantID).put(logged.message())
+	}
+	for _, key := range record.Delete {
+		if p, ok := r.partitions[key.TenantID]; ok {
+			if message, ok := p.messages[key.ID]; ok {
+				p.remove(message)
+			}
+		}
+	}
+}
@@ -0,0 +1,534 @@
+package repository
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/tenant"
+
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+)
+
+// EventSourcedConfig configures the event-sourced message store.
+type EventSourcedConfig struct {
+	// Path is the bbolt file of the event streams. Its directory is created if needed.
+	Path string
+	// OpenTimeout is how long to wait for the file lock held by another process.
+	OpenTimeout time.Duration
+	// SnapshotInterval is the number of events of a message after which its state is
+	// snapshotted, so that loading it replays at most that many events. Zero disables
+	// the snapshots.
+	SnapshotInterval int
+}
+
+// Bucket names of the event-sourced message store. The streams bucket holds a bucket
+// per tenant, which holds a bucket per message with its events by position. The
+// snapshots bucket holds a bucket per tenant, which holds the latest snapshot of each
+// message by ID.
+var (
+	streamsBucket   = []byte("streams")
+	snapshotsBucket = []byte("snapshots")
+)
+
+// messageSnapshot is the stored state of a message at a position of its stream.
+type messageSnapshot struct {
+	State    json.RawMessage `json:"state"`
+	Position uint64          `json:"position"`
+}
+
+// ProjectionStats describes a rebuild of the projections.
+type ProjectionStats struct {
+	// Streams is the number of event streams replayed.
+	Streams int
+	// Events is the number of events replayed.
+	Events int
+}
+
+// EventSourcedMessageRepository is an IMessageRepository that stores each message as
+// an append-only stream of models.MessageEvent in an embedded bbolt file. The reads
+// are served from an in-memory projection of the streams, loaded on start from the
+// latest snapshot of each message and the events after it. Purging a message erases
+// its stream. It is also an IOutboxRepository: the creation of a message is recorded
+// in the outbox in the transaction starting its stream.
+type EventSourcedMessageRepository struct {
+	boltOutbox
+	// projection is the read model. A rebuild replaces it while the reads go on.
+	projection atomic.Pointer[InMemoryMessageRepository]
+	// writeMu serializes the appends and the rebuilds, so that a rebuild misses no
+	// event.
+	writeMu          sync.Mutex
+	snapshotInterval uint64
+}
+
+// streamChange is a message in its state after the events appended to its stream.
+type streamChange struct {
+	message *models.Message
+	events  []*models.MessageEvent
+}
+
+// NewEventSourcedMessageRepository opens the database file of cfg, creating it if
+// needed, and loads the projection of its streams.
+func NewEventSourcedMessageRepository(cfg EventSourcedConfig) (*EventSourcedMessageRepository, error) {
+	if dir := filepath.Dir(cfg.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: cfg.OpenTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", cfg.Path, err)
+	}
+	r := &EventSourcedMessageRepository{
+		boltOutbox:       boltOutbox{db: db},
+		snapshotInterval: uint64(max(cfg.SnapshotInterval, 0)),
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{streamsBucket, snapshotsBucket, outboxBucket, deadLettersBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		projection, _, err := project(context.Background(), tx, true)
+		r.projection.Store(projection)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load %s: %w", cfg.Path, err)
+	}
+	return r, nil
+}
+
+// Close closes the database file.
+func (r *EventSourcedMessageRepository) Close() error {
+	return r.db.Close()
+}
+
+// CreateMessage starts the stream of a new message with a models.MessageCreated event.
+func (r *EventSourcedMessageRepository) CreateMessage(
+	ctx context.Context,
+	message *models.Message,
+) (*models.Message, error) {
+	created, err := r.CreateMessages(ctx, []*models.Message{message})
+	if err != nil {
+		return nil, err
+	}
+	return created[0], nil
+}
+
+// CreateMessages starts the streams of the messages in a single transaction.
+func (r *EventSourcedMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	stored := make([]*models.Message, 0, len(messages))
+	changes := make([]streamChange, 0, len(messages))
+	for _, message := range messages {
+		message = message.Clone()
+		if message.ID == uuid.Nil {
+			message.ID = uuid.New()
+		}
+		message.TenantID = tenantID
+		message.Version = 1
+		stored = append(stored, message)
+		changes = append(changes, streamChange{
+			message: message,
+			events:  []*models.MessageEvent{models.NewMessageCreated(message)},
+		})
+	}
+	if err := r.append(ctx, tenantID, changes, true); err != nil {
+		return nil, err
+	}
+	r.apply(tenantID, stored)
+	return clones(stored), nil
+}
+
+// GetMessageByID retrieves a message of the context tenant from the projection.
+func (r *EventSourcedMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	return r.projection.Load().GetMessageByID(ctx, id)
+}
+
+// GetMessagesByIDs retrieves the messages of the context tenant with the given IDs
+// from the projection.
+func (r *EventSourcedMessageRepository) GetMessagesByIDs(
+	ctx context.Context,
+	ids []uuid.UUID,
+) ([]*models.Message, error) {
+	return r.projection.Load().GetMessagesByIDs(ctx, ids)
+}
+
+// ListMessages reads a page of the projection.
+func (r *EventSourcedMessageRepository) ListMessages(
+	ctx context.Context,
+	opts ListMessagesOptions,
+) (*ListMessagesResult, error) {
+	return r.projection.Load().ListMessages(ctx, opts)
+}
+
+// UpdateMessage appends the events of the changes of the message to its stream if it
+// is still at expectedVersion. The creation time of a message cannot change.
+func (r *EventSourcedMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	stored, err := r.projection.Load().GetMessageByID(ctx, message.ID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Version != expectedVersion {
+		return nil, fmt.Errorf("%w: message %s is at version %d, not %d",
+			models.ErrVersionConflict, message.ID, stored.Version, expectedVersion)
+	}
+
+	updated := message.Clone()
+	updated.TenantID = tenantID
+	updated.CreatedAt = stored.CreatedAt
+	updated.Version = expectedVersion + 1
+	changes := []streamChange{{message: updated, events: models.MessageChanges(stored, updated)}}
+	if err := r.append(ctx, tenantID, changes, false); err != nil {
+		return nil, err
+	}
+	r.apply(tenantID, []*models.Message{updated})
+	return updated.Clone(), nil
+}
+
+// PurgeDeletedMessages erases the streams and snapshots of the messages soft deleted
+// before deletedBefore.
+func (r *EventSourcedMessageRepository) PurgeDeletedMessages(
+	ctx context.Context,
+	deletedBefore time.Time,
+) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	projection := r.projection.Load()
+	var expired []*models.Message
+	projection.mu.RLock()
+	for _, p := range projection.partitions {
+		for _, message := range p.messages {
+			if message.IsDeleted() && message.DeletedAt.Before(deletedBefore) {
+				expired = append(expired, message)
+			}
+		}
+	}
+	projection.mu.RUnlock()
+
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		for _, message := range expired {
+			tenantID := []byte(message.TenantID)
+			if err := tx.Bucket(streamsBucket).Bucket(tenantID).DeleteBucket(message.ID[:]); err != nil {
+				return fmt.Errorf("failed to erase the stream of message %s: %w", message.ID, err)
+			}
+			if snapshots := tx.Bucket(snapshotsBucket).Bucket(tenantID); snapshots != nil {
+				if err := snapshots.Delete(message.ID[:]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	projection.mu.Lock()
+	defer projection.mu.Unlock()
+	for _, message := range expired {
+		projection.partitions[message.TenantID].remove(message)
+	}
+	return len(expired), nil
+}
+
+// MessageEvents returns the event stream of a message of the context tenant, oldest
+// first.
+func (r *EventSourcedMessageRepository) MessageEvents(
+	ctx context.Context,
+	id uuid.UUID,
+) ([]*models.MessageEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var events []*models.MessageEvent
+	err := r.db.View(func(tx *bolt.Tx) error {
+		streams := tx.Bucket(streamsBucket).Bucket([]byte(tenant.IDFromContext(ctx)))
+		if streams == nil || streams.Bucket(id[:]) == nil {
+			return fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+		}
+		return streams.Bucket(id[:]).ForEach(func(key, value []byte) error {
+			event, err := decodeMessageEvent(id, key, value)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// RebuildProjections replays every stream from its first event, ignoring the
+// snapshots, which it rewrites, and then swaps in the new projection. The reads keep
+// using the previous projection until then, and the writes wait for the rebuild.
+func (r *EventSourcedMessageRepository) RebuildProjections(ctx context.Context) (ProjectionStats, error) {
+	if err := ctx.Err(); err != nil {
+		return ProjectionStats{}, err
+	}
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	var (
+		projection *InMemoryMessageRepository
+		stats      ProjectionStats
+	)
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		if projection, stats, err = project(ctx, tx, false); err != nil {
+			return err
+		}
+
+		if err := tx.DeleteBucket(snapshotsBucket); err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(snapshotsBucket); err != nil {
+			return err
+		}
+		if r.snapshotInterval == 0 {
+			return nil
+		}
+		for tenantID, p := range projection.partitions {
+			streams := tx.Bucket(streamsBucket).Bucket([]byte(tenantID))
+			for id, message := range p.messages {
+				if position := streams.Bucket(id[:]).Sequence(); position >= r.snapshotInterval {
+					if err := putSnapshot(tx, message, position); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ProjectionStats{}, fmt.Errorf("failed to rebuild the projections: %w", err)
+	}
+	r.projection.Store(projection)
+	return stats, nil
+}
+
+// append appends the events of the changes to their streams in a single transaction,
+// starting the streams and recording their creation in the outbox when create is set,
+// and snapshots the messages whose stream crossed a multiple of the snapshot interval.
+func (r *EventSourcedMessageRepository) append(
+	ctx context.Context,
+	tenantID string,
+	changes []streamChange,
+	create bool,
+) error {
+	return r.db.Update(func(tx *bolt.Tx) error {
+		streams, err := tx.Bucket(streamsBucket).CreateBucketIfNotExists([]byte(tenantID))
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			id := change.message.ID
+			stream := streams.Bucket(id[:])
+			if create {
+				stream, err = streams.CreateBucket(id[:])
+				if errors.Is(err, bolt.ErrBucketExists) {
+					return errMessageExists(id)
+				}
+				if err != nil {
+					return err
+				}
+				event, err := messageCreatedEvent(ctx, change.message)
+				if err != nil {
+					return err
+				}
+				if err := appendBoltEvent(tx.Bucket(outboxBucket), event); err != nil {
+					return err
+				}
+			}
+			if stream == nil {
+				return fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+			}
+
+			first := stream.Sequence()
+			for _, event := range change.events {
+				position, err := stream.NextSequence()
+				if err != nil {
+					return err
+				}
+				value, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				if err := stream.Put(sequenceKey(position), value); err != nil {
+					return err
+				}
+			}
+			if last := stream.Sequence(); r.snapshotInterval > 0 && last/r.snapshotInterval > first/r.snapshotInterval {
+				if err := putSnapshot(tx, change.message, last); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// apply puts the messages in the projection.
+func (r *EventSourcedMessageRepository) apply(tenantID string, messages []*models.Message) {
+	projection := r.projection.Load()
+	projection.mu.Lock()
+	defer projection.mu.Unlock()
+
+	p := projection.partition(tenantID)
+	for _, message := range messages {
+		p.put(message)
+	}
+}
+
+// project builds the projection of the streams, replaying each one from its snapshot
+// when fromSnapshots is set, from its first event otherwise.
+func project(
+	ctx context.Context,
+	tx *bolt.Tx,
+	fromSnapshots bool,
+) (*InMemoryMessageRepository, ProjectionStats, error) {
+	projection := &InMemoryMessageRepository{partitions: make(map[string]*partition)}
+	var stats ProjectionStats
+
+	err := tx.Bucket(streamsBucket).ForEachBucket(func(name []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		tenantID := string(name)
+		streams := tx.Bucket(streamsBucket).Bucket(name)
+		snapshots := tx.Bucket(snapshotsBucket).Bucket(name)
+		p := projection.partition(tenantID)
+
+		return streams.ForEachBucket(func(key []byte) error {
+			id, err := uuid.FromBytes(key)
+			if err != nil {
+				return err
+			}
+			message := &models.Message{ID: id, TenantID: tenantID}
+			var position uint64
+			if fromSnapshots && snapshots != nil {
+				if value := snapshots.Get(key); value != nil {
+					if message, position, err = decodeSnapshot(id, tenantID, value); err != nil {
+						return err
+					}
+				}
+			}
+
+			replayed, err := replay(streams.Bucket(key), message, position)
+			if err != nil {
+				return err
+			}
+			p.put(message)
+			stats.Streams++
+			stats.Events += replayed
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, ProjectionStats{}, err
+	}
+	return projection, stats, nil
+}
+
+// replay applies to message the events of stream after position, and returns how
+// many it applied.
+func replay(stream *bolt.Bucket, message *models.Message, position uint64) (int, error) {
+	replayed := 0
+	cursor := stream.Cursor()
+	for key, value := cursor.Seek(sequenceKey(position + 1)); key != nil; key, value = cursor.Next() {
+		event, err := decodeMessageEvent(message.ID, key, value)
+		if err != nil {
+			return replayed, err
+		}
+		if err := message.Apply(event); err != nil {
+			return replayed, fmt.Errorf(
+				"invalid event %d of message %s: %w",
+				binary.BigEndian.Uint64(key),
+				message.ID,
+				err,
+			)
+		}
+		replayed++
+	}
+	return replayed, nil
+}
+
+func putSnapshot(tx *bolt.Tx, message *models.Message, position uint64) error {
+	snapshots, err := tx.Bucket(snapshotsBucket).CreateBucketIfNotExists([]byte(message.TenantID))
+	if err != nil {
+		return err
+	}
+	state, err := encodeBoltMessage(message)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(messageSnapshot{State: state, Position: position})
+	if err != nil {
+		return err
+	}
+	return snapshots.Put(message.ID[:], value)
+}
+
+func decodeSnapshot(id uuid.UUID, tenantID string, value []byte) (*models.Message, uint64, error) {
+	var snapshot messageSnapshot
+	if err := json.Unmarshal(value, &snapshot); err != nil {
+		return nil, 0, fmt.Errorf("invalid snapshot of message %s: %w", id, err)
+	}
+	message, err := decodeBoltMessage(snapshot.State)
+	if err != nil {
+		return nil, 0, err
+	}
+	message.ID = id
+	message.TenantID = tenantID
+	return message, snapshot.Position, nil
+}
+
+func decodeMessageEvent(id uuid.UUID, key, value []byte) (*models.MessageEvent, error) {
+	var event models.MessageEvent
+	if err := json.Unmarshal(value, &event); err != nil {
+		return nil, fmt.Errorf("invalid event %d of message %s: %w", binary.BigEndian.Uint64(key), id, err)
+	}
+	return &event, nil
+}
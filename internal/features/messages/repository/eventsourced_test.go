@@ -0,0 +1,210 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEventSourcedRepository(
+	t *testing.T,
+	path string,
+	snapshotInterval int,
+) *repository.EventSourcedMessageRepository {
+	t.Helper()
+	repo, err := repository.NewEventSourcedMessageRepository(repository.EventSourcedConfig{
+		Path:             path,
+		OpenTimeout:      time.Second,
+		SnapshotInterval: snapshotInterval,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// edit updates the text of a message n times, one version after the other.
+func edit(t *testing.T, repo repository.IMessageRepository, message *models.Message, n int) *models.Message {
+	t.Helper()
+	for i := range n {
+		updated := message.Clone()
+		updated.Text = message.Text + string(rune('0'+i))
+		var err error
+		message, err = repo.UpdateMessage(tenant.NewContext(t.Context(), message.TenantID), updated, message.Version)
+		require.NoError(t, err)
+	}
+	return message
+}
+
+func TestEventSourcedMessageRepository(t *testing.T) {
+	t.Run("should record the changes of a message in its stream", func(t *testing.T) {
+		// Arrange
+		repo := newEventSourcedRepository(t, filepath.Join(t.TempDir(), "events.bolt"), 0)
+		acme := tenant.NewContext(t.Context(), "acme")
+		message, err := repo.CreateMessage(acme, &models.Message{Text: "v1", CreatedAt: base, UpdatedAt: base})
+		require.NoError(t, err)
+
+		// Act
+		edited := message.Clone()
+		edited.Text, edited.UpdatedAt = "v2", base.Add(time.Minute)
+		edited, err = repo.UpdateMessage(acme, edited, 1)
+		require.NoError(t, err)
+		deleted := edited.Clone()
+		deleted.DeletedAt, deleted.UpdatedAt = base.Add(2*time.Minute), base.Add(2*time.Minute)
+		deleted, err = repo.UpdateMessage(acme, deleted, 2)
+		require.NoError(t, err)
+		restored := deleted.Clone()
+		restored.DeletedAt, restored.UpdatedAt = time.Time{}, base.Add(3*time.Minute)
+		_, err = repo.UpdateMessage(acme, restored, 3)
+		require.NoError(t, err)
+
+		// Assert
+		stream, err := repo.MessageEvents(acme, message.ID)
+		require.NoError(t, err)
+		types := make([]models.MessageEventType, 0, len(stream))
+		for _, event := range stream {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []models.MessageEventType{
+			models.MessageCreated, models.MessageEdited, models.MessageDeleted, models.MessageRestored,
+		}, types)
+		assert.Equal(t, "v2", stream[1].Text)
+		assert.Equal(t, int64(4), stream[3].Version)
+
+		_, err = repo.MessageEvents(t.Context(), message.ID)
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+	})
+
+	t.Run("should project the streams again when reopened", func(t *testing.T) {
+		for _, interval := range []int{0, 1, 3} {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "events.bolt")
+			repo := newEventSourcedRepository(t, path, interval)
+			acme := tenant.NewContext(t.Context(), "acme")
+			edited, err := repo.CreateMessage(acme, &models.Message{Text: "a", Tags: []string{"news"}, CreatedAt: base})
+			require.NoError(t, err)
+			edited = edit(t, repo, edited, 4)
+			untouched, err := repo.CreateMessage(
+				t.Context(),
+				&models.Message{Text: "b", CreatedAt: base.Add(time.Minute)},
+			)
+			require.NoError(t, err)
+			require.NoError(t, repo.Close())
+
+			// Act
+			reopened := newEventSourcedRepository(t, path, interval)
+
+			// Assert
+			retrieved, err := reopened.GetMessageByID(acme, edited.ID)
+			require.NoError(t, err, "interval %d", interval)
+			assert.Equal(t, edited, retrieved, "interval %d", interval)
+			retrieved, err = reopened.GetMessageByID(t.Context(), untouched.ID)
+			require.NoError(t, err, "interval %d", interval)
+			assert.Equal(t, untouched, retrieved, "interval %d", interval)
+			listed, err := reopened.ListMessages(acme, repository.ListMessagesOptions{Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, "a0123", texts(listed.Messages), "interval %d", interval)
+		}
+	})
+
+	t.Run("should rebuild the projections from every event", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "events.bolt")
+		repo := newEventSourcedRepository(t, path, 2)
+		messages := seed(t, repo, 3)
+		edited := edit(t, repo, messages[0], 3)
+
+		// Act
+		stats, err := repo.RebuildProjections(t.Context())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, repository.ProjectionStats{Streams: 3, Events: 6}, stats)
+		retrieved, err := repo.GetMessageByID(t.Context(), edited.ID)
+		require.NoError(t, err)
+		assert.Equal(t, edited, retrieved)
+
+		require.NoError(t, repo.Close())
+		reopened := newEventSourcedRepository(t, path, 2)
+		retrieved, err = reopened.GetMessageByID(t.Context(), edited.ID)
+		require.NoError(t, err)
+		assert.Equal(t, edited, retrieved)
+	})
+
+	t.Run("should keep serving the messages written after a rebuild", func(t *testing.T) {
+		// Arrange
+		repo := newEventSourcedRepository(t, filepath.Join(t.TempDir(), "events.bolt"), 0)
+		seed(t, repo, 2)
+		_, err := repo.RebuildProjections(t.Context())
+		require.NoError(t, err)
+
+		// Act
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "c", CreatedAt: base.Add(time.Hour)})
+
+		// Assert
+		require.NoError(t, err)
+		listed, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, "abc", texts(listed.Messages))
+		_, err = repo.GetMessageByID(t.Context(), created.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("should erase the streams of purged messages", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "events.bolt")
+		repo := newEventSourcedRepository(t, path, 1)
+		deletedAt := base.Add(time.Hour)
+		batch, err := repo.CreateMessages(t.Context(), []*models.Message{
+			{Text: "purged", CreatedAt: base, DeletedAt: deletedAt},
+			{Text: "kept", CreatedAt: base},
+		})
+		require.NoError(t, err)
+
+		// Act
+		purged, err := repo.PurgeDeletedMessages(t.Context(), deletedAt.Add(time.Minute))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		_, err = repo.MessageEvents(t.Context(), batch[0].ID)
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+
+		stats, err := repo.RebuildProjections(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.Streams)
+		require.NoError(t, repo.Close())
+		reopened := newEventSourcedRepository(t, path, 1)
+		messages, err := reopened.GetMessagesByIDs(t.Context(), []uuid.UUID{batch[0].ID, batch[1].ID})
+		require.NoError(t, err)
+		assert.Equal(t, "kept", texts(messages))
+	})
+
+	t.Run("should create no stream of a batch with an existing ID", func(t *testing.T) {
+		// Arrange
+		repo := newEventSourcedRepository(t, filepath.Join(t.TempDir(), "events.bolt"), 0)
+		existing, err := repo.CreateMessage(t.Context(), &models.Message{Text: "a", CreatedAt: base})
+		require.NoError(t, err)
+		fresh := uuid.New()
+
+		// Act
+		_, err = repo.CreateMessages(t.Context(), []*models.Message{
+			{ID: fresh, Text: "b", CreatedAt: base},
+			{ID: existing.ID, Text: "duplicate", CreatedAt: base},
+		})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageAlreadyExists)
+		_, err = repo.MessageEvents(t.Context(), fresh)
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		retrieved, err := repo.GetMessageByID(t.Context(), existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "a", retrieved.Text)
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"slices"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+
+	"github.com/google/uuid"
+)
+
+// MessageKey is the position of a message in the listing order: by creation time,
+// then by ID for the messages created at the same time.
+type MessageKey struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// KeyOf returns the listing position of message.
+func KeyOf(message *models.Message) MessageKey {
+	return MessageKey{CreatedAt: message.CreatedAt, ID: message.ID}
+}
+
+// Compare orders keys by creation time, then by ID.
+func (k MessageKey) Compare(other MessageKey) int {
+	if c := k.CreatedAt.Compare(other.CreatedAt); c != 0 {
+		return c
+	}
+	return slices.Compare(k.ID[:], other.ID[:])
+}
+
+// MessageFilter selects the messages of a listing. Zero fields match every message.
+type MessageFilter struct {
+	// CreatedAfter and CreatedBefore bound the creation time, inclusively and
+	// exclusively.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	AuthorID      string
+	// Tags must all be carried by the message.
+	Tags           []string
+	IncludeDeleted bool
+}
+
+// Matches reports whether message is selected by the filter.
+func (f MessageFilter) Matches(message *models.Message) bool {
+	if message.IsDeleted() && !f.IncludeDeleted {
+		return false
+	}
+	if f.AuthorID != "" && message.AuthorID != f.AuthorID {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && message.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !message.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.Contains(message.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// ListMessagesOptions selects a page of messages. Pages are delimited by the key of
+// a message rather than an offset, so that they stay stable under concurrent inserts.
+type ListMessagesOptions struct {
+	// After returns the messages following this key in the listing order.
+	After *MessageKey
+	// Before returns the messages preceding this key in the listing order, for the
+	// previous page. After and Before are exclusive and mutually exclusive.
+	Before *MessageKey
+	Filter MessageFilter
+	// Limit is the maximum number of messages of the page.
+	Limit int
+	// Descending lists the newest messages first.
+	Descending bool
+}
+
+// boundary returns the key the page is read from, if any.
+func (o ListMessagesOptions) boundary() *MessageKey {
+	if o.Before != nil {
+		return o.Before
+	}
+	return o.After
+}
+
+// forward reports whether the page is read in ascending key order: for ascending
+// pages read after a key and for descending pages read before a key.
+func (o ListMessagesOptions) forward() bool {
+	return o.Descending == (o.Before != nil)
+}
+
+// ListMessagesResult is a page of messages, in listing order.
+type ListMessagesResult struct {
+	Messages []*models.Message
+	// HasMore reports whether more messages follow the page in the direction it was
+	// read: after its last message, or before its first one when Before was set.
+	HasMore bool
+}
+
+// newListMessagesResult returns the page of opts from up to opts.Limit+1 messages
+// read from its boundary, in the order they were read.
+func newListMessagesResult(messages []*models.Message, opts ListMessagesOptions) *ListMessagesResult {
+	result := &ListMessagesResult{Messages: messages}
+	if len(messages) > opts.Limit {
+		result.Messages = messages[:opts.Limit]
+		result.HasMore = true
+	}
+	if opts.Before != nil {
+		// The page was read backward from its boundary: restore the listing order.
+		slices.Reverse(result.Messages)
+	}
+	return result
+}
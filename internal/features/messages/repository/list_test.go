@@ -0,0 +1,167 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var base = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// seed creates n messages created a minute apart, in random ID order, and returns
+// them in creation order.
+func seed(t *testing.T, repo repository.IMessageRepository, n int) []*models.Message {
+	t.Helper()
+	messages := make([]*models.Message, 0, n)
+	for i := n - 1; i >= 0; i-- {
+		message, err := repo.CreateMessage(t.Context(), &models.Message{
+			Text:      string(rune('a' + i)),
+			AuthorID:  []string{"user-1", "user-2"}[i%2],
+			Tags:      []string{[]string{"even", "odd"}[i%2]},
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		})
+		require.NoError(t, err)
+		messages = append([]*models.Message{message}, messages...)
+	}
+	return messages
+}
+
+func texts(messages []*models.Message) string {
+	result := ""
+	for _, message := range messages {
+		result += message.Text
+	}
+	return result
+}
+
+func TestInMemoryMessageRepository_ListMessages(t *testing.T) {
+	t.Run("should list in creation order, both ways", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		seed(t, repo, 5)
+
+		// Act
+		asc, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+		require.NoError(t, err)
+		desc, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10, Descending: true})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "abcde", texts(asc.Messages))
+		assert.Equal(t, "edcba", texts(desc.Messages))
+		assert.False(t, asc.HasMore)
+	})
+
+	t.Run("should read the pages after and before a key", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		messages := seed(t, repo, 5)
+		key := repository.KeyOf(messages[2])
+
+		// Act
+		after, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 1, After: &key})
+		require.NoError(t, err)
+		before, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 2, Before: &key})
+		require.NoError(t, err)
+		afterDesc, err := repo.ListMessages(
+			t.Context(),
+			repository.ListMessagesOptions{Limit: 5, After: &key, Descending: true},
+		)
+		require.NoError(t, err)
+		beforeDesc, err := repo.ListMessages(
+			t.Context(),
+			repository.ListMessagesOptions{Limit: 5, Before: &key, Descending: true},
+		)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "d", texts(after.Messages))
+		assert.True(t, after.HasMore)
+		assert.Equal(t, "ab", texts(before.Messages))
+		assert.False(t, before.HasMore)
+		assert.Equal(t, "ba", texts(afterDesc.Messages))
+		assert.Equal(t, "ed", texts(beforeDesc.Messages))
+	})
+
+	t.Run("should filter by author, creation time and tags", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		seed(t, repo, 6)
+
+		// Act
+		byAuthor, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{
+			Limit:  10,
+			Filter: repository.MessageFilter{AuthorID: "user-2"},
+		})
+		require.NoError(t, err)
+		byRange, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{
+			Limit: 10,
+			Filter: repository.MessageFilter{
+				CreatedAfter:  base.Add(time.Minute),
+				CreatedBefore: base.Add(4 * time.Minute),
+			},
+		})
+		require.NoError(t, err)
+		byTag, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{
+			Limit:  2,
+			Filter: repository.MessageFilter{Tags: []string{"even"}},
+		})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "bdf", texts(byAuthor.Messages))
+		assert.Equal(t, "bcd", texts(byRange.Messages))
+		assert.Equal(t, "ac", texts(byTag.Messages))
+		assert.True(t, byTag.HasMore)
+	})
+
+	t.Run("should hide deleted messages and other tenants", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		seed(t, repo, 2)
+		_, err := repo.CreateMessage(t.Context(), &models.Message{Text: "x", DeletedAt: base})
+		require.NoError(t, err)
+		_, err = repo.CreateMessage(tenant.NewContext(t.Context(), "acme"), &models.Message{Text: "y"})
+		require.NoError(t, err)
+
+		// Act
+		live, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+		require.NoError(t, err)
+		all, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{
+			Limit:  10,
+			Filter: repository.MessageFilter{IncludeDeleted: true},
+		})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "ab", texts(live.Messages))
+		assert.Len(t, all.Messages, 3)
+	})
+
+	t.Run("should keep the index ordered when messages are purged", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		messages := seed(t, repo, 3)
+		deleted := *messages[1]
+		deleted.DeletedAt = base
+		_, err := repo.UpdateMessage(t.Context(), &deleted, 1)
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.PurgeDeletedMessages(t.Context(), base.Add(time.Hour))
+		require.NoError(t, err)
+		result, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{
+			Limit:  10,
+			Filter: repository.MessageFilter{IncludeDeleted: true},
+		})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "ac", texts(result.Messages))
+	})
+}
@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"fmt"
+	"io"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/tenant"
 
 	"github.com/google/uuid"
 	"go.uber.org/fx"
@@ -13,53 +17,383 @@ import (
 
 // Module exports the repository functionality.
 var Module = fx.Options(
-	fx.Provide(NewInMemoryMessageRepository),
+	fx.Provide(NewStoreConfig),
+	fx.Provide(NewMessageRepository),
+	fx.Provide(NewPurgeConfig),
+	fx.Invoke(RegisterPurge),
 )
 
 // IMessageRepository defines the interface for message repository.
+// Implementations partition the messages by the tenant of the context, as returned
+// by tenant.IDFromContext: a message is never visible from another tenant. They
+// are safe for concurrent use and fail with the context error, without any change,
+// once the context is done. Like a database, they neither keep nor change the
+// messages they are given, and the messages they return belong to the caller.
+// repositorytest.RunContract checks this behavior.
 type IMessageRepository interface {
 	CreateMessage(ctx context.Context, message *models.Message) (*models.Message, error)
+	// CreateMessages adds all the messages in a single operation: either every
+	// message is created or none is.
+	CreateMessages(ctx context.Context, messages []*models.Message) ([]*models.Message, error)
+	// GetMessageByID returns the message even when it is soft deleted.
 	GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error)
+	// GetMessagesByIDs returns the messages found among ids, soft deleted or not, in
+	// the order of ids. Missing IDs are skipped.
+	GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error)
+	// UpdateMessage replaces the message if it is still at expectedVersion and returns
+	// it with its version incremented, or fails with models.ErrVersionConflict.
+	// Messages are soft deleted and restored by updating their DeletedAt.
+	UpdateMessage(ctx context.Context, message *models.Message, expectedVersion int64) (*models.Message, error)
+	// PurgeDeletedMessages permanently deletes the messages of every tenant that were
+	// soft deleted before the given time, and returns how many it deleted.
+	PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error)
+	// ListMessages returns a page of the messages of the context tenant.
+	ListMessages(ctx context.Context, opts ListMessagesOptions) (*ListMessagesResult, error)
+}
+
+// IBackupRepository is implemented by the repositories that can copy their data
+// while they keep serving requests.
+type IBackupRepository interface {
+	// Backup writes a consistent copy of the data to w and returns its size.
+	Backup(ctx context.Context, w io.Writer) (int64, error)
+}
+
+// IProjectionRepository is implemented by the repositories that serve the messages
+// from projections of an event store.
+type IProjectionRepository interface {
+	// RebuildProjections rebuilds the projections by replaying every event from the
+	// start of its stream, and returns what it replayed.
+	RebuildProjections(ctx context.Context) (ProjectionStats, error)
 }
 
 // InMemoryMessageRepository is an in-memory implementation of IMessageRepository.
+// It stores copies of the messages it is given and returns copies of the messages
+// it stores, so that its callers cannot change them in place. When opened with
+// OpenInMemoryMessageRepository, its mutations are also logged to a write-ahead
+// log, from which the messages are rebuilt on the next start.
 type InMemoryMessageRepository struct {
+	// partitions holds one partition per tenant ID.
+	partitions map[string]*partition
+	// wal is nil unless the repository is durable.
+	wal *writeAheadLog
+	mu  sync.RWMutex
+}
+
+// partition holds the messages of a tenant and their keys, sorted in ascending
+// listing order.
+type partition struct {
+	tenantID string
 	messages map[uuid.UUID]*models.Message
-	mu       sync.RWMutex
+	index    []MessageKey
+}
+
+func newPartition(tenantID string) *partition {
+	return &partition{tenantID: tenantID, messages: make(map[uuid.UUID]*models.Message)}
+}
+
+// prepare assigns the ID, tenant and first version of a new message.
+func (p *partition) prepare(message *models.Message) {
+	if message.ID == uuid.Nil {
+		message.ID = uuid.New()
+	}
+	message.TenantID = p.tenantID
+	message.Version = 1
+}
+
+// checkNew fails with models.ErrMessageAlreadyExists unless the prepared messages
+// have distinct IDs that no message of the partition has.
+func (p *partition) checkNew(messages ...*models.Message) error {
+	seen := make(map[uuid.UUID]struct{}, len(messages))
+	for _, message := range messages {
+		if _, ok := p.messages[message.ID]; ok {
+			return errMessageExists(message.ID)
+		}
+		if _, ok := seen[message.ID]; ok {
+			return errMessageExists(message.ID)
+		}
+		seen[message.ID] = struct{}{}
+	}
+	return nil
+}
+
+func errMessageExists(id uuid.UUID) error {
+	return fmt.Errorf("%w: message with ID %s already exists", models.ErrMessageAlreadyExists, id)
+}
+
+func (p *partition) put(message *models.Message) {
+	if stored, ok := p.messages[message.ID]; ok {
+		p.unindex(KeyOf(stored))
+	}
+	p.messages[message.ID] = message
+
+	key := KeyOf(message)
+	i, _ := slices.BinarySearchFunc(p.index, key, MessageKey.Compare)
+	p.index = slices.Insert(p.index, i, key)
+}
+
+// walk returns up to limit messages accepted by the filter of opts, read from the
+// page boundary in index order, forward or backward as told by opts.forward.
+func (p *partition) walk(opts ListMessagesOptions, limit int) []*models.Message {
+	boundary, forward := opts.boundary(), opts.forward()
+
+	var i int
+	if boundary == nil {
+		i = 0
+		if !forward {
+			i = len(p.index) - 1
+		}
+	} else {
+		pos, found := slices.BinarySearchFunc(p.index, *boundary, MessageKey.Compare)
+		i = pos
+		if forward && found {
+			i++
+		}
+		if !forward {
+			i = pos - 1
+		}
+	}
+
+	step := 1
+	if !forward {
+		step = -1
+	}
+	var messages []*models.Message
+	for ; i >= 0 && i < len(p.index) && len(messages) < limit; i += step {
+		message := p.messages[p.index[i].ID]
+		if opts.Filter.Matches(message) {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+func (p *partition) remove(message *models.Message) {
+	delete(p.messages, message.ID)
+	p.unindex(KeyOf(message))
+}
+
+func (p *partition) unindex(key MessageKey) {
+	if i, found := slices.BinarySearchFunc(p.index, key, MessageKey.Compare); found {
+		p.index = slices.Delete(p.index, i, i+1)
+	}
 }
 
 // NewInMemoryMessageRepository creates a new InMemoryMessageRepository.
 // The fx.In is not strictly necessary here but shows how dependencies would be injected.
 func NewInMemoryMessageRepository() IMessageRepository {
 	return &InMemoryMessageRepository{
-		messages: make(map[uuid.UUID]*models.Message),
+		partitions: make(map[string]*partition),
 	}
 }
 
-// CreateMessage adds a new message to the store.
+// CreateMessage adds a new message to the partition of the context tenant.
 func (r *InMemoryMessageRepository) CreateMessage(
 	ctx context.Context,
 	message *models.Message,
 ) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if message.ID == uuid.Nil {
-		message.ID = uuid.New()
+	p := r.partition(tenantID)
+	stored := message.Clone()
+	p.prepare(stored)
+	if err := p.checkNew(stored); err != nil {
+		return nil, err
+	}
+	if err := r.logPut(stored); err != nil {
+		return nil, err
+	}
+	p.put(stored)
+	return stored.Clone(), nil
+}
+
+// CreateMessages adds the messages to the partition of the context tenant under a
+// single write lock.
+func (r *InMemoryMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	tenantID := tenant.IDFromContext(ctx)
 
-	r.messages[message.ID] = message
-	return message, nil
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := r.partition(tenantID)
+	stored := make([]*models.Message, 0, len(messages))
+	for _, message := range messages {
+		message = message.Clone()
+		p.prepare(message)
+		stored = append(stored, message)
+	}
+	if err := p.checkNew(stored...); err != nil {
+		return nil, err
+	}
+	if err := r.logPut(stored...); err != nil {
+		return nil, err
+	}
+	for _, message := range stored {
+		p.put(message)
+	}
+	return clones(stored), nil
+}
+
+// partition returns the partition of the tenant, creating it if needed. The write
+// lock must be held.
+func (r *InMemoryMessageRepository) partition(tenantID string) *partition {
+	p, ok := r.partitions[tenantID]
+	if !ok {
+		p = newPartition(tenantID)
+		r.partitions[tenantID] = p
+	}
+	return p
 }
 
-// GetMessageByID retrieves a message from the store by its ID.
+// GetMessageByID retrieves a message of the context tenant by its ID.
 func (r *InMemoryMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenantID]
+	if !ok {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+	}
+	message, ok := p.messages[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+	}
+	return message.Clone(), nil
+}
+
+// GetMessagesByIDs retrieves the messages of the context tenant with the given IDs,
+// holding the read lock once for the whole batch.
+func (r *InMemoryMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	message, ok := r.messages[id]
+	p, ok := r.partitions[tenantID]
+	if !ok {
+		return nil, nil
+	}
+	messages := make([]*models.Message, 0, len(ids))
+	for _, id := range ids {
+		if message, found := p.messages[id]; found {
+			messages = append(messages, message.Clone())
+		}
+	}
+	return messages, nil
+}
+
+// UpdateMessage compares the stored version of the message with expectedVersion and
+// swaps in a copy of message when they match. The creation time of a message cannot
+// change.
+func (r *InMemoryMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.partitions[tenantID]
 	if !ok {
-		return nil, fmt.Errorf("message with ID %s not found", id)
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, message.ID)
+	}
+	stored, ok := p.messages[message.ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, message.ID)
+	}
+	if stored.Version != expectedVersion {
+		return nil, fmt.Errorf("%w: message %s is at version %d, not %d",
+			models.ErrVersionConflict, message.ID, stored.Version, expectedVersion)
+	}
+
+	updated := message.Clone()
+	updated.TenantID = tenantID
+	updated.CreatedAt = stored.CreatedAt
+	updated.Version = expectedVersion + 1
+	if err := r.logPut(updated); err != nil {
+		return nil, err
+	}
+	p.messages[updated.ID] = updated
+	return updated.Clone(), nil
+}
+
+// PurgeDeletedMessages removes the messages soft deleted before deletedBefore.
+func (r *InMemoryMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []*models.Message
+	for _, p := range r.partitions {
+		for _, message := range p.messages {
+			if message.IsDeleted() && message.DeletedAt.Before(deletedBefore) {
+				expired = append(expired, message)
+			}
+		}
+	}
+	if err := r.logDelete(expired); err != nil {
+		return 0, err
+	}
+	for _, message := range expired {
+		r.partitions[message.TenantID].remove(message)
+	}
+	return len(expired), nil
+}
+
+// ListMessages walks the ordered index of the tenant partition from the page
+// boundary, skipping the messages rejected by the filter.
+func (r *InMemoryMessageRepository) ListMessages(
+	ctx context.Context,
+	opts ListMessagesOptions,
+) (*ListMessagesResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.partitions[tenantID]
+	if !ok || opts.Limit <= 0 {
+		return &ListMessagesResult{}, nil
+	}
+	result := newListMessagesResult(p.walk(opts, opts.Limit+1), opts)
+	result.Messages = clones(result.Messages)
+	return result, nil
+}
+
+// clones returns copies of the messages.
+func clones(messages []*models.Message) []*models.Message {
+	copies := make([]*models.Message, 0, len(messages))
+	for _, message := range messages {
+		copies = append(copies, message.Clone())
 	}
-	return message, nil
+	return copies
 }
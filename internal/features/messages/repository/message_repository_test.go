@@ -2,10 +2,13 @@ package repository_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/repository"
 
 	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/arielsrv/fxf/pkg/tenant"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,8 +20,12 @@ func TestInMemoryMessageRepository(t *testing.T) {
 
 	t.Run("should create and get a message successfully", func(t *testing.T) {
 		// Arrange
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
 		createMsg := &models.Message{
-			Text: "hello world",
+			Text:      "hello world",
+			AuthorID:  "user-1",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
 		}
 
 		// Act
@@ -34,6 +41,9 @@ func TestInMemoryMessageRepository(t *testing.T) {
 		require.NotNil(t, retrievedMsg)
 		assert.Equal(t, createdMsg.ID, retrievedMsg.ID)
 		assert.Equal(t, "hello world", retrievedMsg.Text)
+		assert.Equal(t, "user-1", retrievedMsg.AuthorID)
+		assert.Equal(t, createdAt, retrievedMsg.CreatedAt)
+		assert.Equal(t, createdAt, retrievedMsg.UpdatedAt)
 	})
 
 	t.Run("should return an error when message not found", func(t *testing.T) {
@@ -49,3 +59,218 @@ func TestInMemoryMessageRepository(t *testing.T) {
 		assert.Contains(t, err.Error(), "not found")
 	})
 }
+
+func TestInMemoryMessageRepository_TenantIsolation(t *testing.T) {
+	repo := repository.NewInMemoryMessageRepository()
+	acme := tenant.NewContext(t.Context(), "acme")
+	globex := tenant.NewContext(t.Context(), "globex")
+
+	created, err := repo.CreateMessage(acme, &models.Message{Text: "acme secret"})
+	require.NoError(t, err)
+
+	t.Run("should store the message in the tenant partition", func(t *testing.T) {
+		// Act
+		retrievedMsg, err := repo.GetMessageByID(acme, created.ID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "acme", retrievedMsg.TenantID)
+	})
+
+	t.Run("should not return the message of another tenant with a known ID", func(t *testing.T) {
+		// Act
+		fromGlobex, errGlobex := repo.GetMessageByID(globex, created.ID)
+		fromDefault, errDefault := repo.GetMessageByID(t.Context(), created.ID)
+
+		// Assert
+		require.Error(t, errGlobex)
+		require.Error(t, errDefault)
+		assert.Nil(t, fromGlobex)
+		assert.Nil(t, fromDefault)
+		assert.Contains(t, errGlobex.Error(), "not found")
+	})
+
+	t.Run("should keep messages with the same ID apart", func(t *testing.T) {
+		// Arrange
+		id := uuid.New()
+		_, err := repo.CreateMessage(acme, &models.Message{ID: id, Text: "acme"})
+		require.NoError(t, err)
+		_, err = repo.CreateMessage(globex, &models.Message{ID: id, Text: "globex"})
+		require.NoError(t, err)
+
+		// Act
+		fromAcme, errAcme := repo.GetMessageByID(acme, id)
+		fromGlobex, errGlobex := repo.GetMessageByID(globex, id)
+
+		// Assert
+		require.NoError(t, errAcme)
+		require.NoError(t, errGlobex)
+		assert.Equal(t, "acme", fromAcme.Text)
+		assert.Equal(t, "globex", fromGlobex.Text)
+	})
+}
+
+func TestInMemoryMessageRepository_CreateMessages(t *testing.T) {
+	t.Run("should create every message in the context tenant", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		ctx := tenant.NewContext(t.Context(), "acme")
+
+		// Act
+		created, err := repo.CreateMessages(ctx, []*models.Message{{Text: "first"}, {Text: "second"}})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+		for _, message := range created {
+			assert.NotEqual(t, uuid.Nil, message.ID)
+			assert.Equal(t, "acme", message.TenantID)
+			assert.Equal(t, int64(1), message.Version)
+
+			stored, err := repo.GetMessageByID(ctx, message.ID)
+			require.NoError(t, err)
+			assert.Equal(t, message.Text, stored.Text)
+		}
+	})
+}
+
+func TestInMemoryMessageRepository_GetMessagesByIDs(t *testing.T) {
+	t.Run("should return the messages found in the order of the IDs", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+		first, err := repo.CreateMessage(t.Context(), &models.Message{Text: "first"})
+		require.NoError(t, err)
+		second, err := repo.CreateMessage(t.Context(), &models.Message{Text: "second"})
+		require.NoError(t, err)
+		other, err := repo.CreateMessage(tenant.NewContext(t.Context(), "acme"), &models.Message{Text: "other"})
+		require.NoError(t, err)
+
+		// Act
+		messages, err := repo.GetMessagesByIDs(t.Context(), []uuid.UUID{second.ID, uuid.New(), other.ID, first.ID})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "second", messages[0].Text)
+		assert.Equal(t, "first", messages[1].Text)
+	})
+
+	t.Run("should return nothing for a tenant without messages", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewInMemoryMessageRepository()
+
+		// Act
+		messages, err := repo.GetMessagesByIDs(t.Context(), []uuid.UUID{uuid.New()})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
+
+func TestInMemoryMessageRepository_UpdateMessage(t *testing.T) {
+	ctx := t.Context()
+	repo := repository.NewInMemoryMessageRepository()
+
+	t.Run("should swap the message when the version matches", func(t *testing.T) {
+		// Arrange
+		created, err := repo.CreateMessage(ctx, &models.Message{Text: "hello"})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), created.Version)
+
+		// Act
+		updated, err := repo.UpdateMessage(ctx, &models.Message{ID: created.ID, Text: "hello again"}, 1)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+		retrievedMsg, err := repo.GetMessageByID(ctx, created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello again", retrievedMsg.Text)
+	})
+
+	t.Run("should fail with a conflict when the version changed", func(t *testing.T) {
+		// Arrange
+		created, err := repo.CreateMessage(ctx, &models.Message{Text: "hello"})
+		require.NoError(t, err)
+		_, err = repo.UpdateMessage(ctx, &models.Message{ID: created.ID, Text: "first"}, 1)
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.UpdateMessage(ctx, &models.Message{ID: created.ID, Text: "second"}, 1)
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrVersionConflict)
+	})
+
+	t.Run("should not update the message of another tenant", func(t *testing.T) {
+		// Arrange
+		created, err := repo.CreateMessage(tenant.NewContext(ctx, "acme"), &models.Message{Text: "hello"})
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.UpdateMessage(
+			tenant.NewContext(ctx, "globex"),
+			&models.Message{ID: created.ID, Text: "hijacked"},
+			1,
+		)
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+	})
+}
+
+func TestInMemoryMessageRepository_PurgeDeletedMessages(t *testing.T) {
+	t.Run("should only purge the messages deleted before the given time in every tenant", func(t *testing.T) {
+		// Arrange
+		ctx := t.Context()
+		repo := repository.NewInMemoryMessageRepository()
+		cutoff := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		old, err := repo.CreateMessage(
+			tenant.NewContext(ctx, "acme"),
+			&models.Message{DeletedAt: cutoff.Add(-time.Hour)},
+		)
+		require.NoError(t, err)
+		recent, err := repo.CreateMessage(ctx, &models.Message{DeletedAt: cutoff.Add(time.Hour)})
+		require.NoError(t, err)
+		live, err := repo.CreateMessage(ctx, &models.Message{})
+		require.NoError(t, err)
+
+		// Act
+		purged, err := repo.PurgeDeletedMessages(ctx, cutoff)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		_, err = repo.GetMessageByID(tenant.NewContext(ctx, "acme"), old.ID)
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		_, err = repo.GetMessageByID(ctx, recent.ID)
+		require.NoError(t, err)
+		_, err = repo.GetMessageByID(ctx, live.ID)
+		require.NoError(t, err)
+	})
+}
+
+func TestPurge(t *testing.T) {
+	t.Run("should purge the messages deleted for longer than the window", func(t *testing.T) {
+		// Arrange
+		ctx := t.Context()
+		repo := repository.NewInMemoryMessageRepository()
+		now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+		expired, err := repo.CreateMessage(ctx, &models.Message{DeletedAt: now.Add(-48 * time.Hour)})
+		require.NoError(t, err)
+		restorable, err := repo.CreateMessage(ctx, &models.Message{DeletedAt: now.Add(-12 * time.Hour)})
+		require.NoError(t, err)
+
+		// Act
+		repository.Purge(ctx, repo, clock.Fixed(now), 24*time.Hour)
+
+		// Assert
+		_, err = repo.GetMessageByID(ctx, expired.ID)
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		_, err = repo.GetMessageByID(ctx, restorable.ID)
+		require.NoError(t, err)
+	})
+}
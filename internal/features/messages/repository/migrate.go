@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migration is a versioned schema change. Its version is the numeric prefix of the
+// file name, e.g. 1 for 0001_create_messages.sql.
+type Migration struct {
+	Name    string
+	SQL     string
+	Version int
+}
+
+// Migrations returns the embedded migrations, sorted by version.
+func Migrations() ([]Migration, error) {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Migration, 0, len(names))
+	for _, name := range names {
+		base := path.Base(name)
+		prefix, _, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no version prefix", base)
+		}
+		data, err := migrations.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, Migration{Name: base, SQL: string(data), Version: version})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Migrate applies the migrations that were not applied to db yet, each one in its own
+// transaction, and returns how many it applied.
+func Migrate(ctx context.Context, db *sql.DB) (int, error) {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT    NOT NULL,
+		applied_at TEXT    NOT NULL
+	)`); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	pending, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range pending {
+		done, err := applyMigration(ctx, db, migration)
+		if err != nil {
+			return applied, fmt.Errorf("failed to apply migration %s: %w", migration.Name, err)
+		}
+		if done {
+			applied++
+			slog.InfoContext(ctx, "applied migration", slog.String("migration", migration.Name))
+		}
+	}
+	return applied, nil
+}
+
+// applyMigration applies migration unless it was already applied, and reports
+// whether it applied it.
+func applyMigration(ctx context.Context, db *sql.DB, migration Migration) (bool, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op once committed
+
+	var exists int
+	err = tx.QueryRowContext(ctx, "SELECT 1 FROM schema_migrations WHERE version = ?", migration.Version).Scan(&exists)
+	if err == nil {
+		return false, nil
+	}
+	if err != sql.ErrNoRows {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx, migration.SQL); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)",
+		migration.Version, migration.Name, formatTime(time.Now()),
+	); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
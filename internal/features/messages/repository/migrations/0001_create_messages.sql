@@ -0,0 +1,16 @@
+CREATE TABLE messages (
+    tenant_id  TEXT    NOT NULL,
+    id         TEXT    NOT NULL,
+    text       TEXT    NOT NULL,
+    author_id  TEXT    NOT NULL DEFAULT '',
+    tags       TEXT    NOT NULL DEFAULT 'null',
+    created_at TEXT    NOT NULL,
+    updated_at TEXT    NOT NULL,
+    deleted_at TEXT,
+    version    INTEGER NOT NULL,
+    PRIMARY KEY (tenant_id, id)
+) WITHOUT ROWID;
+
+CREATE INDEX messages_listing ON messages (tenant_id, created_at, id);
+
+CREATE INDEX messages_deleted ON messages (deleted_at) WHERE deleted_at IS NOT NULL;
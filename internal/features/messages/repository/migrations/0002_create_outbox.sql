@@ -0,0 +1,25 @@
+CREATE TABLE outbox (
+    sequence        INTEGER PRIMARY KEY AUTOINCREMENT,
+    id              TEXT    NOT NULL,
+    tenant_id       TEXT    NOT NULL,
+    type            TEXT    NOT NULL,
+    payload         BLOB    NOT NULL,
+    created_at      TEXT    NOT NULL,
+    next_attempt_at TEXT    NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT    NOT NULL DEFAULT ''
+);
+
+CREATE INDEX outbox_due ON outbox (next_attempt_at);
+
+CREATE TABLE outbox_dead_letters (
+    sequence        INTEGER PRIMARY KEY,
+    id              TEXT    NOT NULL,
+    tenant_id       TEXT    NOT NULL,
+    type            TEXT    NOT NULL,
+    payload         BLOB    NOT NULL,
+    created_at      TEXT    NOT NULL,
+    next_attempt_at TEXT    NOT NULL,
+    attempts        INTEGER NOT NULL,
+    last_error      TEXT    NOT NULL
+);
@@ -0,0 +1,5 @@
+ALTER TABLE outbox ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE outbox ADD COLUMN traceparent TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE outbox_dead_letters ADD COLUMN request_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE outbox_dead_letters ADD COLUMN traceparent TEXT NOT NULL DEFAULT '';
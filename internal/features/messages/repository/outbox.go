@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/requestid"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// traceParentHeader is the W3C Trace Context header a trace is carried by.
+const traceParentHeader = "traceparent"
+
+// OutboxEvent is a domain event that a repository recorded in the transaction of the
+// change that raised it, so that the event is not lost if the process dies before it
+// is published.
+type OutboxEvent struct {
+	CreatedAt time.Time
+	// NextAttemptAt is when the event is due for delivery.
+	NextAttemptAt time.Time
+	TenantID      string
+	// Type identifies the event for events.Dispatch, e.g. events.MessageCreatedType.
+	Type string
+	// LastError is the error of the last failed delivery attempt.
+	LastError string
+	// RequestID is the ID of the request that raised the event, if any.
+	RequestID string
+	// TraceParent is the W3C traceparent of the span that raised the event, if any, so
+	// that the delivery joins its trace.
+	TraceParent string
+	Payload     []byte
+	// Attempts is the number of failed delivery attempts.
+	Attempts int
+	// Sequence orders the events of a store and identifies them within it.
+	Sequence uint64
+	// ID identifies the event across stores, so that the subscribers can drop the
+	// duplicates of an at-least-once delivery.
+	ID uuid.UUID
+}
+
+// IOutboxRepository is implemented by the repositories that record a
+// events.MessageCreatedEvent in an outbox in the transaction creating each message.
+// The events of the outbox are delivered by a relay, which acknowledges them.
+// Operations on a sequence that is not in the outbox do nothing.
+type IOutboxRepository interface {
+	// PendingEvents returns up to limit events due at now, in sequence order.
+	PendingEvents(ctx context.Context, now time.Time, limit int) ([]*OutboxEvent, error)
+	// AckEvent removes a delivered event from the outbox.
+	AckEvent(ctx context.Context, sequence uint64) error
+	// RetryEvent records a failed delivery attempt and when to make the next one.
+	RetryEvent(ctx context.Context, sequence uint64, nextAttemptAt time.Time, lastError string) error
+	// DeadLetterEvent records a last failed delivery attempt and moves the event to
+	// the dead letters, where it is no longer delivered.
+	DeadLetterEvent(ctx context.Context, sequence uint64, lastError string) error
+	// DeadLetters returns up to limit dead letters, in sequence order.
+	DeadLetters(ctx context.Context, limit int) ([]*OutboxEvent, error)
+}
+
+// messageCreatedEvent returns the outbox event of the creation of message, due
+// at once, with the request ID and the trace of ctx.
+func messageCreatedEvent(ctx context.Context, message *models.Message) (*OutboxEvent, error) {
+	payload, err := json.Marshal(events.NewMessageCreatedEvent(message))
+	if err != nil {
+		return nil, err
+	}
+	requestID, _ := requestid.FromContext(ctx)
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		TenantID:      message.TenantID,
+		Type:          events.MessageCreatedType,
+		RequestID:     requestID,
+		TraceParent:   carrier.Get(traceParentHeader),
+		Payload:       payload,
+		CreatedAt:     message.CreatedAt,
+		NextAttemptAt: message.CreatedAt,
+	}, nil
+}
+
+// Context returns ctx with the request ID and the trace the event was raised in, so
+// that its delivery can be correlated with the request.
+func (e *OutboxEvent) Context(ctx context.Context) context.Context {
+	if e.RequestID != "" {
+		ctx = requestid.NewContext(ctx, e.RequestID)
+	}
+	if e.TraceParent != "" {
+		ctx = propagation.TraceContext{}.Extract(ctx, propagation.MapCarrier{traceParentHeader: e.TraceParent})
+	}
+	return ctx
+}
@@ -0,0 +1,187 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/events"
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/requestid"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// outboxRepository is a message repository with an outbox.
+type outboxRepository interface {
+	repository.IMessageRepository
+	repository.IOutboxRepository
+}
+
+func TestOutbox(t *testing.T) {
+	for name, newRepository := range map[string]func(t *testing.T) outboxRepository{
+		"sqlite": func(t *testing.T) outboxRepository { return newSQLiteRepository(t) },
+		"bolt": func(t *testing.T) outboxRepository {
+			return newBoltRepository(t, filepath.Join(t.TempDir(), "messages.bolt"))
+		},
+		"event sourced": func(t *testing.T) outboxRepository {
+			return newEventSourcedRepository(t, filepath.Join(t.TempDir(), "events.bolt"), 0)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			testOutbox(t, newRepository)
+		})
+	}
+}
+
+func testOutbox(t *testing.T, newRepository func(t *testing.T) outboxRepository) {
+	t.Run("should record an event per created message", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		acme := tenant.NewContext(t.Context(), "acme")
+
+		// Act
+		single, err := repo.CreateMessage(acme, &models.Message{Text: "a", AuthorID: "user-1", CreatedAt: base})
+		require.NoError(t, err)
+		batch, err := repo.CreateMessages(
+			acme,
+			[]*models.Message{{Text: "b", CreatedAt: base}, {Text: "c", CreatedAt: base}},
+		)
+		require.NoError(t, err)
+
+		// Assert
+		pending, err := repo.PendingEvents(t.Context(), base, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 3)
+		for i, message := range append([]*models.Message{single}, batch...) {
+			event := pending[i]
+			assert.Equal(t, events.MessageCreatedType, event.Type)
+			assert.Equal(t, "acme", event.TenantID)
+			assert.Equal(t, base, event.CreatedAt)
+			assert.Zero(t, event.Attempts)
+
+			var payload events.MessageCreatedEvent
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			assert.Equal(t, message.ID, payload.ID)
+			assert.Equal(t, "acme", payload.TenantID)
+		}
+		assert.Equal(t, "user-1", authorOf(t, pending[0]))
+		assert.Less(t, pending[0].Sequence, pending[1].Sequence)
+		assert.NotEqual(t, pending[0].ID, pending[1].ID)
+	})
+
+	t.Run("should only return the events due and up to the limit", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		seed(t, repo, 3)
+
+		// Act
+		none, errNone := repo.PendingEvents(t.Context(), base.Add(-time.Second), 10)
+		limited, errLimited := repo.PendingEvents(t.Context(), base.Add(time.Hour), 2)
+
+		// Assert
+		require.NoError(t, errNone)
+		require.NoError(t, errLimited)
+		assert.Empty(t, none)
+		assert.Len(t, limited, 2)
+	})
+
+	t.Run("should acknowledge, retry and dead-letter events", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		seed(t, repo, 3)
+		pending, err := repo.PendingEvents(t.Context(), base.Add(time.Hour), 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 3)
+		retryAt := base.Add(2 * time.Hour)
+
+		// Act
+		require.NoError(t, repo.AckEvent(t.Context(), pending[0].Sequence))
+		require.NoError(t, repo.RetryEvent(t.Context(), pending[1].Sequence, retryAt, "unavailable"))
+		require.NoError(t, repo.DeadLetterEvent(t.Context(), pending[2].Sequence, "poison"))
+		require.NoError(t, repo.AckEvent(t.Context(), pending[2].Sequence))
+
+		// Assert
+		due, err := repo.PendingEvents(t.Context(), base.Add(time.Hour), 10)
+		require.NoError(t, err)
+		assert.Empty(t, due)
+
+		retried, err := repo.PendingEvents(t.Context(), retryAt, 10)
+		require.NoError(t, err)
+		require.Len(t, retried, 1)
+		assert.Equal(t, pending[1].ID, retried[0].ID)
+		assert.Equal(t, 1, retried[0].Attempts)
+		assert.Equal(t, "unavailable", retried[0].LastError)
+		assert.Equal(t, retryAt, retried[0].NextAttemptAt)
+
+		dead, err := repo.DeadLetters(t.Context(), 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, pending[2].ID, dead[0].ID)
+		assert.Equal(t, pending[2].Payload, dead[0].Payload)
+		assert.Equal(t, 1, dead[0].Attempts)
+		assert.Equal(t, "poison", dead[0].LastError)
+	})
+
+	t.Run("should record the request ID and the trace of the creation", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{
+				0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36,
+			},
+			SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+			TraceFlags: trace.FlagsSampled,
+		})
+		ctx := trace.ContextWithSpanContext(requestid.NewContext(t.Context(), "req-1"), spanContext)
+
+		// Act
+		_, err := repo.CreateMessage(ctx, &models.Message{Text: "a", CreatedAt: base})
+		require.NoError(t, err)
+
+		// Assert
+		pending, err := repo.PendingEvents(t.Context(), base, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.Equal(t, "req-1", pending[0].RequestID)
+		assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", pending[0].TraceParent)
+
+		require.NoError(t, repo.DeadLetterEvent(t.Context(), pending[0].Sequence, "poison"))
+		dead, err := repo.DeadLetters(t.Context(), 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, "req-1", dead[0].RequestID)
+		assert.Equal(t, pending[0].TraceParent, dead[0].TraceParent)
+	})
+
+	t.Run("should record no event when the creation fails", func(t *testing.T) {
+		// Arrange
+		repo := newRepository(t)
+		existing, err := repo.CreateMessage(t.Context(), &models.Message{Text: "a", CreatedAt: base})
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.CreateMessages(t.Context(), []*models.Message{
+			{Text: "b", CreatedAt: base},
+			{ID: existing.ID, Text: "duplicate", CreatedAt: base},
+		})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageAlreadyExists)
+		pending, err := repo.PendingEvents(t.Context(), base, 10)
+		require.NoError(t, err)
+		assert.Len(t, pending, 1)
+	})
+}
+
+// authorOf returns the author in the payload of a message created event.
+func authorOf(t *testing.T, event *repository.OutboxEvent) string {
+	t.Helper()
+	var payload events.MessageCreatedEvent
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	return payload.AuthorID
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/arielsrv/fxf/pkg/config"
+
+	"go.uber.org/fx"
+)
+
+// PurgeConfig configures the permanent deletion of soft deleted messages.
+type PurgeConfig struct {
+	// Window is how long a deleted message can be restored before it is purged.
+	Window time.Duration
+	// Interval is how often the purge runs. Zero disables it.
+	Interval time.Duration
+}
+
+// NewPurgeConfig reads the purge configuration from FXF_MESSAGES_PURGE_* environment variables.
+func NewPurgeConfig() PurgeConfig {
+	return PurgeConfig{
+		Window:   config.Duration("FXF_MESSAGES_PURGE_WINDOW", 30*24*time.Hour),
+		Interval: config.Duration("FXF_MESSAGES_PURGE_INTERVAL", time.Hour),
+	}
+}
+
+// RegisterPurge purges the messages deleted for longer than the purge window every
+// interval while the application runs.
+func RegisterPurge(lc fx.Lifecycle, repo IMessageRepository, clock clock.Clock, cfg PurgeConfig) {
+	if cfg.Interval <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(cfg.Interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						Purge(ctx, repo, clock, cfg.Window)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+			case <-stopCtx.Done():
+			}
+			return nil
+		},
+	})
+}
+
+// Purge permanently deletes the messages deleted for longer than window.
+func Purge(ctx context.Context, repo IMessageRepository, clock clock.Clock, window time.Duration) {
+	purged, err := repo.PurgeDeletedMessages(ctx, clock.Now().Add(-window))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to purge deleted messages", slog.String("err", err.Error()))
+		return
+	}
+	if purged > 0 {
+		slog.InfoContext(ctx, "purged deleted messages", slog.Int("count", purged))
+	}
+}
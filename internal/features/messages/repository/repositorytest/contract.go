@@ -0,0 +1,515 @@
+// Package repositorytest provides the behavior contract shared by the
+// implementations of repository.IMessageRepository.
+package repositorytest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty repository. It is called once per contract test and
+// registers the cleanup of the repository with t.
+type Factory func(t *testing.T) repository.IMessageRepository
+
+var base = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// RunContract runs the behavior contract of repository.IMessageRepository against
+// the repositories of newRepository. Every implementation must pass it.
+func RunContract(t *testing.T, newRepository Factory) {
+	t.Run("should assign an ID, the tenant and the first version", func(t *testing.T) {
+		testIDAssignment(t, newRepository(t))
+	})
+	t.Run("should keep the fields of the messages", func(t *testing.T) {
+		testRoundTrip(t, newRepository(t))
+	})
+	t.Run("should refuse to create a message whose ID already exists in the tenant", func(t *testing.T) {
+		testExistingIDs(t, newRepository(t))
+	})
+	t.Run("should not find unknown messages or messages of another tenant", func(t *testing.T) {
+		testNotFound(t, newRepository(t))
+	})
+	t.Run("should update a message at the expected version only", func(t *testing.T) {
+		testVersionConflicts(t, newRepository(t))
+	})
+	t.Run("should purge the messages deleted before a time in every tenant", func(t *testing.T) {
+		testPurge(t, newRepository(t))
+	})
+	t.Run("should page in listing order, both ways", func(t *testing.T) {
+		testPagination(t, newRepository(t))
+	})
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		testConcurrency(t, newRepository(t))
+	})
+	t.Run("should fail with the context error once canceled", func(t *testing.T) {
+		testCancellation(t, newRepository(t))
+	})
+	t.Run("should neither keep nor change the messages it is given or returns", func(t *testing.T) {
+		testOwnership(t, newRepository(t))
+	})
+	t.Run("should return messages that can be changed concurrently", func(t *testing.T) {
+		testConcurrentChanges(t, newRepository(t))
+	})
+}
+
+func testIDAssignment(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	ctx := tenant.NewContext(t.Context(), "acme")
+	id := uuid.New()
+
+	// Act
+	generated, err := repo.CreateMessage(ctx, &models.Message{Text: "generated"})
+	require.NoError(t, err)
+	given, err := repo.CreateMessage(ctx, &models.Message{ID: id, Text: "given"})
+	require.NoError(t, err)
+	batch, err := repo.CreateMessages(ctx, []*models.Message{{Text: "first"}, {Text: "second"}})
+	require.NoError(t, err)
+
+	// Assert
+	assert.NotEqual(t, uuid.Nil, generated.ID)
+	assert.Equal(t, id, given.ID)
+	require.Len(t, batch, 2)
+	assert.NotEqual(t, batch[0].ID, batch[1].ID)
+	for _, message := range append([]*models.Message{generated, given}, batch...) {
+		assert.Equal(t, "acme", message.TenantID)
+		assert.Equal(t, int64(1), message.Version)
+	}
+}
+
+func testRoundTrip(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+
+	// Act
+	live, err := repo.CreateMessage(t.Context(), &models.Message{
+		Text:      "hello",
+		AuthorID:  "user-1",
+		Tags:      []string{"news", "go"},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	})
+	require.NoError(t, err)
+	deleted, err := repo.CreateMessage(
+		t.Context(),
+		&models.Message{Text: "bye", CreatedAt: createdAt, DeletedAt: deletedAt},
+	)
+	require.NoError(t, err)
+
+	// Assert
+	retrieved, err := repo.GetMessageByID(t.Context(), live.ID)
+	require.NoError(t, err)
+	assert.Equal(t, live.ID, retrieved.ID)
+	assert.Equal(t, "hello", retrieved.Text)
+	assert.Equal(t, "user-1", retrieved.AuthorID)
+	assert.Equal(t, []string{"news", "go"}, retrieved.Tags)
+	assert.Equal(t, tenant.DefaultID, retrieved.TenantID)
+	assert.True(t, createdAt.Equal(retrieved.CreatedAt), retrieved.CreatedAt)
+	assert.True(t, createdAt.Equal(retrieved.UpdatedAt), retrieved.UpdatedAt)
+	assert.False(t, retrieved.IsDeleted())
+
+	retrieved, err = repo.GetMessageByID(t.Context(), deleted.ID)
+	require.NoError(t, err, "soft deleted messages are still returned")
+	assert.True(t, deletedAt.Equal(retrieved.DeletedAt), retrieved.DeletedAt)
+}
+
+func testExistingIDs(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	acme := tenant.NewContext(t.Context(), "acme")
+	globex := tenant.NewContext(t.Context(), "globex")
+	existing, err := repo.CreateMessage(acme, &models.Message{Text: "v1", CreatedAt: base})
+	require.NoError(t, err)
+	edited := existing.Clone()
+	edited.Text = "v2"
+	existing, err = repo.UpdateMessage(acme, edited, 1)
+	require.NoError(t, err)
+	fresh, duplicate := uuid.New(), uuid.New()
+
+	// Act
+	_, single := repo.CreateMessage(acme, &models.Message{ID: existing.ID, Text: "overwritten"})
+	_, batch := repo.CreateMessages(acme, []*models.Message{
+		{ID: fresh, Text: "fresh"},
+		{ID: existing.ID, Text: "overwritten"},
+	})
+	_, duplicates := repo.CreateMessages(acme, []*models.Message{
+		{ID: duplicate, Text: "first"},
+		{ID: duplicate, Text: "second"},
+	})
+	otherTenant, err := repo.CreateMessage(globex, &models.Message{ID: existing.ID, Text: "globex"})
+
+	// Assert
+	require.ErrorIs(t, single, models.ErrMessageAlreadyExists)
+	require.ErrorIs(t, batch, models.ErrMessageAlreadyExists)
+	require.ErrorIs(t, duplicates, models.ErrMessageAlreadyExists)
+	require.NoError(t, err, "IDs are only unique within a tenant")
+	assert.Equal(t, int64(1), otherTenant.Version)
+
+	retrieved, err := repo.GetMessageByID(acme, existing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", retrieved.Text)
+	assert.Equal(t, int64(2), retrieved.Version)
+	_, err = repo.GetMessageByID(acme, fresh)
+	require.ErrorIs(t, err, models.ErrMessageNotFound, "a batch is created entirely or not at all")
+	_, err = repo.GetMessageByID(acme, duplicate)
+	require.ErrorIs(t, err, models.ErrMessageNotFound)
+}
+
+func testNotFound(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	acme := tenant.NewContext(t.Context(), "acme")
+	globex := tenant.NewContext(t.Context(), "globex")
+	first, err := repo.CreateMessage(acme, &models.Message{Text: "first"})
+	require.NoError(t, err)
+	second, err := repo.CreateMessage(acme, &models.Message{Text: "second"})
+	require.NoError(t, err)
+
+	// Act
+	_, unknown := repo.GetMessageByID(acme, uuid.New())
+	_, otherTenant := repo.GetMessageByID(globex, first.ID)
+	_, unknownUpdate := repo.UpdateMessage(acme, &models.Message{ID: uuid.New(), Text: "ghost"}, 1)
+	_, otherTenantUpdate := repo.UpdateMessage(globex, &models.Message{ID: first.ID, Text: "hijacked"}, 1)
+	batch, err := repo.GetMessagesByIDs(acme, []uuid.UUID{second.ID, uuid.New(), first.ID})
+	require.NoError(t, err)
+	otherBatch, err := repo.GetMessagesByIDs(globex, []uuid.UUID{first.ID, second.ID})
+	require.NoError(t, err)
+
+	// Assert
+	require.ErrorIs(t, unknown, models.ErrMessageNotFound)
+	require.ErrorIs(t, otherTenant, models.ErrMessageNotFound)
+	require.ErrorIs(t, unknownUpdate, models.ErrMessageNotFound)
+	require.ErrorIs(t, otherTenantUpdate, models.ErrMessageNotFound)
+	assert.Equal(t, []uuid.UUID{second.ID, first.ID}, ids(batch))
+	assert.Empty(t, otherBatch)
+
+	stored, err := repo.GetMessageByID(acme, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "first", stored.Text)
+}
+
+func testVersionConflicts(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "v1", CreatedAt: base})
+	require.NoError(t, err)
+
+	// Act
+	updated, err := repo.UpdateMessage(t.Context(), &models.Message{
+		ID:        created.ID,
+		Text:      "v2",
+		CreatedAt: base.Add(time.Hour),
+		UpdatedAt: base.Add(time.Minute),
+	}, 1)
+	require.NoError(t, err)
+	_, stale := repo.UpdateMessage(t.Context(), &models.Message{ID: created.ID, Text: "v3"}, 1)
+	_, ahead := repo.UpdateMessage(t.Context(), &models.Message{ID: created.ID, Text: "v3"}, 3)
+
+	// Assert
+	assert.Equal(t, int64(2), updated.Version)
+	require.ErrorIs(t, stale, models.ErrVersionConflict)
+	require.ErrorIs(t, ahead, models.ErrVersionConflict)
+
+	stored, err := repo.GetMessageByID(t.Context(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", stored.Text)
+	assert.Equal(t, int64(2), stored.Version)
+	assert.True(t, base.Equal(stored.CreatedAt), "the creation time cannot change")
+	assert.True(t, base.Add(time.Minute).Equal(stored.UpdatedAt), stored.UpdatedAt)
+}
+
+func testPurge(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	acme := tenant.NewContext(t.Context(), "acme")
+	cutoff := base.Add(24 * time.Hour)
+	expired, err := repo.CreateMessage(acme, &models.Message{Text: "expired", DeletedAt: cutoff.Add(-time.Hour)})
+	require.NoError(t, err)
+	expiredDefault, err := repo.CreateMessage(
+		t.Context(),
+		&models.Message{Text: "expired", DeletedAt: cutoff.Add(-time.Minute)},
+	)
+	require.NoError(t, err)
+	recent, err := repo.CreateMessage(acme, &models.Message{Text: "recent", DeletedAt: cutoff.Add(time.Hour)})
+	require.NoError(t, err)
+	live, err := repo.CreateMessage(acme, &models.Message{Text: "live"})
+	require.NoError(t, err)
+
+	// Act
+	purged, err := repo.PurgeDeletedMessages(t.Context(), cutoff)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, purged)
+	_, err = repo.GetMessageByID(acme, expired.ID)
+	require.ErrorIs(t, err, models.ErrMessageNotFound)
+	_, err = repo.GetMessageByID(t.Context(), expiredDefault.ID)
+	require.ErrorIs(t, err, models.ErrMessageNotFound)
+
+	listed, err := repo.ListMessages(acme, repository.ListMessagesOptions{
+		Limit:  10,
+		Filter: repository.MessageFilter{IncludeDeleted: true},
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{recent.ID, live.ID}, ids(listed.Messages))
+}
+
+func testPagination(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange: messages created three at a time, so that the IDs break the ties.
+	var messages []*models.Message
+	for i := range 10 {
+		message, err := repo.CreateMessage(t.Context(), &models.Message{
+			Text:      "message",
+			CreatedAt: base.Add(time.Duration(i/3) * time.Minute),
+		})
+		require.NoError(t, err)
+		messages = append(messages, message)
+	}
+	_, err := repo.CreateMessage(tenant.NewContext(t.Context(), "acme"), &models.Message{Text: "other tenant"})
+	require.NoError(t, err)
+
+	slices.SortFunc(messages, func(a, b *models.Message) int {
+		return repository.KeyOf(a).Compare(repository.KeyOf(b))
+	})
+	expected := ids(messages)
+	reversed := slices.Clone(expected)
+	slices.Reverse(reversed)
+
+	// Act
+	ascending := walk(t, repo, false)
+	descending := walk(t, repo, true)
+
+	// Assert
+	assert.Equal(t, expected, flatten(ascending))
+	assert.Equal(t, reversed, flatten(descending))
+	assert.Len(t, ascending, 4)
+
+	// Reading before the first message of each page returns the previous page.
+	for i := 1; i < len(ascending); i++ {
+		key := repository.KeyOf(ascending[i][0])
+		previous, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 3, Before: &key})
+		require.NoError(t, err)
+		assert.Equal(t, ids(ascending[i-1]), ids(previous.Messages))
+		assert.Equal(t, i > 1, previous.HasMore)
+	}
+}
+
+// walk lists every message of the default tenant three at a time, following the
+// After key of each page.
+func walk(t *testing.T, repo repository.IMessageRepository, descending bool) [][]*models.Message {
+	t.Helper()
+	var pages [][]*models.Message
+	opts := repository.ListMessagesOptions{Limit: 3, Descending: descending}
+	for {
+		page, err := repo.ListMessages(t.Context(), opts)
+		require.NoError(t, err)
+		require.NotEmpty(t, page.Messages)
+		pages = append(pages, page.Messages)
+		if !page.HasMore {
+			return pages
+		}
+		key := repository.KeyOf(page.Messages[len(page.Messages)-1])
+		opts.After = &key
+	}
+}
+
+func testConcurrency(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	const workers, perWorker = 8, 10
+	contended, err := repo.CreateMessage(t.Context(), &models.Message{Text: "contended"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	updated, conflicts := 0, 0
+	errs := make(chan error, workers*(perWorker+2))
+
+	// Act
+	for range workers {
+		wg.Go(func() {
+			for range perWorker {
+				if _, err := repo.CreateMessage(t.Context(), &models.Message{Text: "concurrent"}); err != nil {
+					errs <- err
+				}
+			}
+			if _, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 5}); err != nil {
+				errs <- err
+			}
+
+			_, err := repo.UpdateMessage(t.Context(), &models.Message{ID: contended.ID, Text: "winner"}, 1)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				updated++
+			case assert.ErrorIs(t, err, models.ErrVersionConflict):
+				conflicts++
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, updated)
+	assert.Equal(t, workers-1, conflicts)
+
+	listed, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: workers*perWorker + 10})
+	require.NoError(t, err)
+	assert.Len(t, listed.Messages, workers*perWorker+1)
+	unique := make(map[uuid.UUID]struct{})
+	for _, message := range listed.Messages {
+		unique[message.ID] = struct{}{}
+	}
+	assert.Len(t, unique, workers*perWorker+1)
+}
+
+func testCancellation(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	stored, err := repo.CreateMessage(t.Context(), &models.Message{Text: "stored", DeletedAt: base})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	// Act
+	_, createErr := repo.CreateMessage(ctx, &models.Message{Text: "canceled"})
+	_, batchErr := repo.CreateMessages(ctx, []*models.Message{{Text: "canceled"}})
+	_, getErr := repo.GetMessageByID(ctx, stored.ID)
+	_, getBatchErr := repo.GetMessagesByIDs(ctx, []uuid.UUID{stored.ID})
+	_, updateErr := repo.UpdateMessage(ctx, &models.Message{ID: stored.ID, Text: "canceled"}, 1)
+	_, purgeErr := repo.PurgeDeletedMessages(ctx, base.Add(time.Hour))
+	_, listErr := repo.ListMessages(ctx, repository.ListMessagesOptions{Limit: 10})
+
+	// Assert
+	for _, err := range []error{createErr, batchErr, getErr, getBatchErr, updateErr, purgeErr, listErr} {
+		require.ErrorIs(t, err, context.Canceled)
+	}
+
+	listed, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{
+		Limit:  10,
+		Filter: repository.MessageFilter{IncludeDeleted: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, listed.Messages, 1, "a canceled call must not change the repository")
+	assert.Equal(t, "stored", listed.Messages[0].Text)
+	assert.Equal(t, int64(1), listed.Messages[0].Version)
+}
+
+func testOwnership(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	input := &models.Message{Text: "hello", Tags: []string{"news"}}
+	batchInput := []*models.Message{{Text: "batch", Tags: []string{"news"}}}
+
+	// Act
+	created, err := repo.CreateMessage(t.Context(), input)
+	require.NoError(t, err)
+	batch, err := repo.CreateMessages(t.Context(), batchInput)
+	require.NoError(t, err)
+	input.Text, input.Tags[0] = "changed input", "changed"
+	batchInput[0].Text, batchInput[0].Tags[0] = "changed input", "changed"
+	created.Text, created.Tags[0] = "changed result", "changed"
+	batch[0].Text, batch[0].Tags[0] = "changed result", "changed"
+
+	retrieved, err := repo.GetMessageByID(t.Context(), created.ID)
+	require.NoError(t, err)
+	retrieved.Tags[0] = "changed"
+	found, err := repo.GetMessagesByIDs(t.Context(), []uuid.UUID{created.ID})
+	require.NoError(t, err)
+	found[0].Tags[0] = "changed"
+	listed, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+	require.NoError(t, err)
+	for _, message := range listed.Messages {
+		message.Tags[0] = "changed"
+	}
+
+	update := &models.Message{ID: created.ID, Text: "updated", Tags: []string{"go"}}
+	updated, err := repo.UpdateMessage(t.Context(), update, 1)
+	require.NoError(t, err)
+	update.Tags[0] = "changed input"
+	updated.Tags[0] = "changed result"
+
+	// Assert
+	assert.Equal(t, uuid.Nil, input.ID, "the input is not changed")
+	assert.Zero(t, input.Version)
+	assert.Empty(t, input.TenantID)
+	assert.Equal(t, uuid.Nil, batchInput[0].ID)
+	assert.Zero(t, update.Version)
+	assert.Empty(t, update.TenantID)
+
+	stored, err := repo.GetMessageByID(t.Context(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "updated", stored.Text)
+	assert.Equal(t, []string{"go"}, stored.Tags)
+	assert.Equal(t, int64(2), stored.Version)
+
+	storedBatch, err := repo.GetMessageByID(t.Context(), batch[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "batch", storedBatch.Text)
+	assert.Equal(t, []string{"news"}, storedBatch.Tags)
+}
+
+func testConcurrentChanges(t *testing.T, repo repository.IMessageRepository) {
+	// Arrange
+	const workers = 8
+	created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "shared", Tags: []string{"news"}})
+	require.NoError(t, err)
+
+	// Act: under the race detector, any memory shared between the returned messages
+	// or with the repository is reported.
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Go(func() {
+			for range 20 {
+				retrieved, err := repo.GetMessageByID(t.Context(), created.ID)
+				if !assert.NoError(t, err) {
+					return
+				}
+				retrieved.Text = "changed"
+				retrieved.Tags[0] = "changed"
+
+				listed, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+				if !assert.NoError(t, err) {
+					return
+				}
+				for _, message := range listed.Messages {
+					message.Tags = append(message.Tags[:0], "changed", "again")
+				}
+				if i == 0 {
+					_, err := repo.CreateMessage(t.Context(), &models.Message{Text: "other", Tags: []string{"news"}})
+					assert.NoError(t, err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	// Assert
+	stored, err := repo.GetMessageByID(t.Context(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "shared", stored.Text)
+	assert.Equal(t, []string{"news"}, stored.Tags)
+}
+
+func ids(messages []*models.Message) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, message.ID)
+	}
+	return result
+}
+
+func flatten(pages [][]*models.Message) []uuid.UUID {
+	var result []uuid.UUID
+	for _, page := range pages {
+		result = append(result, ids(page)...)
+	}
+	return result
+}
@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/tenant"
+
+	"github.com/google/uuid"
+)
+
+// ShardedMessageRepository is an in-memory IMessageRepository that spreads the
+// messages over lock-striped shards by the hash of their ID, so that the writes to
+// different shards do not contend. A listing reads the shards one after the other
+// and merges their pages, so it costs more than with InMemoryMessageRepository and,
+// unlike it, may observe a write to a shard and miss an earlier write to another.
+// Prefer it for write-heavy workloads. Like InMemoryMessageRepository, it stores and
+// returns copies of the messages.
+type ShardedMessageRepository struct {
+	seed   maphash.Seed
+	shards []*shard
+}
+
+// shard holds the partitions of the messages whose ID hashes to it.
+type shard struct {
+	// partitions holds one partition per tenant ID.
+	partitions map[string]*partition
+	mu         sync.RWMutex
+}
+
+// partition returns the partition of the tenant, creating it if needed. The write
+// lock must be held.
+func (s *shard) partition(tenantID string) *partition {
+	p, ok := s.partitions[tenantID]
+	if !ok {
+		p = newPartition(tenantID)
+		s.partitions[tenantID] = p
+	}
+	return p
+}
+
+// NewShardedMessageRepository creates a ShardedMessageRepository with the given
+// number of shards, at least one.
+func NewShardedMessageRepository(shards int) *ShardedMessageRepository {
+	r := &ShardedMessageRepository{
+		seed:   maphash.MakeSeed(),
+		shards: make([]*shard, max(shards, 1)),
+	}
+	for i := range r.shards {
+		r.shards[i] = &shard{partitions: make(map[string]*partition)}
+	}
+	return r
+}
+
+func (r *ShardedMessageRepository) shardIndex(id uuid.UUID) int {
+	return int(maphash.Comparable(r.seed, id) % uint64(len(r.shards)))
+}
+
+func (r *ShardedMessageRepository) shardOf(id uuid.UUID) *shard {
+	return r.shards[r.shardIndex(id)]
+}
+
+// CreateMessage adds a new message to the shard of its ID.
+func (r *ShardedMessageRepository) CreateMessage(
+	ctx context.Context,
+	message *models.Message,
+) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	stored := message.Clone()
+	if stored.ID == uuid.Nil {
+		stored.ID = uuid.New()
+	}
+
+	s := r.shardOf(stored.ID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.partition(tenant.IDFromContext(ctx))
+	p.prepare(stored)
+	if err := p.checkNew(stored); err != nil {
+		return nil, err
+	}
+	p.put(stored)
+	return stored.Clone(), nil
+}
+
+// CreateMessages adds the messages holding the write locks of all their shards at
+// once, so that the batch becomes visible at once.
+func (r *ShardedMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	stored := make([]*models.Message, 0, len(messages))
+	locked := make([]bool, len(r.shards))
+	for _, message := range messages {
+		message = message.Clone()
+		if message.ID == uuid.Nil {
+			message.ID = uuid.New()
+		}
+		stored = append(stored, message)
+		locked[r.shardIndex(message.ID)] = true
+	}
+	// Shards are locked in index order, so that concurrent batches cannot deadlock.
+	for i, lock := range locked {
+		if lock {
+			r.shards[i].mu.Lock()
+			defer r.shards[i].mu.Unlock()
+		}
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(stored))
+	for _, message := range stored {
+		if _, ok := seen[message.ID]; ok {
+			return nil, errMessageExists(message.ID)
+		}
+		seen[message.ID] = struct{}{}
+		p := r.shardOf(message.ID).partition(tenantID)
+		p.prepare(message)
+		if err := p.checkNew(message); err != nil {
+			return nil, err
+		}
+	}
+	for _, message := range stored {
+		r.shardOf(message.ID).partition(tenantID).put(message)
+	}
+	return clones(stored), nil
+}
+
+// GetMessageByID retrieves a message of the context tenant from the shard of its ID.
+func (r *ShardedMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	message, ok := r.get(tenant.IDFromContext(ctx), id)
+	if !ok {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+	}
+	return message, nil
+}
+
+// GetMessagesByIDs retrieves the messages of the context tenant with the given IDs,
+// each from its shard.
+func (r *ShardedMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	messages := make([]*models.Message, 0, len(ids))
+	for _, id := range ids {
+		if message, ok := r.get(tenantID, id); ok {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+func (r *ShardedMessageRepository) get(tenantID string, id uuid.UUID) (*models.Message, bool) {
+	s := r.shardOf(id)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.partitions[tenantID]
+	if !ok {
+		return nil, false
+	}
+	message, ok := p.messages[id]
+	if !ok {
+		return nil, false
+	}
+	return message.Clone(), true
+}
+
+// UpdateMessage compares the stored version of the message with expectedVersion and
+// swaps in a copy of message when they match. The creation time of a message cannot
+// change.
+func (r *ShardedMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	s := r.shardOf(message.ID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.partitions[tenantID]
+	if !ok {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, message.ID)
+	}
+	stored, ok := p.messages[message.ID]
+	if !ok {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, message.ID)
+	}
+	if stored.Version != expectedVersion {
+		return nil, fmt.Errorf("%w: message %s is at version %d, not %d",
+			models.ErrVersionConflict, message.ID, stored.Version, expectedVersion)
+	}
+
+	updated := message.Clone()
+	updated.TenantID = tenantID
+	updated.CreatedAt = stored.CreatedAt
+	updated.Version = expectedVersion + 1
+	p.messages[updated.ID] = updated
+	return updated.Clone(), nil
+}
+
+// PurgeDeletedMessages removes the messages soft deleted before deletedBefore, one
+// shard at a time.
+func (r *ShardedMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, s := range r.shards {
+		s.mu.Lock()
+		for _, p := range s.partitions {
+			for _, message := range p.messages {
+				if message.IsDeleted() && message.DeletedAt.Before(deletedBefore) {
+					p.remove(message)
+					purged++
+				}
+			}
+		}
+		s.mu.Unlock()
+	}
+	return purged, nil
+}
+
+// ListMessages reads a page from the tenant partition of every shard and merges
+// them in index order.
+func (r *ShardedMessageRepository) ListMessages(
+	ctx context.Context,
+	opts ListMessagesOptions,
+) (*ListMessagesResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.Limit <= 0 {
+		return &ListMessagesResult{}, nil
+	}
+	tenantID := tenant.IDFromContext(ctx)
+
+	runs := make([][]*models.Message, 0, len(r.shards))
+	for _, s := range r.shards {
+		s.mu.RLock()
+		if p, ok := s.partitions[tenantID]; ok {
+			runs = append(runs, p.walk(opts, opts.Limit+1))
+		}
+		s.mu.RUnlock()
+	}
+	result := newListMessagesResult(merge(runs, opts.forward(), opts.Limit+1), opts)
+	result.Messages = clones(result.Messages)
+	return result, nil
+}
+
+// merge returns the first limit messages of runs, each already in the read order:
+// ascending keys when forward, descending otherwise.
+func merge(runs [][]*models.Message, forward bool, limit int) []*models.Message {
+	var messages []*models.Message
+	for len(messages) < limit {
+		next := -1
+		for i, run := range runs {
+			if len(run) == 0 {
+				continue
+			}
+			if next < 0 {
+				next = i
+				continue
+			}
+			c := KeyOf(run[0]).Compare(KeyOf(runs[next][0]))
+			if forward && c < 0 || !forward && c > 0 {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		messages = append(messages, runs[next][0])
+		runs[next] = runs[next][1:]
+	}
+	return messages
+}
@@ -0,0 +1,100 @@
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedMessageRepository_ListMessages(t *testing.T) {
+	t.Run("should merge the shards like the in-memory repository", func(t *testing.T) {
+		// Arrange
+		sharded := repository.NewShardedMessageRepository(4)
+		memory := repository.NewInMemoryMessageRepository()
+		seed(t, sharded, 6)
+		seed(t, memory, 6)
+
+		for _, opts := range []repository.ListMessagesOptions{
+			{Limit: 10},
+			{Limit: 2, Descending: true},
+			{Limit: 2, Filter: repository.MessageFilter{AuthorID: "user-2"}},
+			{Limit: 10, Filter: repository.MessageFilter{Tags: []string{"even"}}},
+		} {
+			// Act
+			fromSharded, err := sharded.ListMessages(t.Context(), opts)
+			require.NoError(t, err)
+			fromMemory, err := memory.ListMessages(t.Context(), opts)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, texts(fromMemory.Messages), texts(fromSharded.Messages))
+			assert.Equal(t, fromMemory.HasMore, fromSharded.HasMore)
+		}
+	})
+
+	t.Run("should work without a positive shard count", func(t *testing.T) {
+		// Arrange
+		repo := repository.NewShardedMessageRepository(0)
+
+		// Act
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "hello"})
+		require.NoError(t, err)
+		retrieved, err := repo.GetMessageByID(t.Context(), created.ID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "hello", retrieved.Text)
+	})
+}
+
+// BenchmarkMessageRepositories compares the in-memory repositories under concurrent
+// loads mixing creations with reads by ID and listings, at several write ratios.
+func BenchmarkMessageRepositories(b *testing.B) {
+	implementations := []struct {
+		newRepository func() repository.IMessageRepository
+		name          string
+	}{
+		{name: "memory", newRepository: repository.NewInMemoryMessageRepository},
+		{name: "sharded", newRepository: func() repository.IMessageRepository {
+			return repository.NewShardedMessageRepository(32)
+		}},
+	}
+
+	for _, writes := range []int{10, 50, 90} {
+		for _, implementation := range implementations {
+			b.Run(fmt.Sprintf("writes=%d%%/%s", writes, implementation.name), func(b *testing.B) {
+				repo := implementation.newRepository()
+				ids := make([]uuid.UUID, 1000)
+				for i := range ids {
+					message, err := repo.CreateMessage(b.Context(), &models.Message{Text: "seed"})
+					require.NoError(b, err)
+					ids[i] = message.ID
+				}
+
+				b.ResetTimer()
+				b.RunParallel(func(pb *testing.PB) {
+					for i := 0; pb.Next(); i++ {
+						var err error
+						switch {
+						case i%100 < writes:
+							_, err = repo.CreateMessage(b.Context(), &models.Message{Text: "benchmark"})
+						case i%10 == 0:
+							_, err = repo.ListMessages(b.Context(), repository.ListMessagesOptions{Limit: 20})
+						default:
+							_, err = repo.GetMessageByID(b.Context(), ids[i%len(ids)])
+						}
+						if err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
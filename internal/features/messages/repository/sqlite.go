@@ -0,0 +1,466 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/pkg/tenant"
+
+	"github.com/google/uuid"
+	"modernc.org/sqlite" // also registers the "sqlite" database/sql driver
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLiteConfig configures the SQLite message store.
+type SQLiteConfig struct {
+	// Path is the database file. Its directory is created if needed.
+	Path            string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	// BusyTimeout is how long a statement waits for the lock of another connection.
+	BusyTimeout time.Duration
+	// Migrate applies the pending migrations when the repository is created.
+	Migrate bool
+}
+
+// timeLayout formats the times as fixed-width UTC text, which sorts chronologically
+// and, unlike Unix nanoseconds, represents the zero time.
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+const messageColumns = "id, tenant_id, text, author_id, tags, created_at, updated_at, deleted_at, version"
+
+// SQLiteMessageRepository is an IMessageRepository that stores the messages in a
+// SQLite database, using the pure-Go modernc.org/sqlite driver. It is also an
+// IOutboxRepository.
+type SQLiteMessageRepository struct {
+	db          *sql.DB
+	insert      *sql.Stmt
+	insertEvent *sql.Stmt
+	selectByID  *sql.Stmt
+	update      *sql.Stmt
+	purge       *sql.Stmt
+}
+
+// OpenSQLite opens the database of cfg with its connection pool settings, in WAL
+// mode so that readers do not block the writer.
+func OpenSQLite(cfg SQLiteConfig) (*sql.DB, error) {
+	if dir := filepath.Dir(cfg.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create database directory: %w", err)
+		}
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", cfg.BusyTimeout.Milliseconds()))
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", cfg.Path, err)
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	return db, nil
+}
+
+// NewSQLiteMessageRepository opens the database of cfg, applies the pending
+// migrations if cfg.Migrate is set and prepares the statements of the repository.
+func NewSQLiteMessageRepository(ctx context.Context, cfg SQLiteConfig) (*SQLiteMessageRepository, error) {
+	db, err := OpenSQLite(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := newSQLiteMessageRepository(ctx, db, cfg.Migrate)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return repo, nil
+}
+
+func newSQLiteMessageRepository(ctx context.Context, db *sql.DB, migrate bool) (*SQLiteMessageRepository, error) {
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to the database: %w", err)
+	}
+	if migrate {
+		if _, err := Migrate(ctx, db); err != nil {
+			return nil, err
+		}
+	}
+
+	repo := &SQLiteMessageRepository{db: db}
+	for target, query := range map[**sql.Stmt]string{
+		&repo.insert: "INSERT INTO messages (" + messageColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		&repo.insertEvent: "INSERT INTO outbox " +
+			"(id, tenant_id, type, payload, created_at, next_attempt_at, request_id, traceparent) " +
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+		&repo.selectByID: "SELECT " + messageColumns + " FROM messages WHERE tenant_id = ? AND id = ?",
+		&repo.update: `UPDATE messages
+			SET text = ?, author_id = ?, tags = ?, updated_at = ?, deleted_at = ?, version = version + 1
+			WHERE tenant_id = ? AND id = ? AND version = ?`,
+		&repo.purge: "DELETE FROM messages WHERE deleted_at IS NOT NULL AND deleted_at < ?",
+	} {
+		stmt, err := db.PrepareContext(ctx, query)
+		if err != nil {
+			repo.closeStatements()
+			return nil, fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		*target = stmt
+	}
+	return repo, nil
+}
+
+// Close closes the prepared statements and the database.
+func (r *SQLiteMessageRepository) Close() error {
+	r.closeStatements()
+	return r.db.Close()
+}
+
+func (r *SQLiteMessageRepository) closeStatements() {
+	for _, stmt := range []*sql.Stmt{r.insert, r.insertEvent, r.selectByID, r.update, r.purge} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+}
+
+// CreateMessage inserts a new message in the context tenant.
+func (r *SQLiteMessageRepository) CreateMessage(ctx context.Context, message *models.Message) (*models.Message, error) {
+	created, err := r.CreateMessages(ctx, []*models.Message{message})
+	if err != nil {
+		return nil, err
+	}
+	return created[0], nil
+}
+
+// CreateMessages inserts the messages, and their events in the outbox, in a single
+// transaction.
+func (r *SQLiteMessageRepository) CreateMessages(
+	ctx context.Context,
+	messages []*models.Message,
+) ([]*models.Message, error) {
+	tenantID := tenant.IDFromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op once committed
+
+	insert, insertEvent := tx.StmtContext(ctx, r.insert), tx.StmtContext(ctx, r.insertEvent)
+	created := make([]*models.Message, 0, len(messages))
+	for _, message := range messages {
+		message = message.Clone()
+		if err := r.insertMessage(ctx, insert, insertEvent, tenantID, message); err != nil {
+			return nil, err
+		}
+		created = append(created, message)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to create messages: %w", err)
+	}
+	return created, nil
+}
+
+func (r *SQLiteMessageRepository) insertMessage(
+	ctx context.Context,
+	insert, insertEvent *sql.Stmt,
+	tenantID string,
+	message *models.Message,
+) error {
+	if message.ID == uuid.Nil {
+		message.ID = uuid.New()
+	}
+	message.TenantID = tenantID
+	message.Version = 1
+
+	tags, err := json.Marshal(message.Tags)
+	if err != nil {
+		return err
+	}
+	if _, err := insert.ExecContext(ctx,
+		message.ID.String(), tenantID, message.Text, message.AuthorID, string(tags),
+		formatTime(message.CreatedAt), formatTime(message.UpdatedAt), nullableTime(message.DeletedAt), message.Version,
+	); err != nil {
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY {
+			return errMessageExists(message.ID)
+		}
+		return fmt.Errorf("failed to create message %s: %w", message.ID, err)
+	}
+
+	event, err := messageCreatedEvent(ctx, message)
+	if err != nil {
+		return err
+	}
+	if _, err := insertEvent.ExecContext(ctx,
+		event.ID.String(), event.TenantID, event.Type, event.Payload,
+		formatTime(event.CreatedAt), formatTime(event.NextAttemptAt), event.RequestID, event.TraceParent,
+	); err != nil {
+		return fmt.Errorf("failed to record the event of message %s: %w", message.ID, err)
+	}
+	return nil
+}
+
+// GetMessageByID retrieves a message of the context tenant by its ID.
+func (r *SQLiteMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
+	message, err := scanMessage(r.selectByID.QueryRowContext(ctx, tenant.IDFromContext(ctx), id.String()).Scan)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// GetMessagesByIDs retrieves the messages of the context tenant with the given IDs
+// in a single query.
+func (r *SQLiteMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, 0, len(ids)+1)
+	args = append(args, tenant.IDFromContext(ctx))
+	for _, id := range ids {
+		args = append(args, id.String())
+	}
+	query := "SELECT " + messageColumns + " FROM messages WHERE tenant_id = ? AND id IN (?" +
+		strings.Repeat(", ?", len(ids)-1) + ")"
+
+	found, err := r.queryMessages(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*models.Message, len(found))
+	for _, message := range found {
+		byID[message.ID] = message
+	}
+	messages := make([]*models.Message, 0, len(found))
+	for _, id := range ids {
+		if message, ok := byID[id]; ok {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// UpdateMessage updates the message if it is still at expectedVersion. The creation
+// time of a message cannot change.
+func (r *SQLiteMessageRepository) UpdateMessage(
+	ctx context.Context,
+	message *models.Message,
+	expectedVersion int64,
+) (*models.Message, error) {
+	tenantID := tenant.IDFromContext(ctx)
+
+	tags, err := json.Marshal(message.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op once committed
+
+	stored, err := scanMessage(
+		tx.StmtContext(ctx, r.selectByID).QueryRowContext(ctx, tenantID, message.ID.String()).Scan,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: message with ID %s not found", models.ErrMessageNotFound, message.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.Version != expectedVersion {
+		return nil, fmt.Errorf("%w: message %s is at version %d, not %d",
+			models.ErrVersionConflict, message.ID, stored.Version, expectedVersion)
+	}
+
+	if _, err := tx.StmtContext(ctx, r.update).ExecContext(ctx,
+		message.Text, message.AuthorID, string(tags), formatTime(message.UpdatedAt), nullableTime(message.DeletedAt),
+		tenantID, message.ID.String(), expectedVersion,
+	); err != nil {
+		return nil, fmt.Errorf("failed to update message %s: %w", message.ID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to update message %s: %w", message.ID, err)
+	}
+
+	updated := message.Clone()
+	updated.TenantID = tenantID
+	updated.CreatedAt = stored.CreatedAt
+	updated.Version = expectedVersion + 1
+	return updated, nil
+}
+
+// PurgeDeletedMessages deletes the messages of every tenant soft deleted before
+// deletedBefore.
+func (r *SQLiteMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	result, err := r.purge.ExecContext(ctx, formatTime(deletedBefore))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted messages: %w", err)
+	}
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
+
+// ListMessages reads a page with a keyset query on the listing index.
+func (r *SQLiteMessageRepository) ListMessages(
+	ctx context.Context,
+	opts ListMessagesOptions,
+) (*ListMessagesResult, error) {
+	result := &ListMessagesResult{}
+	if opts.Limit <= 0 {
+		return result, nil
+	}
+
+	where := []string{"tenant_id = ?"}
+	args := []any{tenant.IDFromContext(ctx)}
+
+	filter := opts.Filter
+	if !filter.IncludeDeleted {
+		where = append(where, "deleted_at IS NULL")
+	}
+	if filter.AuthorID != "" {
+		where = append(where, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+	if !filter.CreatedAfter.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, formatTime(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, formatTime(filter.CreatedBefore))
+	}
+	for _, tag := range filter.Tags {
+		where = append(where, "EXISTS (SELECT 1 FROM json_each(messages.tags) WHERE json_each.value = ?)")
+		args = append(args, tag)
+	}
+
+	// Read forward in ascending key order for ascending pages read after a key and for
+	// descending pages read before a key; backward otherwise.
+	boundary := opts.After
+	if opts.Before != nil {
+		boundary = opts.Before
+	}
+	forward := opts.Descending == (opts.Before != nil)
+
+	order, comparison := "ASC", ">"
+	if !forward {
+		order, comparison = "DESC", "<"
+	}
+	if boundary != nil {
+		where = append(where, "(created_at, id) "+comparison+" (?, ?)")
+		args = append(args, formatTime(boundary.CreatedAt), boundary.ID.String())
+	}
+
+	query := "SELECT " + messageColumns + " FROM messages WHERE " + strings.Join(where, " AND ") +
+		" ORDER BY created_at " + order + ", id " + order + " LIMIT ?"
+	args = append(args, opts.Limit+1)
+
+	messages, err := r.queryMessages(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) > opts.Limit {
+		messages = messages[:opts.Limit]
+		result.HasMore = true
+	}
+	if opts.Before != nil {
+		// The page was read backward from its boundary: restore the listing order.
+		slices.Reverse(messages)
+	}
+	result.Messages = messages
+	return result, nil
+}
+
+func (r *SQLiteMessageRepository) queryMessages(
+	ctx context.Context,
+	query string,
+	args ...any,
+) ([]*models.Message, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*models.Message
+	for rows.Next() {
+		message, err := scanMessage(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// scanMessage reads a message with scan, the Scan method of a *sql.Row or *sql.Rows.
+func scanMessage(scan func(dest ...any) error) (*models.Message, error) {
+	var (
+		message              models.Message
+		id, tags             string
+		createdAt, updatedAt string
+		deletedAt            sql.NullString
+	)
+	if err := scan(
+		&id, &message.TenantID, &message.Text, &message.AuthorID, &tags,
+		&createdAt, &updatedAt, &deletedAt, &message.Version,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if message.ID, err = uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("invalid message ID %q: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(tags), &message.Tags); err != nil {
+		return nil, fmt.Errorf("invalid tags of message %s: %w", id, err)
+	}
+	if message.CreatedAt, err = time.Parse(timeLayout, createdAt); err != nil {
+		return nil, err
+	}
+	if message.UpdatedAt, err = time.Parse(timeLayout, updatedAt); err != nil {
+		return nil, err
+	}
+	if deletedAt.Valid {
+		if message.DeletedAt, err = time.Parse(timeLayout, deletedAt.String); err != nil {
+			return nil, err
+		}
+	}
+	return &message, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// nullableTime stores the zero time as NULL.
+func nullableTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return formatTime(t)
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const outboxColumns = "sequence, id, tenant_id, type, payload, created_at, next_attempt_at, attempts, last_error, " +
+	"request_id, traceparent"
+
+// PendingEvents returns up to limit events of the outbox due at now.
+func (r *SQLiteMessageRepository) PendingEvents(ctx context.Context, now time.Time, limit int) ([]*OutboxEvent, error) {
+	return r.queryEvents(ctx,
+		"SELECT "+outboxColumns+" FROM outbox WHERE next_attempt_at <= ? ORDER BY sequence LIMIT ?",
+		formatTime(now), limit,
+	)
+}
+
+// AckEvent deletes a delivered event from the outbox.
+func (r *SQLiteMessageRepository) AckEvent(ctx context.Context, sequence uint64) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM outbox WHERE sequence = ?", sequence); err != nil {
+		return fmt.Errorf("failed to acknowledge event %d: %w", sequence, err)
+	}
+	return nil
+}
+
+// RetryEvent counts a failed delivery attempt of an event and reschedules it.
+func (r *SQLiteMessageRepository) RetryEvent(
+	ctx context.Context,
+	sequence uint64,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	if _, err := r.db.ExecContext(ctx,
+		"UPDATE outbox SET attempts = attempts + 1, next_attempt_at = ?, last_error = ? WHERE sequence = ?",
+		formatTime(nextAttemptAt), lastError, sequence,
+	); err != nil {
+		return fmt.Errorf("failed to reschedule event %d: %w", sequence, err)
+	}
+	return nil
+}
+
+// DeadLetterEvent moves an event from the outbox to the dead letters in a single
+// transaction.
+func (r *SQLiteMessageRepository) DeadLetterEvent(ctx context.Context, sequence uint64, lastError string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op once committed
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO outbox_dead_letters ("+outboxColumns+") "+
+		"SELECT sequence, id, tenant_id, type, payload, created_at, next_attempt_at, attempts + 1, ?, "+
+		"request_id, traceparent "+
+		"FROM outbox WHERE sequence = ?",
+		lastError, sequence,
+	); err != nil {
+		return fmt.Errorf("failed to dead-letter event %d: %w", sequence, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM outbox WHERE sequence = ?", sequence); err != nil {
+		return fmt.Errorf("failed to dead-letter event %d: %w", sequence, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to dead-letter event %d: %w", sequence, err)
+	}
+	return nil
+}
+
+// DeadLetters returns up to limit dead letters.
+func (r *SQLiteMessageRepository) DeadLetters(ctx context.Context, limit int) ([]*OutboxEvent, error) {
+	return r.queryEvents(ctx,
+		"SELECT "+outboxColumns+" FROM outbox_dead_letters ORDER BY sequence LIMIT ?",
+		limit,
+	)
+}
+
+func (r *SQLiteMessageRepository) queryEvents(ctx context.Context, query string, args ...any) ([]*OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*OutboxEvent
+	for rows.Next() {
+		var (
+			event                    OutboxEvent
+			id                       string
+			createdAt, nextAttemptAt string
+		)
+		if err := rows.Scan(
+			&event.Sequence, &id, &event.TenantID, &event.Type, &event.Payload,
+			&createdAt, &nextAttemptAt, &event.Attempts, &event.LastError,
+			&event.RequestID, &event.TraceParent,
+		); err != nil {
+			return nil, err
+		}
+		if event.ID, err = uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid event ID %q: %w", id, err)
+		}
+		if event.CreatedAt, err = time.Parse(timeLayout, createdAt); err != nil {
+			return nil, err
+		}
+		if event.NextAttemptAt, err = time.Parse(timeLayout, nextAttemptAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,243 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteRepository(t *testing.T) *repository.SQLiteMessageRepository {
+	t.Helper()
+	repo, err := repository.NewSQLiteMessageRepository(t.Context(), repository.SQLiteConfig{
+		Path:         filepath.Join(t.TempDir(), "messages.db"),
+		MaxOpenConns: 2,
+		MaxIdleConns: 2,
+		BusyTimeout:  time.Second,
+		Migrate:      true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestSQLiteMessageRepository(t *testing.T) {
+	t.Run("should create and get a message", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+
+		// Act
+		created, err := repo.CreateMessage(t.Context(), &models.Message{
+			Text:      "hello",
+			AuthorID:  "user-1",
+			Tags:      []string{"news"},
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		})
+		require.NoError(t, err)
+		retrieved, err := repo.GetMessageByID(t.Context(), created.ID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, created.ID)
+		assert.Equal(t, created, retrieved)
+		assert.Equal(t, tenant.DefaultID, retrieved.TenantID)
+		assert.Equal(t, int64(1), retrieved.Version)
+		assert.False(t, retrieved.IsDeleted())
+	})
+
+	t.Run("should keep the given ID and the zero times", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		id := uuid.New()
+
+		// Act
+		_, err := repo.CreateMessage(t.Context(), &models.Message{ID: id, Text: "hello"})
+		require.NoError(t, err)
+		retrieved, err := repo.GetMessageByID(t.Context(), id)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, retrieved.CreatedAt.IsZero())
+		assert.Nil(t, retrieved.Tags)
+	})
+
+	t.Run("should not find a message of another tenant", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		created, err := repo.CreateMessage(tenant.NewContext(t.Context(), "acme"), &models.Message{Text: "secret"})
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.GetMessageByID(t.Context(), created.ID)
+		batch, batchErr := repo.GetMessagesByIDs(t.Context(), []uuid.UUID{created.ID})
+
+		// Assert
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		require.NoError(t, batchErr)
+		assert.Empty(t, batch)
+	})
+
+	t.Run("should update a message at the expected version only", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		created, err := repo.CreateMessage(t.Context(), &models.Message{Text: "v1", CreatedAt: createdAt})
+		require.NoError(t, err)
+
+		// Act
+		updated, err := repo.UpdateMessage(
+			t.Context(),
+			&models.Message{ID: created.ID, Text: "v2", DeletedAt: createdAt},
+			1,
+		)
+		require.NoError(t, err)
+		_, conflict := repo.UpdateMessage(t.Context(), &models.Message{ID: created.ID, Text: "v3"}, 1)
+		_, missing := repo.UpdateMessage(t.Context(), &models.Message{ID: uuid.New()}, 1)
+		stored, getErr := repo.GetMessageByID(t.Context(), created.ID)
+
+		// Assert
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Equal(t, createdAt, updated.CreatedAt)
+		require.ErrorIs(t, conflict, models.ErrVersionConflict)
+		require.ErrorIs(t, missing, models.ErrMessageNotFound)
+		require.NoError(t, getErr)
+		assert.Equal(t, "v2", stored.Text)
+		assert.Equal(t, createdAt, stored.DeletedAt)
+	})
+
+	t.Run("should create a batch and get it in the order of the IDs", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		created, err := repo.CreateMessages(t.Context(), []*models.Message{{Text: "first"}, {Text: "second"}})
+		require.NoError(t, err)
+
+		// Act
+		messages, err := repo.GetMessagesByIDs(t.Context(), []uuid.UUID{created[1].ID, uuid.New(), created[0].ID})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "second", messages[0].Text)
+		assert.Equal(t, "first", messages[1].Text)
+	})
+
+	t.Run("should create no message of a failed batch", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		id := uuid.New()
+
+		// Act
+		_, err := repo.CreateMessages(t.Context(), []*models.Message{{Text: "first"}, {ID: id}, {ID: id}})
+		messages, listErr := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+
+		// Assert
+		require.Error(t, err)
+		require.NoError(t, listErr)
+		assert.Empty(t, messages.Messages)
+	})
+
+	t.Run("should purge the messages deleted before a time", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		deletedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		_, err := repo.CreateMessages(t.Context(), []*models.Message{
+			{Text: "old", DeletedAt: deletedAt},
+			{Text: "recent", DeletedAt: deletedAt.Add(time.Hour)},
+			{Text: "live"},
+		})
+		require.NoError(t, err)
+
+		// Act
+		purged, err := repo.PurgeDeletedMessages(t.Context(), deletedAt.Add(time.Minute))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+}
+
+func TestSQLiteMessageRepository_ListMessages(t *testing.T) {
+	t.Run("should page and filter like the in-memory repository", func(t *testing.T) {
+		// Arrange
+		sqlite := newSQLiteRepository(t)
+		memory := repository.NewInMemoryMessageRepository()
+		seed(t, sqlite, 6)
+		seed(t, memory, 6)
+		middle := base.Add(3 * time.Minute)
+
+		for _, opts := range []repository.ListMessagesOptions{
+			{Limit: 10},
+			{Limit: 2, Descending: true},
+			{Limit: 2, Filter: repository.MessageFilter{AuthorID: "user-2"}},
+			{Limit: 10, Filter: repository.MessageFilter{Tags: []string{"even"}}},
+			{Limit: 10, Filter: repository.MessageFilter{CreatedAfter: base.Add(time.Minute), CreatedBefore: middle}},
+		} {
+			// Act
+			fromSQLite, err := sqlite.ListMessages(t.Context(), opts)
+			require.NoError(t, err)
+			fromMemory, err := memory.ListMessages(t.Context(), opts)
+			require.NoError(t, err)
+
+			// Assert
+			assert.Equal(t, texts(fromMemory.Messages), texts(fromSQLite.Messages))
+			assert.Equal(t, fromMemory.HasMore, fromSQLite.HasMore)
+		}
+	})
+
+	t.Run("should read the pages after and before a key", func(t *testing.T) {
+		// Arrange
+		repo := newSQLiteRepository(t)
+		messages := seed(t, repo, 5)
+		key := repository.KeyOf(messages[2])
+
+		// Act
+		after, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 1, After: &key})
+		require.NoError(t, err)
+		before, err := repo.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 2, Before: &key})
+		require.NoError(t, err)
+		beforeDesc, err := repo.ListMessages(
+			t.Context(),
+			repository.ListMessagesOptions{Limit: 5, Before: &key, Descending: true},
+		)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, "d", texts(after.Messages))
+		assert.True(t, after.HasMore)
+		assert.Equal(t, "ab", texts(before.Messages))
+		assert.False(t, before.HasMore)
+		assert.Equal(t, "ed", texts(beforeDesc.Messages))
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("should apply each migration once", func(t *testing.T) {
+		// Arrange
+		db, err := repository.OpenSQLite(repository.SQLiteConfig{
+			Path:         filepath.Join(t.TempDir(), "messages.db"),
+			MaxOpenConns: 1,
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		migrations, err := repository.Migrations()
+		require.NoError(t, err)
+
+		// Act
+		first, errFirst := repository.Migrate(t.Context(), db)
+		second, errSecond := repository.Migrate(t.Context(), db)
+
+		// Assert
+		require.NoError(t, errFirst)
+		require.NoError(t, errSecond)
+		assert.Equal(t, len(migrations), first)
+		assert.Equal(t, 0, second)
+	})
+}
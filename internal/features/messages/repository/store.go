@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/config"
+
+	"go.uber.org/fx"
+)
+
+// Kinds of message store.
+const (
+	StoreMemory       = "memory"
+	StoreSharded      = "sharded"
+	StoreSQLite       = "sqlite"
+	StoreBolt         = "bolt"
+	StoreEventSourced = "eventsourced"
+)
+
+// StoreConfig selects and configures the IMessageRepository implementation.
+type StoreConfig struct {
+	// Store is StoreMemory, StoreSharded, StoreSQLite, StoreBolt or StoreEventSourced.
+	Store string
+	// BackupDir is the directory of the backups of the stores implementing
+	// IBackupRepository.
+	BackupDir string
+	// WAL makes the memory store durable when its Dir is set.
+	WAL WALConfig
+	// Shards is the number of shards of the sharded store.
+	Shards       int
+	SQLite       SQLiteConfig
+	Bolt         BoltConfig
+	EventSourced EventSourcedConfig
+}
+
+// NewStoreConfig reads the store configuration from FXF_MESSAGES_* environment variables.
+func NewStoreConfig() StoreConfig {
+	return StoreConfig{
+		Store:     config.String("FXF_MESSAGES_STORE", StoreMemory),
+		BackupDir: config.String("FXF_MESSAGES_BACKUP_DIR", "data/backups"),
+		WAL: WALConfig{
+			Dir:              config.String("FXF_MESSAGES_WAL_DIR", ""),
+			Fsync:            config.String("FXF_MESSAGES_WAL_FSYNC", FsyncInterval),
+			FsyncInterval:    config.Duration("FXF_MESSAGES_WAL_FSYNC_INTERVAL", time.Second),
+			SnapshotInterval: config.Duration("FXF_MESSAGES_SNAPSHOT_INTERVAL", 5*time.Minute),
+		},
+		Shards: config.Int("FXF_MESSAGES_SHARDS", 32),
+		SQLite: SQLiteConfig{
+			Path:            config.String("FXF_MESSAGES_SQLITE_PATH", "data/messages.db"),
+			MaxOpenConns:    config.Int("FXF_MESSAGES_SQLITE_MAX_OPEN_CONNS", 4),
+			MaxIdleConns:    config.Int("FXF_MESSAGES_SQLITE_MAX_IDLE_CONNS", 4),
+			ConnMaxLifetime: config.Duration("FXF_MESSAGES_SQLITE_CONN_MAX_LIFETIME", time.Hour),
+			BusyTimeout:     config.Duration("FXF_MESSAGES_SQLITE_BUSY_TIMEOUT", 5*time.Second),
+			Migrate:         config.Bool("FXF_MESSAGES_SQLITE_MIGRATE", true),
+		},
+		Bolt: BoltConfig{
+			Path:        config.String("FXF_MESSAGES_BOLT_PATH", "data/messages.bolt"),
+			OpenTimeout: config.Duration("FXF_MESSAGES_BOLT_OPEN_TIMEOUT", 5*time.Second),
+		},
+		EventSourced: EventSourcedConfig{
+			Path:             config.String("FXF_MESSAGES_EVENTS_PATH", "data/messages-events.bolt"),
+			OpenTimeout:      config.Duration("FXF_MESSAGES_EVENTS_OPEN_TIMEOUT", 5*time.Second),
+			SnapshotInterval: config.Int("FXF_MESSAGES_EVENTS_SNAPSHOT_INTERVAL", 100),
+		},
+	}
+}
+
+// NewMessageRepository creates the configured IMessageRepository. The write-ahead
+// log and the SQLite and bbolt databases are opened here, before the server starts,
+// and closed when the application stops, after the server.
+func NewMessageRepository(lc fx.Lifecycle, cfg StoreConfig) (IMessageRepository, error) {
+	switch {
+	case cfg.Store == StoreMemory && cfg.WAL.Dir == "":
+		return NewInMemoryMessageRepository(), nil
+	case cfg.Store == StoreMemory:
+		repo, err := OpenInMemoryMessageRepository(cfg.WAL)
+		if err != nil {
+			return nil, err
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				return repo.Close()
+			},
+		})
+		return repo, nil
+	case cfg.Store == StoreSharded:
+		return NewShardedMessageRepository(cfg.Shards), nil
+	case cfg.Store == StoreSQLite:
+		repo, err := NewSQLiteMessageRepository(context.Background(), cfg.SQLite)
+		if err != nil {
+			return nil, err
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				return repo.Close()
+			},
+		})
+		return repo, nil
+	case cfg.Store == StoreBolt:
+		repo, err := NewBoltMessageRepository(cfg.Bolt)
+		if err != nil {
+			return nil, err
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				return repo.Close()
+			},
+		})
+		return repo, nil
+	case cfg.Store == StoreEventSourced:
+		repo, err := NewEventSourcedMessageRepository(cfg.EventSourced)
+		if err != nil {
+			return nil, err
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(context.Context) error {
+				return repo.Close()
+			},
+		})
+		return repo, nil
+	default:
+		return nil, fmt.Errorf("unknown message store %q", cfg.Store)
+	}
+}
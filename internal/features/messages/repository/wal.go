@@ -0,0 +1,541 @@
+package repository
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+
+	"github.com/google/uuid"
+)
+
+// Fsync policies of the write-ahead log.
+const (
+	// FsyncAlways flushes every record to disk before the mutation is applied.
+	FsyncAlways = "always"
+	// FsyncInterval flushes the log every FsyncInterval: a crash loses at most the
+	// mutations of the last interval.
+	FsyncInterval = "interval"
+	// FsyncNever leaves the flushing to the operating system.
+	FsyncNever = "never"
+)
+
+// WALConfig configures the write-ahead log of a durable InMemoryMessageRepository.
+type WALConfig struct {
+	// Dir holds the log segments and the snapshot. An empty Dir keeps the messages
+	// in memory only.
+	Dir string
+	// Fsync is FsyncAlways, FsyncInterval or FsyncNever.
+	Fsync string
+	// FsyncInterval is how often the log is flushed with FsyncInterval.
+	FsyncInterval time.Duration
+	// SnapshotInterval is how often a snapshot is taken and the log compacted. Zero
+	// disables the periodic snapshots.
+	SnapshotInterval time.Duration
+}
+
+// ErrCorruptLog is returned when a log segment or the snapshot fails its checksum
+// anywhere but in a torn tail record.
+var ErrCorruptLog = errors.New("corrupt write-ahead log")
+
+// errTornRecord reports a record cut short at the end of a file, as left by a crash
+// while it was being appended.
+var errTornRecord = errors.New("torn record")
+
+const (
+	// recordHeaderSize is the size of the header of a record: the length and the
+	// CRC-32C of its payload, little endian.
+	recordHeaderSize = 8
+	segmentSuffix    = ".wal"
+	snapshotName     = "snapshot"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// walRecord is a logged mutation. The messages of a record are applied together.
+type walRecord struct {
+	Put    []walMessage `json:"put,omitempty"`
+	Delete []walKey     `json:"delete,omitempty"`
+}
+
+// walKey identifies a message of a tenant.
+type walKey struct {
+	TenantID string    `json:"tenant_id"`
+	ID       uuid.UUID `json:"id"`
+}
+
+// walMessage is the logged form of a message.
+type walMessage struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	DeletedAt time.Time `json:"deleted_at"`
+	Text      string    `json:"text"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	TenantID  string    `json:"tenant_id"`
+	Tags      []string  `json:"tags,omitempty"`
+	Version   int64     `json:"version"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func newWALMessage(message *models.Message) walMessage {
+	return walMessage{
+		CreatedAt: message.CreatedAt,
+		UpdatedAt: message.UpdatedAt,
+		DeletedAt: message.DeletedAt,
+		Text:      message.Text,
+		AuthorID:  message.AuthorID,
+		TenantID:  message.TenantID,
+		Tags:      message.Tags,
+		Version:   message.Version,
+		ID:        message.ID,
+	}
+}
+
+func (m walMessage) message() *models.Message {
+	return &models.Message{
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+		DeletedAt: m.DeletedAt,
+		Text:      m.Text,
+		AuthorID:  m.AuthorID,
+		TenantID:  m.TenantID,
+		Tags:      m.Tags,
+		Version:   m.Version,
+		ID:        m.ID,
+	}
+}
+
+// snapshotHeader is the first record of a snapshot. The snapshot holds the state
+// logged in the segments before Generation.
+type snapshotHeader struct {
+	Generation uint64 `json:"generation"`
+	Count      int    `json:"count"`
+}
+
+// writeAheadLog appends the mutations of the repository to numbered segment files.
+// A snapshot replaces the segments before its generation.
+type writeAheadLog struct {
+	cfg WALConfig
+	// snapshotMu serializes the snapshots.
+	snapshotMu sync.Mutex
+
+	// stopped is closed to stop the background work, which closes done once it returned.
+	stopped  chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	mu   sync.Mutex
+	file *os.File
+	// size is the size of the records of the current segment.
+	size       int64
+	generation uint64
+	dirty      bool
+	// failed is the error that left the current segment in an unknown state. The
+	// appends are refused once it is set, so that no record follows a partial one.
+	failed error
+}
+
+// append writes the record to the current segment as a single write, so that a
+// crash can only tear the last record. A failed write is truncated away; if that
+// fails too, the log refuses the next appends.
+func (w *writeAheadLog) append(record walRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	frame := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(frame, uint32(len(payload)))
+	binary.LittleEndian.PutUint32(frame[4:], crc32.Checksum(payload, crcTable))
+	frame = append(frame, payload...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed != nil {
+		return fmt.Errorf("the write-ahead log is unavailable: %w", w.failed)
+	}
+	if _, err := w.file.Write(frame); err != nil {
+		if truncateErr := w.file.Truncate(w.size); truncateErr != nil {
+			w.failed = err
+		}
+		return fmt.Errorf("failed to append to the write-ahead log: %w", err)
+	}
+	w.size += int64(len(frame))
+	if w.cfg.Fsync == FsyncAlways {
+		if err := w.file.Sync(); err != nil {
+			// The record may or may not be on disk: it cannot be taken back.
+			w.failed = err
+			return fmt.Errorf("failed to flush the write-ahead log: %w", err)
+		}
+		return nil
+	}
+	w.dirty = true
+	return nil
+}
+
+// sync flushes the current segment if records were appended since the last flush.
+func (w *writeAheadLog) sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.dirty {
+		return nil
+	}
+	w.dirty = false
+	return w.file.Sync()
+}
+
+// start flushes the log with FsyncInterval and calls snapshot every SnapshotInterval
+// until stop is called.
+func (w *writeAheadLog) start(snapshot func() error) {
+	w.done = make(chan struct{})
+	w.stopped = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+
+		var syncs, snapshots <-chan time.Time
+		if w.cfg.Fsync == FsyncInterval && w.cfg.FsyncInterval > 0 {
+			ticker := time.NewTicker(w.cfg.FsyncInterval)
+			defer ticker.Stop()
+			syncs = ticker.C
+		}
+		if w.cfg.SnapshotInterval > 0 {
+			ticker := time.NewTicker(w.cfg.SnapshotInterval)
+			defer ticker.Stop()
+			snapshots = ticker.C
+		}
+
+		for {
+			select {
+			case <-w.stopped:
+				return
+			case <-syncs:
+				if err := w.sync(); err != nil {
+					slog.Error("failed to flush the write-ahead log", slog.String("err", err.Error()))
+				}
+			case <-snapshots:
+				if err := snapshot(); err != nil {
+					slog.Error("failed to snapshot the messages", slog.String("err", err.Error()))
+				}
+			}
+		}
+	}()
+}
+
+// stop stops the background work started by start and waits for it to return.
+func (w *writeAheadLog) stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopped)
+		<-w.done
+	})
+}
+
+// rotate closes the current segment and starts the next one, whose generation it
+// returns. The current segment is flushed before the next one is created, so that
+// only the last segment can have a torn tail after a crash.
+func (w *writeAheadLog) rotate() (uint64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed != nil {
+		return 0, fmt.Errorf("the write-ahead log is unavailable: %w", w.failed)
+	}
+	if err := w.file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to flush the write-ahead log: %w", err)
+	}
+	file, err := createSegment(w.cfg.Dir, w.generation+1)
+	if err != nil {
+		return 0, err
+	}
+	if err := w.file.Close(); err != nil {
+		file.Close()
+		return 0, fmt.Errorf("failed to close the write-ahead log segment: %w", err)
+	}
+	w.file = file
+	w.size = 0
+	w.generation++
+	w.dirty = false
+	return w.generation, nil
+}
+
+func (w *writeAheadLog) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return closeSegment(w.file)
+}
+
+// writeSnapshot atomically replaces the snapshot with messages, the state before
+// the segment of generation, and deletes the older segments.
+func (w *writeAheadLog) writeSnapshot(generation uint64, messages []walMessage) error {
+	temp := filepath.Join(w.cfg.Dir, "."+snapshotName+".tmp")
+	file, err := os.OpenFile(temp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	defer os.Remove(temp)
+
+	buffered := bufio.NewWriter(file)
+	err = writeRecord(buffered, snapshotHeader{Generation: generation, Count: len(messages)})
+	for _, message := range messages {
+		if err != nil {
+			break
+		}
+		err = writeRecord(buffered, message)
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(temp, filepath.Join(w.cfg.Dir, snapshotName)); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := syncDir(w.cfg.Dir); err != nil {
+		return err
+	}
+
+	segments, err := listSegments(w.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment < generation {
+			if err := os.Remove(segmentPath(w.cfg.Dir, segment)); err != nil {
+				return fmt.Errorf("failed to compact the write-ahead log: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// openWAL replays the snapshot and the segments of cfg.Dir through apply, then
+// resumes appending to the last segment. A torn record at the end of the last segment is truncated;
+// any other damage fails with ErrCorruptLog.
+func openWAL(cfg WALConfig, apply func(walRecord)) (*writeAheadLog, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create write-ahead log directory: %w", err)
+	}
+
+	generation, err := readSnapshot(cfg.Dir, apply)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := listSegments(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, segment := range segments {
+		if segment < generation {
+			// Left over by a compaction interrupted after its snapshot was written.
+			if err := os.Remove(segmentPath(cfg.Dir, segment)); err != nil {
+				return nil, fmt.Errorf("failed to compact the write-ahead log: %w", err)
+			}
+			continue
+		}
+		if err := replaySegment(cfg.Dir, segment, i == len(segments)-1, apply); err != nil {
+			return nil, err
+		}
+		generation = segment
+	}
+
+	// Appending resumes in the last segment, whose torn tail was truncated.
+	generation = max(generation, 1)
+	file, err := createSegment(cfg.Dir, generation)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read the write-ahead log: %w", err)
+	}
+	return &writeAheadLog{cfg: cfg, file: file, size: info.Size(), generation: generation}, nil
+}
+
+// readSnapshot applies the messages of the snapshot and returns its generation, or
+// zero without a snapshot.
+func readSnapshot(dir string, apply func(walRecord)) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var header *snapshotHeader
+	count := 0
+	_, err = scanRecords(data, func(payload []byte) error {
+		if header == nil {
+			header = &snapshotHeader{}
+			return json.Unmarshal(payload, header)
+		}
+		var message walMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			return err
+		}
+		apply(walRecord{Put: []walMessage{message}})
+		count++
+		return nil
+	})
+	// The snapshot is renamed into place once complete: even its tail cannot be torn.
+	if err != nil || header == nil || count != header.Count {
+		return 0, fmt.Errorf("%w: invalid snapshot in %s", ErrCorruptLog, dir)
+	}
+	return header.Generation, nil
+}
+
+func replaySegment(dir string, generation uint64, last bool, apply func(walRecord)) error {
+	path := segmentPath(dir, generation)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the write-ahead log: %w", err)
+	}
+
+	valid, err := scanRecords(data, func(payload []byte) error {
+		var record walRecord
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrCorruptLog, path, err)
+		}
+		apply(record)
+		return nil
+	})
+	switch {
+	case errors.Is(err, errTornRecord) && last:
+		slog.Warn("truncating torn write-ahead log record",
+			slog.String("segment", path), slog.Int("offset", valid), slog.Int("size", len(data)))
+		if err := os.Truncate(path, int64(valid)); err != nil {
+			return fmt.Errorf("failed to truncate the write-ahead log: %w", err)
+		}
+		return nil
+	case errors.Is(err, errTornRecord):
+		return fmt.Errorf("%w: %s is truncated at offset %d", ErrCorruptLog, path, valid)
+	default:
+		return err
+	}
+}
+
+// scanRecords calls fn with the payload of each record of data and returns the size
+// of the records read. A record that ends past data, or whose checksum fails while
+// it is the last one, is torn.
+func scanRecords(data []byte, fn func(payload []byte) error) (int, error) {
+	offset := 0
+	for offset < len(data) {
+		rest := data[offset:]
+		if len(rest) < recordHeaderSize {
+			return offset, errTornRecord
+		}
+		size := int(binary.LittleEndian.Uint32(rest))
+		if size > len(rest)-recordHeaderSize {
+			return offset, errTornRecord
+		}
+		payload := rest[recordHeaderSize : recordHeaderSize+size]
+		if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(rest[4:]) {
+			if recordHeaderSize+size == len(rest) {
+				return offset, errTornRecord
+			}
+			return offset, fmt.Errorf("%w: checksum mismatch at offset %d", ErrCorruptLog, offset)
+		}
+		if err := fn(payload); err != nil {
+			return offset, err
+		}
+		offset += recordHeaderSize + size
+	}
+	return offset, nil
+}
+
+func writeRecord(w *bufio.Writer, value any) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var header [recordHeaderSize]byte
+	binary.LittleEndian.PutUint32(header[:], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(header[4:], crc32.Checksum(payload, crcTable))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	return err
+}
+
+func segmentPath(dir string, generation uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%016d%s", generation, segmentSuffix))
+}
+
+// listSegments returns the generations of the segments of dir in ascending order.
+func listSegments(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the write-ahead log: %w", err)
+	}
+	var segments []uint64
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), segmentSuffix)
+		if !ok {
+			continue
+		}
+		if generation, err := strconv.ParseUint(name, 10, 64); err == nil {
+			segments = append(segments, generation)
+		}
+	}
+	slices.Sort(segments)
+	return segments, nil
+}
+
+func createSegment(dir string, generation uint64) (*os.File, error) {
+	file, err := os.OpenFile(segmentPath(dir, generation), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create write-ahead log segment: %w", err)
+	}
+	if err := syncDir(dir); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+func closeSegment(file *os.File) error {
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to flush the write-ahead log: %w", err)
+	}
+	return file.Close()
+}
+
+// syncDir flushes the directory entries of dir, so that created and renamed files
+// survive a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to flush directory %s: %w", dir, err)
+	}
+	return nil
+}
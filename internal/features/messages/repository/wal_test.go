@@ -0,0 +1,187 @@
+package repository_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openDurable opens a durable repository on dir, flushing every record.
+func openDurable(t *testing.T, dir string) *repository.InMemoryMessageRepository {
+	t.Helper()
+	repo, err := repository.OpenInMemoryMessageRepository(repository.WALConfig{Dir: dir, Fsync: repository.FsyncAlways})
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// segments returns the log segments of dir.
+func segments(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.wal"))
+	require.NoError(t, err)
+	return paths
+}
+
+// appendTo appends data to the file at path.
+func appendTo(t *testing.T, path string, data []byte) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func TestOpenInMemoryMessageRepository(t *testing.T) {
+	t.Run("should replay the log after a crash", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		crashed := openDurable(t, dir)
+		acme := tenant.NewContext(t.Context(), "acme")
+		deletedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		kept, err := crashed.CreateMessage(acme, &models.Message{Text: "v1", Tags: []string{"news"}})
+		require.NoError(t, err)
+		_, err = crashed.UpdateMessage(acme, &models.Message{ID: kept.ID, Text: "v2", Tags: []string{"news"}}, 1)
+		require.NoError(t, err)
+		batch, err := crashed.CreateMessages(
+			t.Context(),
+			[]*models.Message{{Text: "purged", DeletedAt: deletedAt}, {Text: "live"}},
+		)
+		require.NoError(t, err)
+		_, err = crashed.PurgeDeletedMessages(t.Context(), deletedAt.Add(time.Minute))
+		require.NoError(t, err)
+
+		// Act
+		reopened := openDurable(t, dir)
+
+		// Assert
+		retrieved, err := reopened.GetMessageByID(acme, kept.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", retrieved.Text)
+		assert.Equal(t, []string{"news"}, retrieved.Tags)
+		assert.Equal(t, int64(2), retrieved.Version)
+		assert.Equal(t, "acme", retrieved.TenantID)
+
+		_, err = reopened.GetMessageByID(t.Context(), batch[0].ID)
+		require.ErrorIs(t, err, models.ErrMessageNotFound)
+		live, err := reopened.GetMessageByID(t.Context(), batch[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "live", live.Text)
+	})
+
+	t.Run("should compact the log into the snapshot", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		repo := openDurable(t, dir)
+		before, err := repo.CreateMessage(t.Context(), &models.Message{Text: "before"})
+		require.NoError(t, err)
+		require.NoError(t, repo.Snapshot())
+		after, err := repo.CreateMessage(t.Context(), &models.Message{Text: "after"})
+		require.NoError(t, err)
+
+		// Act
+		reopened := openDurable(t, dir)
+
+		// Assert
+		assert.FileExists(t, filepath.Join(dir, "snapshot"))
+		assert.Len(t, segments(t, dir), 1)
+		messages, err := reopened.GetMessagesByIDs(t.Context(), []uuid.UUID{before.ID, after.ID})
+		require.NoError(t, err)
+		assert.Equal(t, "beforeafter", texts(messages))
+	})
+
+	t.Run("should take a snapshot when closed", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		repo := openDurable(t, dir)
+		seed(t, repo, 3)
+
+		// Act
+		require.NoError(t, repo.Close())
+		reopened := openDurable(t, dir)
+
+		// Assert
+		listed, err := reopened.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, "abc", texts(listed.Messages))
+		assert.Len(t, segments(t, dir), 1)
+	})
+
+	t.Run("should truncate a torn record at the end of the log", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		crashed := openDurable(t, dir)
+		seed(t, crashed, 2)
+		path := segments(t, dir)[0]
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		appendTo(t, path, []byte{0x40, 0, 0, 0, 1, 2, 3, 4, '{', '"'})
+
+		// Act
+		reopened := openDurable(t, dir)
+
+		// Assert
+		listed, err := reopened.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, "ab", texts(listed.Messages))
+		truncated, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, info.Size(), truncated.Size())
+	})
+
+	t.Run("should truncate a last record failing its checksum", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		crashed := openDurable(t, dir)
+		seed(t, crashed, 2)
+		path := segments(t, dir)[0]
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[len(data)-2] ^= 0xff
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		// Act
+		reopened := openDurable(t, dir)
+
+		// Assert
+		listed, err := reopened.ListMessages(t.Context(), repository.ListMessagesOptions{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, listed.Messages, 1)
+	})
+
+	t.Run("should refuse a log corrupted before its end", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		crashed := openDurable(t, dir)
+		seed(t, crashed, 2)
+		path := segments(t, dir)[0]
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data[10] ^= 0xff
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		// Act
+		_, err = repository.OpenInMemoryMessageRepository(repository.WALConfig{Dir: dir, Fsync: repository.FsyncAlways})
+
+		// Assert
+		require.ErrorIs(t, err, repository.ErrCorruptLog)
+	})
+
+	t.Run("should refuse an unknown fsync policy", func(t *testing.T) {
+		// Act
+		_, err := repository.OpenInMemoryMessageRepository(repository.WALConfig{Dir: t.TempDir(), Fsync: "sometimes"})
+
+		// Assert
+		require.Error(t, err)
+	})
+}
@@ -26,9 +26,65 @@ func (s *MessageService) CreateMessage(
 	return mediatr.Send[*dtos.CreateMessageCommand, *dtos.CreateMessageCommandResponse](ctx, cmd)
 }
 
+func (s *MessageService) CreateMessagesBatch(
+	ctx context.Context,
+	cmd *dtos.CreateMessagesBatchCommand,
+) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	return mediatr.Send[*dtos.CreateMessagesBatchCommand, *dtos.CreateMessagesBatchCommandResponse](ctx, cmd)
+}
+
 func (s *MessageService) GetMessageByID(
 	ctx context.Context,
 	query *dtos.GetMessageByIDQuery,
 ) (*dtos.GetMessageByIDQueryResponse, error) {
 	return mediatr.Send[*dtos.GetMessageByIDQuery, *dtos.GetMessageByIDQueryResponse](ctx, query)
 }
+
+func (s *MessageService) GetMessagesByIDs(
+	ctx context.Context,
+	query *dtos.GetMessagesByIDsQuery,
+) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	return mediatr.Send[*dtos.GetMessagesByIDsQuery, *dtos.GetMessagesByIDsQueryResponse](ctx, query)
+}
+
+func (s *MessageService) ListMessages(
+	ctx context.Context,
+	query *dtos.ListMessagesQuery,
+) (*dtos.ListMessagesQueryResponse, error) {
+	return mediatr.Send[*dtos.ListMessagesQuery, *dtos.ListMessagesQueryResponse](ctx, query)
+}
+
+func (s *MessageService) UpdateMessage(
+	ctx context.Context,
+	cmd *dtos.UpdateMessageCommand,
+) (*dtos.UpdateMessageCommandResponse, error) {
+	return mediatr.Send[*dtos.UpdateMessageCommand, *dtos.UpdateMessageCommandResponse](ctx, cmd)
+}
+
+func (s *MessageService) DeleteMessage(
+	ctx context.Context,
+	cmd *dtos.DeleteMessageCommand,
+) (*dtos.DeleteMessageCommandResponse, error) {
+	return mediatr.Send[*dtos.DeleteMessageCommand, *dtos.DeleteMessageCommandResponse](ctx, cmd)
+}
+
+func (s *MessageService) RestoreMessage(
+	ctx context.Context,
+	cmd *dtos.RestoreMessageCommand,
+) (*dtos.RestoreMessageCommandResponse, error) {
+	return mediatr.Send[*dtos.RestoreMessageCommand, *dtos.RestoreMessageCommandResponse](ctx, cmd)
+}
+
+func (s *MessageService) BackupMessages(
+	ctx context.Context,
+	cmd *dtos.BackupMessagesCommand,
+) (*dtos.BackupMessagesCommandResponse, error) {
+	return mediatr.Send[*dtos.BackupMessagesCommand, *dtos.BackupMessagesCommandResponse](ctx, cmd)
+}
+
+func (s *MessageService) RebuildProjections(
+	ctx context.Context,
+	cmd *dtos.RebuildProjectionsCommand,
+) (*dtos.RebuildProjectionsCommandResponse, error) {
+	return mediatr.Send[*dtos.RebuildProjectionsCommand, *dtos.RebuildProjectionsCommandResponse](ctx, cmd)
+}
@@ -28,6 +28,17 @@ func (m *MockMessageService) CreateMessage(
 	return args.Get(0).(*dtos.CreateMessageCommandResponse), args.Error(1)
 }
 
+func (m *MockMessageService) CreateMessagesBatch(
+	ctx context.Context,
+	cmd *dtos.CreateMessagesBatchCommand,
+) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.CreateMessagesBatchCommandResponse), args.Error(1)
+}
+
 func (m *MockMessageService) GetMessageByID(
 	ctx context.Context,
 	query *dtos.GetMessageByIDQuery,
@@ -39,6 +50,83 @@ func (m *MockMessageService) GetMessageByID(
 	return args.Get(0).(*dtos.GetMessageByIDQueryResponse), args.Error(1)
 }
 
+func (m *MockMessageService) GetMessagesByIDs(
+	ctx context.Context,
+	query *dtos.GetMessagesByIDsQuery,
+) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.GetMessagesByIDsQueryResponse), args.Error(1)
+}
+
+func (m *MockMessageService) ListMessages(
+	ctx context.Context,
+	query *dtos.ListMessagesQuery,
+) (*dtos.ListMessagesQueryResponse, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.ListMessagesQueryResponse), args.Error(1)
+}
+
+func (m *MockMessageService) UpdateMessage(
+	ctx context.Context,
+	cmd *dtos.UpdateMessageCommand,
+) (*dtos.UpdateMessageCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.UpdateMessageCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) DeleteMessage(
+	ctx context.Context,
+	cmd *dtos.DeleteMessageCommand,
+) (*dtos.DeleteMessageCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.DeleteMessageCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) BackupMessages(
+	ctx context.Context,
+	cmd *dtos.BackupMessagesCommand,
+) (*dtos.BackupMessagesCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.BackupMessagesCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) RebuildProjections(
+	ctx context.Context,
+	cmd *dtos.RebuildProjectionsCommand,
+) (*dtos.RebuildProjectionsCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.RebuildProjectionsCommandResponse), args.Error(1)
+}
+
+func (m *MockMessageService) RestoreMessage(
+	ctx context.Context,
+	cmd *dtos.RestoreMessageCommand,
+) (*dtos.RestoreMessageCommandResponse, error) {
+	args := m.Called(ctx, cmd)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dtos.RestoreMessageCommandResponse), args.Error(1)
+}
+
 func TestMessageService_CreateMessage(t *testing.T) {
 	t.Run("should create message service successfully", func(t *testing.T) {
 		// Act
@@ -11,7 +11,50 @@ type ICreateMessageCommandHandler interface {
 	Handle(ctx context.Context, cmd *dtos.CreateMessageCommand) (*dtos.CreateMessageCommandResponse, error)
 }
 
+// ICreateMessagesBatchCommandHandler defines the interface for the create messages batch command handler.
+type ICreateMessagesBatchCommandHandler interface {
+	Handle(
+		ctx context.Context,
+		cmd *dtos.CreateMessagesBatchCommand,
+	) (*dtos.CreateMessagesBatchCommandResponse, error)
+}
+
 // IGetMessageByIDQueryHandler defines the interface for the get message by id query handler.
 type IGetMessageByIDQueryHandler interface {
 	Handle(ctx context.Context, query *dtos.GetMessageByIDQuery) (*dtos.GetMessageByIDQueryResponse, error)
 }
+
+// IGetMessagesByIDsQueryHandler defines the interface for the get messages by ids query handler.
+type IGetMessagesByIDsQueryHandler interface {
+	Handle(ctx context.Context, query *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error)
+}
+
+// IListMessagesQueryHandler defines the interface for the list messages query handler.
+type IListMessagesQueryHandler interface {
+	Handle(ctx context.Context, query *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error)
+}
+
+// IUpdateMessageCommandHandler defines the interface for the update message command handler.
+type IUpdateMessageCommandHandler interface {
+	Handle(ctx context.Context, cmd *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error)
+}
+
+// IDeleteMessageCommandHandler defines the interface for the delete message command handler.
+type IDeleteMessageCommandHandler interface {
+	Handle(ctx context.Context, cmd *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error)
+}
+
+// IRestoreMessageCommandHandler defines the interface for the restore message command handler.
+type IRestoreMessageCommandHandler interface {
+	Handle(ctx context.Context, cmd *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error)
+}
+
+// IBackupMessagesCommandHandler defines the interface for the backup messages command handler.
+type IBackupMessagesCommandHandler interface {
+	Handle(ctx context.Context, cmd *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error)
+}
+
+// IRebuildProjectionsCommandHandler defines the interface for the rebuild projections command handler.
+type IRebuildProjectionsCommandHandler interface {
+	Handle(ctx context.Context, cmd *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error)
+}
@@ -9,5 +9,22 @@ import (
 // IMessageService defines the application service for messages.
 type IMessageService interface {
 	CreateMessage(ctx context.Context, cmd *dtos.CreateMessageCommand) (*dtos.CreateMessageCommandResponse, error)
+	CreateMessagesBatch(
+		ctx context.Context,
+		cmd *dtos.CreateMessagesBatchCommand,
+	) (*dtos.CreateMessagesBatchCommandResponse, error)
 	GetMessageByID(ctx context.Context, query *dtos.GetMessageByIDQuery) (*dtos.GetMessageByIDQueryResponse, error)
+	GetMessagesByIDs(
+		ctx context.Context,
+		query *dtos.GetMessagesByIDsQuery,
+	) (*dtos.GetMessagesByIDsQueryResponse, error)
+	ListMessages(ctx context.Context, query *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error)
+	UpdateMessage(ctx context.Context, cmd *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error)
+	DeleteMessage(ctx context.Context, cmd *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error)
+	BackupMessages(ctx context.Context, cmd *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error)
+	RebuildProjections(
+		ctx context.Context,
+		cmd *dtos.RebuildProjectionsCommand,
+	) (*dtos.RebuildProjectionsCommandResponse, error)
+	RestoreMessage(ctx context.Context, cmd *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error)
 }
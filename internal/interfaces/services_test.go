@@ -21,6 +21,13 @@ func (m *MockMessageService) CreateMessage(
 	return &dtos.CreateMessageCommandResponse{ID: uuid.New()}, nil
 }
 
+func (m *MockMessageService) CreateMessagesBatch(
+	ctx context.Context,
+	cmd *dtos.CreateMessagesBatchCommand,
+) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	return &dtos.CreateMessagesBatchCommandResponse{Created: len(cmd.Items)}, nil
+}
+
 func (m *MockMessageService) GetMessageByID(
 	ctx context.Context,
 	query *dtos.GetMessageByIDQuery,
@@ -28,6 +35,55 @@ func (m *MockMessageService) GetMessageByID(
 	return &dtos.GetMessageByIDQueryResponse{ID: query.ID, Text: "test"}, nil
 }
 
+func (m *MockMessageService) GetMessagesByIDs(
+	ctx context.Context,
+	query *dtos.GetMessagesByIDsQuery,
+) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	return &dtos.GetMessagesByIDsQueryResponse{}, nil
+}
+
+func (m *MockMessageService) ListMessages(
+	ctx context.Context,
+	query *dtos.ListMessagesQuery,
+) (*dtos.ListMessagesQueryResponse, error) {
+	return &dtos.ListMessagesQueryResponse{}, nil
+}
+
+func (m *MockMessageService) UpdateMessage(
+	ctx context.Context,
+	cmd *dtos.UpdateMessageCommand,
+) (*dtos.UpdateMessageCommandResponse, error) {
+	return &dtos.UpdateMessageCommandResponse{ID: cmd.ID, Text: *cmd.Text, Version: 2}, nil
+}
+
+func (m *MockMessageService) DeleteMessage(
+	ctx context.Context,
+	cmd *dtos.DeleteMessageCommand,
+) (*dtos.DeleteMessageCommandResponse, error) {
+	return &dtos.DeleteMessageCommandResponse{ID: cmd.ID, Version: 2}, nil
+}
+
+func (m *MockMessageService) BackupMessages(
+	ctx context.Context,
+	cmd *dtos.BackupMessagesCommand,
+) (*dtos.BackupMessagesCommandResponse, error) {
+	return &dtos.BackupMessagesCommandResponse{}, nil
+}
+
+func (m *MockMessageService) RebuildProjections(
+	ctx context.Context,
+	cmd *dtos.RebuildProjectionsCommand,
+) (*dtos.RebuildProjectionsCommandResponse, error) {
+	return &dtos.RebuildProjectionsCommandResponse{}, nil
+}
+
+func (m *MockMessageService) RestoreMessage(
+	ctx context.Context,
+	cmd *dtos.RestoreMessageCommand,
+) (*dtos.RestoreMessageCommandResponse, error) {
+	return &dtos.RestoreMessageCommandResponse{ID: cmd.ID, Version: 3}, nil
+}
+
 func TestIMessageService(t *testing.T) {
 	t.Run("should implement interface correctly", func(t *testing.T) {
 		// Arrange
@@ -0,0 +1,33 @@
+// Package mocks_test checks the generated mocks. It lives outside of the directories
+// written by mockery, so that regenerating the mocks keeps it, yet under mocks/,
+// the only tree allowed to import mocks/internal.
+package mocks_test
+
+import (
+	"testing"
+
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	"github.com/arielsrv/fxf/internal/features/messages/repository/repositorytest"
+	mocks "github.com/arielsrv/fxf/mocks/internal/features/messages/repository"
+	"github.com/stretchr/testify/mock"
+)
+
+// TestMockIMessageRepository_Contract checks that the generated mock forwards the
+// arguments and results of every method, by delegating it to the in-memory repository.
+func TestMockIMessageRepository_Contract(t *testing.T) {
+	repositorytest.RunContract(t, func(t *testing.T) repository.IMessageRepository {
+		fake := repository.NewInMemoryMessageRepository()
+		repo := mocks.NewMockIMessageRepository(t)
+		repo.EXPECT().CreateMessage(mock.Anything, mock.Anything).RunAndReturn(fake.CreateMessage).Maybe()
+		repo.EXPECT().CreateMessages(mock.Anything, mock.Anything).RunAndReturn(fake.CreateMessages).Maybe()
+		repo.EXPECT().GetMessageByID(mock.Anything, mock.Anything).RunAndReturn(fake.GetMessageByID).Maybe()
+		repo.EXPECT().GetMessagesByIDs(mock.Anything, mock.Anything).RunAndReturn(fake.GetMessagesByIDs).Maybe()
+		repo.EXPECT().
+			UpdateMessage(mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(fake.UpdateMessage).
+			Maybe()
+		repo.EXPECT().PurgeDeletedMessages(mock.Anything, mock.Anything).RunAndReturn(fake.PurgeDeletedMessages).Maybe()
+		repo.EXPECT().ListMessages(mock.Anything, mock.Anything).RunAndReturn(fake.ListMessages).Maybe()
+		return repo
+	})
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package repository
+
+import (
+	"context"
+	"io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIBackupRepository creates a new instance of MockIBackupRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIBackupRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIBackupRepository {
+	mock := &MockIBackupRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIBackupRepository is an autogenerated mock type for the IBackupRepository type
+type MockIBackupRepository struct {
+	mock.Mock
+}
+
+type MockIBackupRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIBackupRepository) EXPECT() *MockIBackupRepository_Expecter {
+	return &MockIBackupRepository_Expecter{mock: &_m.Mock}
+}
+
+// Backup provides a mock function for the type MockIBackupRepository
+func (_mock *MockIBackupRepository) Backup(ctx context.Context, w io.Writer) (int64, error) {
+	ret := _mock.Called(ctx, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Backup")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, io.Writer) (int64, error)); ok {
+		return returnFunc(ctx, w)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, io.Writer) int64); ok {
+		r0 = returnFunc(ctx, w)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, io.Writer) error); ok {
+		r1 = returnFunc(ctx, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIBackupRepository_Backup_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Backup'
+type MockIBackupRepository_Backup_Call struct {
+	*mock.Call
+}
+
+// Backup is a helper method to define mock.On call
+//   - ctx context.Context
+//   - w io.Writer
+func (_e *MockIBackupRepository_Expecter) Backup(ctx interface{}, w interface{}) *MockIBackupRepository_Backup_Call {
+	return &MockIBackupRepository_Backup_Call{Call: _e.mock.On("Backup", ctx, w)}
+}
+
+func (_c *MockIBackupRepository_Backup_Call) Run(run func(ctx context.Context, w io.Writer)) *MockIBackupRepository_Backup_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 io.Writer
+		if args[1] != nil {
+			arg1 = args[1].(io.Writer)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIBackupRepository_Backup_Call) Return(n int64, err error) *MockIBackupRepository_Backup_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIBackupRepository_Backup_Call) RunAndReturn(run func(ctx context.Context, w io.Writer) (int64, error)) *MockIBackupRepository_Backup_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -6,8 +6,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/arielsrv/fxf/internal/features/messages/models"
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
 	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -107,6 +109,74 @@ func (_c *MockIMessageRepository_CreateMessage_Call) RunAndReturn(run func(ctx c
 	return _c
 }
 
+// CreateMessages provides a mock function for the type MockIMessageRepository
+func (_mock *MockIMessageRepository) CreateMessages(ctx context.Context, messages []*models.Message) ([]*models.Message, error) {
+	ret := _mock.Called(ctx, messages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMessages")
+	}
+
+	var r0 []*models.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*models.Message) ([]*models.Message, error)); ok {
+		return returnFunc(ctx, messages)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*models.Message) []*models.Message); ok {
+		r0 = returnFunc(ctx, messages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []*models.Message) error); ok {
+		r1 = returnFunc(ctx, messages)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageRepository_CreateMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMessages'
+type MockIMessageRepository_CreateMessages_Call struct {
+	*mock.Call
+}
+
+// CreateMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - messages []*models.Message
+func (_e *MockIMessageRepository_Expecter) CreateMessages(ctx interface{}, messages interface{}) *MockIMessageRepository_CreateMessages_Call {
+	return &MockIMessageRepository_CreateMessages_Call{Call: _e.mock.On("CreateMessages", ctx, messages)}
+}
+
+func (_c *MockIMessageRepository_CreateMessages_Call) Run(run func(ctx context.Context, messages []*models.Message)) *MockIMessageRepository_CreateMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*models.Message
+		if args[1] != nil {
+			arg1 = args[1].([]*models.Message)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageRepository_CreateMessages_Call) Return(messages1 []*models.Message, err error) *MockIMessageRepository_CreateMessages_Call {
+	_c.Call.Return(messages1, err)
+	return _c
+}
+
+func (_c *MockIMessageRepository_CreateMessages_Call) RunAndReturn(run func(ctx context.Context, messages []*models.Message) ([]*models.Message, error)) *MockIMessageRepository_CreateMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMessageByID provides a mock function for the type MockIMessageRepository
 func (_mock *MockIMessageRepository) GetMessageByID(ctx context.Context, id uuid.UUID) (*models.Message, error) {
 	ret := _mock.Called(ctx, id)
@@ -174,3 +244,279 @@ func (_c *MockIMessageRepository_GetMessageByID_Call) RunAndReturn(run func(ctx
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetMessagesByIDs provides a mock function for the type MockIMessageRepository
+func (_mock *MockIMessageRepository) GetMessagesByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error) {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesByIDs")
+	}
+
+	var r0 []*models.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID) ([]*models.Message, error)); ok {
+		return returnFunc(ctx, ids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID) []*models.Message); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageRepository_GetMessagesByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByIDs'
+type MockIMessageRepository_GetMessagesByIDs_Call struct {
+	*mock.Call
+}
+
+// GetMessagesByIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+func (_e *MockIMessageRepository_Expecter) GetMessagesByIDs(ctx interface{}, ids interface{}) *MockIMessageRepository_GetMessagesByIDs_Call {
+	return &MockIMessageRepository_GetMessagesByIDs_Call{Call: _e.mock.On("GetMessagesByIDs", ctx, ids)}
+}
+
+func (_c *MockIMessageRepository_GetMessagesByIDs_Call) Run(run func(ctx context.Context, ids []uuid.UUID)) *MockIMessageRepository_GetMessagesByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].([]uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageRepository_GetMessagesByIDs_Call) Return(messages []*models.Message, err error) *MockIMessageRepository_GetMessagesByIDs_Call {
+	_c.Call.Return(messages, err)
+	return _c
+}
+
+func (_c *MockIMessageRepository_GetMessagesByIDs_Call) RunAndReturn(run func(ctx context.Context, ids []uuid.UUID) ([]*models.Message, error)) *MockIMessageRepository_GetMessagesByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListMessages provides a mock function for the type MockIMessageRepository
+func (_mock *MockIMessageRepository) ListMessages(ctx context.Context, opts repository.ListMessagesOptions) (*repository.ListMessagesResult, error) {
+	ret := _mock.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMessages")
+	}
+
+	var r0 *repository.ListMessagesResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.ListMessagesOptions) (*repository.ListMessagesResult, error)); ok {
+		return returnFunc(ctx, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, repository.ListMessagesOptions) *repository.ListMessagesResult); ok {
+		r0 = returnFunc(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*repository.ListMessagesResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, repository.ListMessagesOptions) error); ok {
+		r1 = returnFunc(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageRepository_ListMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListMessages'
+type MockIMessageRepository_ListMessages_Call struct {
+	*mock.Call
+}
+
+// ListMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts repository.ListMessagesOptions
+func (_e *MockIMessageRepository_Expecter) ListMessages(ctx interface{}, opts interface{}) *MockIMessageRepository_ListMessages_Call {
+	return &MockIMessageRepository_ListMessages_Call{Call: _e.mock.On("ListMessages", ctx, opts)}
+}
+
+func (_c *MockIMessageRepository_ListMessages_Call) Run(run func(ctx context.Context, opts repository.ListMessagesOptions)) *MockIMessageRepository_ListMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 repository.ListMessagesOptions
+		if args[1] != nil {
+			arg1 = args[1].(repository.ListMessagesOptions)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageRepository_ListMessages_Call) Return(listMessagesResult *repository.ListMessagesResult, err error) *MockIMessageRepository_ListMessages_Call {
+	_c.Call.Return(listMessagesResult, err)
+	return _c
+}
+
+func (_c *MockIMessageRepository_ListMessages_Call) RunAndReturn(run func(ctx context.Context, opts repository.ListMessagesOptions) (*repository.ListMessagesResult, error)) *MockIMessageRepository_ListMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeletedMessages provides a mock function for the type MockIMessageRepository
+func (_mock *MockIMessageRepository) PurgeDeletedMessages(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ret := _mock.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedMessages")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return returnFunc(ctx, deletedBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = returnFunc(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageRepository_PurgeDeletedMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeletedMessages'
+type MockIMessageRepository_PurgeDeletedMessages_Call struct {
+	*mock.Call
+}
+
+// PurgeDeletedMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deletedBefore time.Time
+func (_e *MockIMessageRepository_Expecter) PurgeDeletedMessages(ctx interface{}, deletedBefore interface{}) *MockIMessageRepository_PurgeDeletedMessages_Call {
+	return &MockIMessageRepository_PurgeDeletedMessages_Call{Call: _e.mock.On("PurgeDeletedMessages", ctx, deletedBefore)}
+}
+
+func (_c *MockIMessageRepository_PurgeDeletedMessages_Call) Run(run func(ctx context.Context, deletedBefore time.Time)) *MockIMessageRepository_PurgeDeletedMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageRepository_PurgeDeletedMessages_Call) Return(n int, err error) *MockIMessageRepository_PurgeDeletedMessages_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIMessageRepository_PurgeDeletedMessages_Call) RunAndReturn(run func(ctx context.Context, deletedBefore time.Time) (int, error)) *MockIMessageRepository_PurgeDeletedMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMessage provides a mock function for the type MockIMessageRepository
+func (_mock *MockIMessageRepository) UpdateMessage(ctx context.Context, message *models.Message, expectedVersion int64) (*models.Message, error) {
+	ret := _mock.Called(ctx, message, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMessage")
+	}
+
+	var r0 *models.Message
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Message, int64) (*models.Message, error)); ok {
+		return returnFunc(ctx, message, expectedVersion)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.Message, int64) *models.Message); ok {
+		r0 = returnFunc(ctx, message, expectedVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.Message)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *models.Message, int64) error); ok {
+		r1 = returnFunc(ctx, message, expectedVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageRepository_UpdateMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessage'
+type MockIMessageRepository_UpdateMessage_Call struct {
+	*mock.Call
+}
+
+// UpdateMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - message *models.Message
+//   - expectedVersion int64
+func (_e *MockIMessageRepository_Expecter) UpdateMessage(ctx interface{}, message interface{}, expectedVersion interface{}) *MockIMessageRepository_UpdateMessage_Call {
+	return &MockIMessageRepository_UpdateMessage_Call{Call: _e.mock.On("UpdateMessage", ctx, message, expectedVersion)}
+}
+
+func (_c *MockIMessageRepository_UpdateMessage_Call) Run(run func(ctx context.Context, message *models.Message, expectedVersion int64)) *MockIMessageRepository_UpdateMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *models.Message
+		if args[1] != nil {
+			arg1 = args[1].(*models.Message)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageRepository_UpdateMessage_Call) Return(message1 *models.Message, err error) *MockIMessageRepository_UpdateMessage_Call {
+	_c.Call.Return(message1, err)
+	return _c
+}
+
+func (_c *MockIMessageRepository_UpdateMessage_Call) RunAndReturn(run func(ctx context.Context, message *models.Message, expectedVersion int64) (*models.Message, error)) *MockIMessageRepository_UpdateMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,371 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIOutboxRepository creates a new instance of MockIOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIOutboxRepository is an autogenerated mock type for the IOutboxRepository type
+type MockIOutboxRepository struct {
+	mock.Mock
+}
+
+type MockIOutboxRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIOutboxRepository) EXPECT() *MockIOutboxRepository_Expecter {
+	return &MockIOutboxRepository_Expecter{mock: &_m.Mock}
+}
+
+// AckEvent provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) AckEvent(ctx context.Context, sequence uint64) error {
+	ret := _mock.Called(ctx, sequence)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AckEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64) error); ok {
+		r0 = returnFunc(ctx, sequence)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIOutboxRepository_AckEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AckEvent'
+type MockIOutboxRepository_AckEvent_Call struct {
+	*mock.Call
+}
+
+// AckEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sequence uint64
+func (_e *MockIOutboxRepository_Expecter) AckEvent(ctx interface{}, sequence interface{}) *MockIOutboxRepository_AckEvent_Call {
+	return &MockIOutboxRepository_AckEvent_Call{Call: _e.mock.On("AckEvent", ctx, sequence)}
+}
+
+func (_c *MockIOutboxRepository_AckEvent_Call) Run(run func(ctx context.Context, sequence uint64)) *MockIOutboxRepository_AckEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_AckEvent_Call) Return(err error) *MockIOutboxRepository_AckEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_AckEvent_Call) RunAndReturn(run func(ctx context.Context, sequence uint64) error) *MockIOutboxRepository_AckEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeadLetterEvent provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) DeadLetterEvent(ctx context.Context, sequence uint64, lastError string) error {
+	ret := _mock.Called(ctx, sequence, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeadLetterEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, string) error); ok {
+		r0 = returnFunc(ctx, sequence, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIOutboxRepository_DeadLetterEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeadLetterEvent'
+type MockIOutboxRepository_DeadLetterEvent_Call struct {
+	*mock.Call
+}
+
+// DeadLetterEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sequence uint64
+//   - lastError string
+func (_e *MockIOutboxRepository_Expecter) DeadLetterEvent(ctx interface{}, sequence interface{}, lastError interface{}) *MockIOutboxRepository_DeadLetterEvent_Call {
+	return &MockIOutboxRepository_DeadLetterEvent_Call{Call: _e.mock.On("DeadLetterEvent", ctx, sequence, lastError)}
+}
+
+func (_c *MockIOutboxRepository_DeadLetterEvent_Call) Run(run func(ctx context.Context, sequence uint64, lastError string)) *MockIOutboxRepository_DeadLetterEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_DeadLetterEvent_Call) Return(err error) *MockIOutboxRepository_DeadLetterEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_DeadLetterEvent_Call) RunAndReturn(run func(ctx context.Context, sequence uint64, lastError string) error) *MockIOutboxRepository_DeadLetterEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeadLetters provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) DeadLetters(ctx context.Context, limit int) ([]*repository.OutboxEvent, error) {
+	ret := _mock.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeadLetters")
+	}
+
+	var r0 []*repository.OutboxEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) ([]*repository.OutboxEvent, error)); ok {
+		return returnFunc(ctx, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) []*repository.OutboxEvent); ok {
+		r0 = returnFunc(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.OutboxEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = returnFunc(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIOutboxRepository_DeadLetters_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeadLetters'
+type MockIOutboxRepository_DeadLetters_Call struct {
+	*mock.Call
+}
+
+// DeadLetters is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+func (_e *MockIOutboxRepository_Expecter) DeadLetters(ctx interface{}, limit interface{}) *MockIOutboxRepository_DeadLetters_Call {
+	return &MockIOutboxRepository_DeadLetters_Call{Call: _e.mock.On("DeadLetters", ctx, limit)}
+}
+
+func (_c *MockIOutboxRepository_DeadLetters_Call) Run(run func(ctx context.Context, limit int)) *MockIOutboxRepository_DeadLetters_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_DeadLetters_Call) Return(outboxEvents []*repository.OutboxEvent, err error) *MockIOutboxRepository_DeadLetters_Call {
+	_c.Call.Return(outboxEvents, err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_DeadLetters_Call) RunAndReturn(run func(ctx context.Context, limit int) ([]*repository.OutboxEvent, error)) *MockIOutboxRepository_DeadLetters_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PendingEvents provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) PendingEvents(ctx context.Context, now time.Time, limit int) ([]*repository.OutboxEvent, error) {
+	ret := _mock.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PendingEvents")
+	}
+
+	var r0 []*repository.OutboxEvent
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*repository.OutboxEvent, error)); ok {
+		return returnFunc(ctx, now, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []*repository.OutboxEvent); ok {
+		r0 = returnFunc(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*repository.OutboxEvent)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIOutboxRepository_PendingEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PendingEvents'
+type MockIOutboxRepository_PendingEvents_Call struct {
+	*mock.Call
+}
+
+// PendingEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - limit int
+func (_e *MockIOutboxRepository_Expecter) PendingEvents(ctx interface{}, now interface{}, limit interface{}) *MockIOutboxRepository_PendingEvents_Call {
+	return &MockIOutboxRepository_PendingEvents_Call{Call: _e.mock.On("PendingEvents", ctx, now, limit)}
+}
+
+func (_c *MockIOutboxRepository_PendingEvents_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *MockIOutboxRepository_PendingEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_PendingEvents_Call) Return(outboxEvents []*repository.OutboxEvent, err error) *MockIOutboxRepository_PendingEvents_Call {
+	_c.Call.Return(outboxEvents, err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_PendingEvents_Call) RunAndReturn(run func(ctx context.Context, now time.Time, limit int) ([]*repository.OutboxEvent, error)) *MockIOutboxRepository_PendingEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RetryEvent provides a mock function for the type MockIOutboxRepository
+func (_mock *MockIOutboxRepository) RetryEvent(ctx context.Context, sequence uint64, nextAttemptAt time.Time, lastError string) error {
+	ret := _mock.Called(ctx, sequence, nextAttemptAt, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryEvent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, time.Time, string) error); ok {
+		r0 = returnFunc(ctx, sequence, nextAttemptAt, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIOutboxRepository_RetryEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RetryEvent'
+type MockIOutboxRepository_RetryEvent_Call struct {
+	*mock.Call
+}
+
+// RetryEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sequence uint64
+//   - nextAttemptAt time.Time
+//   - lastError string
+func (_e *MockIOutboxRepository_Expecter) RetryEvent(ctx interface{}, sequence interface{}, nextAttemptAt interface{}, lastError interface{}) *MockIOutboxRepository_RetryEvent_Call {
+	return &MockIOutboxRepository_RetryEvent_Call{Call: _e.mock.On("RetryEvent", ctx, sequence, nextAttemptAt, lastError)}
+}
+
+func (_c *MockIOutboxRepository_RetryEvent_Call) Run(run func(ctx context.Context, sequence uint64, nextAttemptAt time.Time, lastError string)) *MockIOutboxRepository_RetryEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIOutboxRepository_RetryEvent_Call) Return(err error) *MockIOutboxRepository_RetryEvent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIOutboxRepository_RetryEvent_Call) RunAndReturn(run func(ctx context.Context, sequence uint64, nextAttemptAt time.Time, lastError string) error) *MockIOutboxRepository_RetryEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package repository
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/repository"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIProjectionRepository creates a new instance of MockIProjectionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIProjectionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIProjectionRepository {
+	mock := &MockIProjectionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIProjectionRepository is an autogenerated mock type for the IProjectionRepository type
+type MockIProjectionRepository struct {
+	mock.Mock
+}
+
+type MockIProjectionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIProjectionRepository) EXPECT() *MockIProjectionRepository_Expecter {
+	return &MockIProjectionRepository_Expecter{mock: &_m.Mock}
+}
+
+// RebuildProjections provides a mock function for the type MockIProjectionRepository
+func (_mock *MockIProjectionRepository) RebuildProjections(ctx context.Context) (repository.ProjectionStats, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildProjections")
+	}
+
+	var r0 repository.ProjectionStats
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (repository.ProjectionStats, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) repository.ProjectionStats); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(repository.ProjectionStats)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIProjectionRepository_RebuildProjections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebuildProjections'
+type MockIProjectionRepository_RebuildProjections_Call struct {
+	*mock.Call
+}
+
+// RebuildProjections is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIProjectionRepository_Expecter) RebuildProjections(ctx interface{}) *MockIProjectionRepository_RebuildProjections_Call {
+	return &MockIProjectionRepository_RebuildProjections_Call{Call: _e.mock.On("RebuildProjections", ctx)}
+}
+
+func (_c *MockIProjectionRepository_RebuildProjections_Call) Run(run func(ctx context.Context)) *MockIProjectionRepository_RebuildProjections_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIProjectionRepository_RebuildProjections_Call) Return(projectionStats repository.ProjectionStats, err error) *MockIProjectionRepository_RebuildProjections_Call {
+	_c.Call.Return(projectionStats, err)
+	return _c
+}
+
+func (_c *MockIProjectionRepository_RebuildProjections_Call) RunAndReturn(run func(ctx context.Context) (repository.ProjectionStats, error)) *MockIProjectionRepository_RebuildProjections_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIBackupMessagesCommandHandler creates a new instance of MockIBackupMessagesCommandHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIBackupMessagesCommandHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIBackupMessagesCommandHandler {
+	mock := &MockIBackupMessagesCommandHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIBackupMessagesCommandHandler is an autogenerated mock type for the IBackupMessagesCommandHandler type
+type MockIBackupMessagesCommandHandler struct {
+	mock.Mock
+}
+
+type MockIBackupMessagesCommandHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIBackupMessagesCommandHandler) EXPECT() *MockIBackupMessagesCommandHandler_Expecter {
+	return &MockIBackupMessagesCommandHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIBackupMessagesCommandHandler
+func (_mock *MockIBackupMessagesCommandHandler) Handle(ctx context.Context, cmd *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.BackupMessagesCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.BackupMessagesCommand) *dtos.BackupMessagesCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.BackupMessagesCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.BackupMessagesCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIBackupMessagesCommandHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIBackupMessagesCommandHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.BackupMessagesCommand
+func (_e *MockIBackupMessagesCommandHandler_Expecter) Handle(ctx interface{}, cmd interface{}) *MockIBackupMessagesCommandHandler_Handle_Call {
+	return &MockIBackupMessagesCommandHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, cmd)}
+}
+
+func (_c *MockIBackupMessagesCommandHandler_Handle_Call) Run(run func(ctx context.Context, cmd *dtos.BackupMessagesCommand)) *MockIBackupMessagesCommandHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.BackupMessagesCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.BackupMessagesCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIBackupMessagesCommandHandler_Handle_Call) Return(backupMessagesCommandResponse *dtos.BackupMessagesCommandResponse, err error) *MockIBackupMessagesCommandHandler_Handle_Call {
+	_c.Call.Return(backupMessagesCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIBackupMessagesCommandHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error)) *MockIBackupMessagesCommandHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockICreateMessagesBatchCommandHandler creates a new instance of MockICreateMessagesBatchCommandHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockICreateMessagesBatchCommandHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockICreateMessagesBatchCommandHandler {
+	mock := &MockICreateMessagesBatchCommandHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockICreateMessagesBatchCommandHandler is an autogenerated mock type for the ICreateMessagesBatchCommandHandler type
+type MockICreateMessagesBatchCommandHandler struct {
+	mock.Mock
+}
+
+type MockICreateMessagesBatchCommandHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockICreateMessagesBatchCommandHandler) EXPECT() *MockICreateMessagesBatchCommandHandler_Expecter {
+	return &MockICreateMessagesBatchCommandHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockICreateMessagesBatchCommandHandler
+func (_mock *MockICreateMessagesBatchCommandHandler) Handle(ctx context.Context, cmd *dtos.CreateMessagesBatchCommand) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.CreateMessagesBatchCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.CreateMessagesBatchCommand) (*dtos.CreateMessagesBatchCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.CreateMessagesBatchCommand) *dtos.CreateMessagesBatchCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.CreateMessagesBatchCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.CreateMessagesBatchCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockICreateMessagesBatchCommandHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockICreateMessagesBatchCommandHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.CreateMessagesBatchCommand
+func (_e *MockICreateMessagesBatchCommandHandler_Expecter) Handle(ctx interface{}, cmd interface{}) *MockICreateMessagesBatchCommandHandler_Handle_Call {
+	return &MockICreateMessagesBatchCommandHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, cmd)}
+}
+
+func (_c *MockICreateMessagesBatchCommandHandler_Handle_Call) Run(run func(ctx context.Context, cmd *dtos.CreateMessagesBatchCommand)) *MockICreateMessagesBatchCommandHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.CreateMessagesBatchCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.CreateMessagesBatchCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockICreateMessagesBatchCommandHandler_Handle_Call) Return(createMessagesBatchCommandResponse *dtos.CreateMessagesBatchCommandResponse, err error) *MockICreateMessagesBatchCommandHandler_Handle_Call {
+	_c.Call.Return(createMessagesBatchCommandResponse, err)
+	return _c
+}
+
+func (_c *MockICreateMessagesBatchCommandHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.CreateMessagesBatchCommand) (*dtos.CreateMessagesBatchCommandResponse, error)) *MockICreateMessagesBatchCommandHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIDeleteMessageCommandHandler creates a new instance of MockIDeleteMessageCommandHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIDeleteMessageCommandHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIDeleteMessageCommandHandler {
+	mock := &MockIDeleteMessageCommandHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIDeleteMessageCommandHandler is an autogenerated mock type for the IDeleteMessageCommandHandler type
+type MockIDeleteMessageCommandHandler struct {
+	mock.Mock
+}
+
+type MockIDeleteMessageCommandHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIDeleteMessageCommandHandler) EXPECT() *MockIDeleteMessageCommandHandler_Expecter {
+	return &MockIDeleteMessageCommandHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIDeleteMessageCommandHandler
+func (_mock *MockIDeleteMessageCommandHandler) Handle(ctx context.Context, cmd *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.DeleteMessageCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.DeleteMessageCommand) *dtos.DeleteMessageCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.DeleteMessageCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.DeleteMessageCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIDeleteMessageCommandHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIDeleteMessageCommandHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.DeleteMessageCommand
+func (_e *MockIDeleteMessageCommandHandler_Expecter) Handle(ctx interface{}, cmd interface{}) *MockIDeleteMessageCommandHandler_Handle_Call {
+	return &MockIDeleteMessageCommandHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, cmd)}
+}
+
+func (_c *MockIDeleteMessageCommandHandler_Handle_Call) Run(run func(ctx context.Context, cmd *dtos.DeleteMessageCommand)) *MockIDeleteMessageCommandHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.DeleteMessageCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.DeleteMessageCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIDeleteMessageCommandHandler_Handle_Call) Return(deleteMessageCommandResponse *dtos.DeleteMessageCommandResponse, err error) *MockIDeleteMessageCommandHandler_Handle_Call {
+	_c.Call.Return(deleteMessageCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIDeleteMessageCommandHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error)) *MockIDeleteMessageCommandHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIGetMessagesByIDsQueryHandler creates a new instance of MockIGetMessagesByIDsQueryHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIGetMessagesByIDsQueryHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIGetMessagesByIDsQueryHandler {
+	mock := &MockIGetMessagesByIDsQueryHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIGetMessagesByIDsQueryHandler is an autogenerated mock type for the IGetMessagesByIDsQueryHandler type
+type MockIGetMessagesByIDsQueryHandler struct {
+	mock.Mock
+}
+
+type MockIGetMessagesByIDsQueryHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIGetMessagesByIDsQueryHandler) EXPECT() *MockIGetMessagesByIDsQueryHandler_Expecter {
+	return &MockIGetMessagesByIDsQueryHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIGetMessagesByIDsQueryHandler
+func (_mock *MockIGetMessagesByIDsQueryHandler) Handle(ctx context.Context, query *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.GetMessagesByIDsQueryResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.GetMessagesByIDsQuery) *dtos.GetMessagesByIDsQueryResponse); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.GetMessagesByIDsQueryResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.GetMessagesByIDsQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIGetMessagesByIDsQueryHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIGetMessagesByIDsQueryHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *dtos.GetMessagesByIDsQuery
+func (_e *MockIGetMessagesByIDsQueryHandler_Expecter) Handle(ctx interface{}, query interface{}) *MockIGetMessagesByIDsQueryHandler_Handle_Call {
+	return &MockIGetMessagesByIDsQueryHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, query)}
+}
+
+func (_c *MockIGetMessagesByIDsQueryHandler_Handle_Call) Run(run func(ctx context.Context, query *dtos.GetMessagesByIDsQuery)) *MockIGetMessagesByIDsQueryHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.GetMessagesByIDsQuery
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.GetMessagesByIDsQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIGetMessagesByIDsQueryHandler_Handle_Call) Return(getMessagesByIDsQueryResponse *dtos.GetMessagesByIDsQueryResponse, err error) *MockIGetMessagesByIDsQueryHandler_Handle_Call {
+	_c.Call.Return(getMessagesByIDsQueryResponse, err)
+	return _c
+}
+
+func (_c *MockIGetMessagesByIDsQueryHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, query *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error)) *MockIGetMessagesByIDsQueryHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
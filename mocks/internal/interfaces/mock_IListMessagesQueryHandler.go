@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIListMessagesQueryHandler creates a new instance of MockIListMessagesQueryHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIListMessagesQueryHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIListMessagesQueryHandler {
+	mock := &MockIListMessagesQueryHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIListMessagesQueryHandler is an autogenerated mock type for the IListMessagesQueryHandler type
+type MockIListMessagesQueryHandler struct {
+	mock.Mock
+}
+
+type MockIListMessagesQueryHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIListMessagesQueryHandler) EXPECT() *MockIListMessagesQueryHandler_Expecter {
+	return &MockIListMessagesQueryHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIListMessagesQueryHandler
+func (_mock *MockIListMessagesQueryHandler) Handle(ctx context.Context, query *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.ListMessagesQueryResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.ListMessagesQuery) *dtos.ListMessagesQueryResponse); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.ListMessagesQueryResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.ListMessagesQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIListMessagesQueryHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIListMessagesQueryHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *dtos.ListMessagesQuery
+func (_e *MockIListMessagesQueryHandler_Expecter) Handle(ctx interface{}, query interface{}) *MockIListMessagesQueryHandler_Handle_Call {
+	return &MockIListMessagesQueryHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, query)}
+}
+
+func (_c *MockIListMessagesQueryHandler_Handle_Call) Run(run func(ctx context.Context, query *dtos.ListMessagesQuery)) *MockIListMessagesQueryHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.ListMessagesQuery
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.ListMessagesQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIListMessagesQueryHandler_Handle_Call) Return(listMessagesQueryResponse *dtos.ListMessagesQueryResponse, err error) *MockIListMessagesQueryHandler_Handle_Call {
+	_c.Call.Return(listMessagesQueryResponse, err)
+	return _c
+}
+
+func (_c *MockIListMessagesQueryHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, query *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error)) *MockIListMessagesQueryHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -38,6 +38,74 @@ func (_m *MockIMessageService) EXPECT() *MockIMessageService_Expecter {
 	return &MockIMessageService_Expecter{mock: &_m.Mock}
 }
 
+// BackupMessages provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) BackupMessages(ctx context.Context, cmd *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackupMessages")
+	}
+
+	var r0 *dtos.BackupMessagesCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.BackupMessagesCommand) *dtos.BackupMessagesCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.BackupMessagesCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.BackupMessagesCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_BackupMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BackupMessages'
+type MockIMessageService_BackupMessages_Call struct {
+	*mock.Call
+}
+
+// BackupMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.BackupMessagesCommand
+func (_e *MockIMessageService_Expecter) BackupMessages(ctx interface{}, cmd interface{}) *MockIMessageService_BackupMessages_Call {
+	return &MockIMessageService_BackupMessages_Call{Call: _e.mock.On("BackupMessages", ctx, cmd)}
+}
+
+func (_c *MockIMessageService_BackupMessages_Call) Run(run func(ctx context.Context, cmd *dtos.BackupMessagesCommand)) *MockIMessageService_BackupMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.BackupMessagesCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.BackupMessagesCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_BackupMessages_Call) Return(backupMessagesCommandResponse *dtos.BackupMessagesCommandResponse, err error) *MockIMessageService_BackupMessages_Call {
+	_c.Call.Return(backupMessagesCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_BackupMessages_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.BackupMessagesCommand) (*dtos.BackupMessagesCommandResponse, error)) *MockIMessageService_BackupMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateMessage provides a mock function for the type MockIMessageService
 func (_mock *MockIMessageService) CreateMessage(ctx context.Context, cmd *dtos.CreateMessageCommand) (*dtos.CreateMessageCommandResponse, error) {
 	ret := _mock.Called(ctx, cmd)
@@ -106,6 +174,142 @@ func (_c *MockIMessageService_CreateMessage_Call) RunAndReturn(run func(ctx cont
 	return _c
 }
 
+// CreateMessagesBatch provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) CreateMessagesBatch(ctx context.Context, cmd *dtos.CreateMessagesBatchCommand) (*dtos.CreateMessagesBatchCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMessagesBatch")
+	}
+
+	var r0 *dtos.CreateMessagesBatchCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.CreateMessagesBatchCommand) (*dtos.CreateMessagesBatchCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.CreateMessagesBatchCommand) *dtos.CreateMessagesBatchCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.CreateMessagesBatchCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.CreateMessagesBatchCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_CreateMessagesBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateMessagesBatch'
+type MockIMessageService_CreateMessagesBatch_Call struct {
+	*mock.Call
+}
+
+// CreateMessagesBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.CreateMessagesBatchCommand
+func (_e *MockIMessageService_Expecter) CreateMessagesBatch(ctx interface{}, cmd interface{}) *MockIMessageService_CreateMessagesBatch_Call {
+	return &MockIMessageService_CreateMessagesBatch_Call{Call: _e.mock.On("CreateMessagesBatch", ctx, cmd)}
+}
+
+func (_c *MockIMessageService_CreateMessagesBatch_Call) Run(run func(ctx context.Context, cmd *dtos.CreateMessagesBatchCommand)) *MockIMessageService_CreateMessagesBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.CreateMessagesBatchCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.CreateMessagesBatchCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_CreateMessagesBatch_Call) Return(createMessagesBatchCommandResponse *dtos.CreateMessagesBatchCommandResponse, err error) *MockIMessageService_CreateMessagesBatch_Call {
+	_c.Call.Return(createMessagesBatchCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_CreateMessagesBatch_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.CreateMessagesBatchCommand) (*dtos.CreateMessagesBatchCommandResponse, error)) *MockIMessageService_CreateMessagesBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteMessage provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) DeleteMessage(ctx context.Context, cmd *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMessage")
+	}
+
+	var r0 *dtos.DeleteMessageCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.DeleteMessageCommand) *dtos.DeleteMessageCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.DeleteMessageCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.DeleteMessageCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_DeleteMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMessage'
+type MockIMessageService_DeleteMessage_Call struct {
+	*mock.Call
+}
+
+// DeleteMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.DeleteMessageCommand
+func (_e *MockIMessageService_Expecter) DeleteMessage(ctx interface{}, cmd interface{}) *MockIMessageService_DeleteMessage_Call {
+	return &MockIMessageService_DeleteMessage_Call{Call: _e.mock.On("DeleteMessage", ctx, cmd)}
+}
+
+func (_c *MockIMessageService_DeleteMessage_Call) Run(run func(ctx context.Context, cmd *dtos.DeleteMessageCommand)) *MockIMessageService_DeleteMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.DeleteMessageCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.DeleteMessageCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_DeleteMessage_Call) Return(deleteMessageCommandResponse *dtos.DeleteMessageCommandResponse, err error) *MockIMessageService_DeleteMessage_Call {
+	_c.Call.Return(deleteMessageCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_DeleteMessage_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.DeleteMessageCommand) (*dtos.DeleteMessageCommandResponse, error)) *MockIMessageService_DeleteMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetMessageByID provides a mock function for the type MockIMessageService
 func (_mock *MockIMessageService) GetMessageByID(ctx context.Context, query *dtos.GetMessageByIDQuery) (*dtos.GetMessageByIDQueryResponse, error) {
 	ret := _mock.Called(ctx, query)
@@ -173,3 +377,343 @@ func (_c *MockIMessageService_GetMessageByID_Call) RunAndReturn(run func(ctx con
 	_c.Call.Return(run)
 	return _c
 }
+
+// GetMessagesByIDs provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) GetMessagesByIDs(ctx context.Context, query *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMessagesByIDs")
+	}
+
+	var r0 *dtos.GetMessagesByIDsQueryResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.GetMessagesByIDsQuery) *dtos.GetMessagesByIDsQueryResponse); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.GetMessagesByIDsQueryResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.GetMessagesByIDsQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_GetMessagesByIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMessagesByIDs'
+type MockIMessageService_GetMessagesByIDs_Call struct {
+	*mock.Call
+}
+
+// GetMessagesByIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *dtos.GetMessagesByIDsQuery
+func (_e *MockIMessageService_Expecter) GetMessagesByIDs(ctx interface{}, query interface{}) *MockIMessageService_GetMessagesByIDs_Call {
+	return &MockIMessageService_GetMessagesByIDs_Call{Call: _e.mock.On("GetMessagesByIDs", ctx, query)}
+}
+
+func (_c *MockIMessageService_GetMessagesByIDs_Call) Run(run func(ctx context.Context, query *dtos.GetMessagesByIDsQuery)) *MockIMessageService_GetMessagesByIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.GetMessagesByIDsQuery
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.GetMessagesByIDsQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_GetMessagesByIDs_Call) Return(getMessagesByIDsQueryResponse *dtos.GetMessagesByIDsQueryResponse, err error) *MockIMessageService_GetMessagesByIDs_Call {
+	_c.Call.Return(getMessagesByIDsQueryResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_GetMessagesByIDs_Call) RunAndReturn(run func(ctx context.Context, query *dtos.GetMessagesByIDsQuery) (*dtos.GetMessagesByIDsQueryResponse, error)) *MockIMessageService_GetMessagesByIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListMessages provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) ListMessages(ctx context.Context, query *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMessages")
+	}
+
+	var r0 *dtos.ListMessagesQueryResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.ListMessagesQuery) *dtos.ListMessagesQueryResponse); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.ListMessagesQueryResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.ListMessagesQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_ListMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListMessages'
+type MockIMessageService_ListMessages_Call struct {
+	*mock.Call
+}
+
+// ListMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query *dtos.ListMessagesQuery
+func (_e *MockIMessageService_Expecter) ListMessages(ctx interface{}, query interface{}) *MockIMessageService_ListMessages_Call {
+	return &MockIMessageService_ListMessages_Call{Call: _e.mock.On("ListMessages", ctx, query)}
+}
+
+func (_c *MockIMessageService_ListMessages_Call) Run(run func(ctx context.Context, query *dtos.ListMessagesQuery)) *MockIMessageService_ListMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.ListMessagesQuery
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.ListMessagesQuery)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_ListMessages_Call) Return(listMessagesQueryResponse *dtos.ListMessagesQueryResponse, err error) *MockIMessageService_ListMessages_Call {
+	_c.Call.Return(listMessagesQueryResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_ListMessages_Call) RunAndReturn(run func(ctx context.Context, query *dtos.ListMessagesQuery) (*dtos.ListMessagesQueryResponse, error)) *MockIMessageService_ListMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebuildProjections provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) RebuildProjections(ctx context.Context, cmd *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebuildProjections")
+	}
+
+	var r0 *dtos.RebuildProjectionsCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RebuildProjectionsCommand) *dtos.RebuildProjectionsCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.RebuildProjectionsCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.RebuildProjectionsCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_RebuildProjections_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebuildProjections'
+type MockIMessageService_RebuildProjections_Call struct {
+	*mock.Call
+}
+
+// RebuildProjections is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.RebuildProjectionsCommand
+func (_e *MockIMessageService_Expecter) RebuildProjections(ctx interface{}, cmd interface{}) *MockIMessageService_RebuildProjections_Call {
+	return &MockIMessageService_RebuildProjections_Call{Call: _e.mock.On("RebuildProjections", ctx, cmd)}
+}
+
+func (_c *MockIMessageService_RebuildProjections_Call) Run(run func(ctx context.Context, cmd *dtos.RebuildProjectionsCommand)) *MockIMessageService_RebuildProjections_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.RebuildProjectionsCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.RebuildProjectionsCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_RebuildProjections_Call) Return(rebuildProjectionsCommandResponse *dtos.RebuildProjectionsCommandResponse, err error) *MockIMessageService_RebuildProjections_Call {
+	_c.Call.Return(rebuildProjectionsCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_RebuildProjections_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error)) *MockIMessageService_RebuildProjections_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreMessage provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) RestoreMessage(ctx context.Context, cmd *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreMessage")
+	}
+
+	var r0 *dtos.RestoreMessageCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RestoreMessageCommand) *dtos.RestoreMessageCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.RestoreMessageCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.RestoreMessageCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_RestoreMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreMessage'
+type MockIMessageService_RestoreMessage_Call struct {
+	*mock.Call
+}
+
+// RestoreMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.RestoreMessageCommand
+func (_e *MockIMessageService_Expecter) RestoreMessage(ctx interface{}, cmd interface{}) *MockIMessageService_RestoreMessage_Call {
+	return &MockIMessageService_RestoreMessage_Call{Call: _e.mock.On("RestoreMessage", ctx, cmd)}
+}
+
+func (_c *MockIMessageService_RestoreMessage_Call) Run(run func(ctx context.Context, cmd *dtos.RestoreMessageCommand)) *MockIMessageService_RestoreMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.RestoreMessageCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.RestoreMessageCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_RestoreMessage_Call) Return(restoreMessageCommandResponse *dtos.RestoreMessageCommandResponse, err error) *MockIMessageService_RestoreMessage_Call {
+	_c.Call.Return(restoreMessageCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_RestoreMessage_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error)) *MockIMessageService_RestoreMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateMessage provides a mock function for the type MockIMessageService
+func (_mock *MockIMessageService) UpdateMessage(ctx context.Context, cmd *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMessage")
+	}
+
+	var r0 *dtos.UpdateMessageCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.UpdateMessageCommand) *dtos.UpdateMessageCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.UpdateMessageCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.UpdateMessageCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIMessageService_UpdateMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateMessage'
+type MockIMessageService_UpdateMessage_Call struct {
+	*mock.Call
+}
+
+// UpdateMessage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.UpdateMessageCommand
+func (_e *MockIMessageService_Expecter) UpdateMessage(ctx interface{}, cmd interface{}) *MockIMessageService_UpdateMessage_Call {
+	return &MockIMessageService_UpdateMessage_Call{Call: _e.mock.On("UpdateMessage", ctx, cmd)}
+}
+
+func (_c *MockIMessageService_UpdateMessage_Call) Run(run func(ctx context.Context, cmd *dtos.UpdateMessageCommand)) *MockIMessageService_UpdateMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.UpdateMessageCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.UpdateMessageCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIMessageService_UpdateMessage_Call) Return(updateMessageCommandResponse *dtos.UpdateMessageCommandResponse, err error) *MockIMessageService_UpdateMessage_Call {
+	_c.Call.Return(updateMessageCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIMessageService_UpdateMessage_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error)) *MockIMessageService_UpdateMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIRebuildProjectionsCommandHandler creates a new instance of MockIRebuildProjectionsCommandHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIRebuildProjectionsCommandHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIRebuildProjectionsCommandHandler {
+	mock := &MockIRebuildProjectionsCommandHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIRebuildProjectionsCommandHandler is an autogenerated mock type for the IRebuildProjectionsCommandHandler type
+type MockIRebuildProjectionsCommandHandler struct {
+	mock.Mock
+}
+
+type MockIRebuildProjectionsCommandHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIRebuildProjectionsCommandHandler) EXPECT() *MockIRebuildProjectionsCommandHandler_Expecter {
+	return &MockIRebuildProjectionsCommandHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIRebuildProjectionsCommandHandler
+func (_mock *MockIRebuildProjectionsCommandHandler) Handle(ctx context.Context, cmd *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.RebuildProjectionsCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RebuildProjectionsCommand) *dtos.RebuildProjectionsCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.RebuildProjectionsCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.RebuildProjectionsCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIRebuildProjectionsCommandHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIRebuildProjectionsCommandHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.RebuildProjectionsCommand
+func (_e *MockIRebuildProjectionsCommandHandler_Expecter) Handle(ctx interface{}, cmd interface{}) *MockIRebuildProjectionsCommandHandler_Handle_Call {
+	return &MockIRebuildProjectionsCommandHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, cmd)}
+}
+
+func (_c *MockIRebuildProjectionsCommandHandler_Handle_Call) Run(run func(ctx context.Context, cmd *dtos.RebuildProjectionsCommand)) *MockIRebuildProjectionsCommandHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.RebuildProjectionsCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.RebuildProjectionsCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIRebuildProjectionsCommandHandler_Handle_Call) Return(rebuildProjectionsCommandResponse *dtos.RebuildProjectionsCommandResponse, err error) *MockIRebuildProjectionsCommandHandler_Handle_Call {
+	_c.Call.Return(rebuildProjectionsCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIRebuildProjectionsCommandHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.RebuildProjectionsCommand) (*dtos.RebuildProjectionsCommandResponse, error)) *MockIRebuildProjectionsCommandHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
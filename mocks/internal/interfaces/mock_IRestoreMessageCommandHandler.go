@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIRestoreMessageCommandHandler creates a new instance of MockIRestoreMessageCommandHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIRestoreMessageCommandHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIRestoreMessageCommandHandler {
+	mock := &MockIRestoreMessageCommandHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIRestoreMessageCommandHandler is an autogenerated mock type for the IRestoreMessageCommandHandler type
+type MockIRestoreMessageCommandHandler struct {
+	mock.Mock
+}
+
+type MockIRestoreMessageCommandHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIRestoreMessageCommandHandler) EXPECT() *MockIRestoreMessageCommandHandler_Expecter {
+	return &MockIRestoreMessageCommandHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIRestoreMessageCommandHandler
+func (_mock *MockIRestoreMessageCommandHandler) Handle(ctx context.Context, cmd *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.RestoreMessageCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.RestoreMessageCommand) *dtos.RestoreMessageCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.RestoreMessageCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.RestoreMessageCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIRestoreMessageCommandHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIRestoreMessageCommandHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.RestoreMessageCommand
+func (_e *MockIRestoreMessageCommandHandler_Expecter) Handle(ctx interface{}, cmd interface{}) *MockIRestoreMessageCommandHandler_Handle_Call {
+	return &MockIRestoreMessageCommandHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, cmd)}
+}
+
+func (_c *MockIRestoreMessageCommandHandler_Handle_Call) Run(run func(ctx context.Context, cmd *dtos.RestoreMessageCommand)) *MockIRestoreMessageCommandHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.RestoreMessageCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.RestoreMessageCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIRestoreMessageCommandHandler_Handle_Call) Return(restoreMessageCommandResponse *dtos.RestoreMessageCommandResponse, err error) *MockIRestoreMessageCommandHandler_Handle_Call {
+	_c.Call.Return(restoreMessageCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIRestoreMessageCommandHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.RestoreMessageCommand) (*dtos.RestoreMessageCommandResponse, error)) *MockIRestoreMessageCommandHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package interfaces
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/internal/features/messages/dtos"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIUpdateMessageCommandHandler creates a new instance of MockIUpdateMessageCommandHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIUpdateMessageCommandHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIUpdateMessageCommandHandler {
+	mock := &MockIUpdateMessageCommandHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIUpdateMessageCommandHandler is an autogenerated mock type for the IUpdateMessageCommandHandler type
+type MockIUpdateMessageCommandHandler struct {
+	mock.Mock
+}
+
+type MockIUpdateMessageCommandHandler_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIUpdateMessageCommandHandler) EXPECT() *MockIUpdateMessageCommandHandler_Expecter {
+	return &MockIUpdateMessageCommandHandler_Expecter{mock: &_m.Mock}
+}
+
+// Handle provides a mock function for the type MockIUpdateMessageCommandHandler
+func (_mock *MockIUpdateMessageCommandHandler) Handle(ctx context.Context, cmd *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error) {
+	ret := _mock.Called(ctx, cmd)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 *dtos.UpdateMessageCommandResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error)); ok {
+		return returnFunc(ctx, cmd)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dtos.UpdateMessageCommand) *dtos.UpdateMessageCommandResponse); ok {
+		r0 = returnFunc(ctx, cmd)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dtos.UpdateMessageCommandResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dtos.UpdateMessageCommand) error); ok {
+		r1 = returnFunc(ctx, cmd)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUpdateMessageCommandHandler_Handle_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Handle'
+type MockIUpdateMessageCommandHandler_Handle_Call struct {
+	*mock.Call
+}
+
+// Handle is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cmd *dtos.UpdateMessageCommand
+func (_e *MockIUpdateMessageCommandHandler_Expecter) Handle(ctx interface{}, cmd interface{}) *MockIUpdateMessageCommandHandler_Handle_Call {
+	return &MockIUpdateMessageCommandHandler_Handle_Call{Call: _e.mock.On("Handle", ctx, cmd)}
+}
+
+func (_c *MockIUpdateMessageCommandHandler_Handle_Call) Run(run func(ctx context.Context, cmd *dtos.UpdateMessageCommand)) *MockIUpdateMessageCommandHandler_Handle_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dtos.UpdateMessageCommand
+		if args[1] != nil {
+			arg1 = args[1].(*dtos.UpdateMessageCommand)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUpdateMessageCommandHandler_Handle_Call) Return(updateMessageCommandResponse *dtos.UpdateMessageCommandResponse, err error) *MockIUpdateMessageCommandHandler_Handle_Call {
+	_c.Call.Return(updateMessageCommandResponse, err)
+	return _c
+}
+
+func (_c *MockIUpdateMessageCommandHandler_Handle_Call) RunAndReturn(run func(ctx context.Context, cmd *dtos.UpdateMessageCommand) (*dtos.UpdateMessageCommandResponse, error)) *MockIUpdateMessageCommandHandler_Handle_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiKeyFile is the format of the API keys file. Keys are stored as the hex-encoded
+// SHA-256 of the key, never in clear text.
+type apiKeyFile struct {
+	Keys []apiKeyEntry `json:"keys"`
+}
+
+type apiKeyEntry struct {
+	Hash     string   `json:"hash"`
+	Subject  string   `json:"subject"`
+	TenantID string   `json:"tenant_id"`
+	Scopes   []string `json:"scopes"`
+}
+
+// APIKeyVerifier authenticates static API keys loaded from a file of hashed keys.
+type APIKeyVerifier struct {
+	path string
+	keys []apiKeyEntry
+	mu   sync.RWMutex
+}
+
+// NewAPIKeyVerifier creates an APIKeyVerifier and loads the keys file at path.
+func NewAPIKeyVerifier(path string) (*APIKeyVerifier, error) {
+	v := &APIKeyVerifier{path: path}
+	if err := v.Load(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 of key, as stored in the keys file.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Load reads the keys file again.
+func (v *APIKeyVerifier) Load() error {
+	data, err := os.ReadFile(v.path)
+	if err != nil {
+		return fmt.Errorf("read API keys file: %w", err)
+	}
+
+	var file apiKeyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parse API keys file: %w", err)
+	}
+	for i, key := range file.Keys {
+		if len(key.Hash) != sha256.Size*2 || key.Subject == "" {
+			return fmt.Errorf("API key %d: a SHA-256 hash and a subject are required", i)
+		}
+		file.Keys[i].Hash = strings.ToLower(key.Hash)
+	}
+
+	v.mu.Lock()
+	v.keys = file.Keys
+	v.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the keys file when it changes, until ctx is done.
+func (v *APIKeyVerifier) Watch(ctx context.Context, interval time.Duration) {
+	watchFile(ctx, v.path, interval, v.Load)
+}
+
+// Verify authenticates credentials.APIKey.
+func (v *APIKeyVerifier) Verify(_ context.Context, credentials Credentials) (*Principal, error) {
+	if credentials.APIKey == "" {
+		return nil, ErrNoCredentials
+	}
+
+	hash := []byte(HashAPIKey(credentials.APIKey))
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, key := range v.keys {
+		if subtle.ConstantTimeCompare(hash, []byte(key.Hash)) == 1 {
+			return &Principal{
+				Subject:  key.Subject,
+				TenantID: key.TenantID,
+				Scopes:   key.Scopes,
+				Method:   MethodAPIKey,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+}
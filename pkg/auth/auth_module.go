@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/pkg/config"
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/fx"
+)
+
+// Module authenticates the requests. It must be listed before fiber.Module so that
+// the principal is known to the rate limiter and the routes.
+var Module = fx.Options(
+	fx.Provide(NewConfig),
+	fx.Provide(NewVerifiers),
+	fx.Decorate(DecorateRateLimitConfig),
+	fx.Invoke(UseAuthentication),
+)
+
+// NewVerifiers builds the enabled verifiers, in order: API keys, JWTs and client
+// certificates. The API keys and JWKS files are reloaded while the application runs.
+func NewVerifiers(lc fx.Lifecycle, cfg Config) ([]Verifier, error) {
+	var verifiers []Verifier
+	var watchers []func(ctx context.Context)
+
+	if cfg.APIKeysFile != "" {
+		verifier, err := NewAPIKeyVerifier(cfg.APIKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, verifier)
+		watchers = append(watchers, func(ctx context.Context) { verifier.Watch(ctx, cfg.ReloadInterval) })
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := NewKeySet(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, NewJWTVerifier(keys, cfg.JWT))
+		watchers = append(watchers, func(ctx context.Context) { keys.Watch(ctx, cfg.ReloadInterval) })
+	}
+
+	if cfg.ClientCertificate {
+		verifiers = append(verifiers, NewClientCertificateVerifier(cfg.ClientCertificateScopes...))
+	}
+
+	if len(watchers) > 0 && cfg.ReloadInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				for _, watch := range watchers {
+					go watch(ctx)
+				}
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+	}
+
+	return verifiers, nil
+}
+
+// UseAuthentication installs the authentication middleware on app.
+func UseAuthentication(app *fiber.App, cfg Config, verifiers []Verifier) {
+	if len(verifiers) == 0 && !cfg.Required {
+		return
+	}
+	app.Use(Authenticate(cfg.Required, verifiers...))
+}
+
+// DecorateRateLimitConfig rate limits by authenticated subject when FXF_RATE_LIMIT_KEY
+// is "subject" or "api_key". An API key is only trusted once verified, through the subject
+// of its principal: requests with an unknown key are limited by client IP.
+func DecorateRateLimitConfig(cfg fiberpkg.RateLimitConfig) fiberpkg.RateLimitConfig {
+	if key := config.String("FXF_RATE_LIMIT_KEY", "ip"); key != "subject" && key != "api_key" {
+		return cfg
+	}
+
+	rules := make([]fiberpkg.RateLimitRule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		rule.Key = fiberpkg.KeyByContext("subject", SubjectFromContext)
+		rules[i] = rule
+	}
+	cfg.Rules = rules
+	return cfg
+}
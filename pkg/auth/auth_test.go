@@ -0,0 +1,379 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/auth"
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJSON(t *testing.T, value any) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "file.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ed25519 ed25519.PrivateKey
+	secret  []byte
+}
+
+func newTestKeys(t *testing.T) (testKeys, string) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keys := testKeys{rsa: rsaKey, ed25519: edKey, secret: []byte("0123456789abcdef0123456789abcdef")}
+
+	path := writeJSON(t, map[string]any{"keys": []map[string]string{
+		{"kty": "oct", "kid": "hs", "k": encode(keys.secret)},
+		{"kty": "RSA", "kid": "rs", "n": encode(rsaKey.N.Bytes()), "e": encode(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": encode(edKey.Public().(ed25519.PublicKey))},
+	}})
+	return keys, path
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":       "user-1",
+		"iss":       "https://issuer.test",
+		"aud":       "fxf",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"scope":     "messages:read messages:write",
+		"tenant_id": "acme",
+	}
+}
+
+func TestAPIKeyVerifier(t *testing.T) {
+	path := writeJSON(t, map[string]any{"keys": []map[string]any{
+		{
+			"hash":      auth.HashAPIKey("secret-key"),
+			"subject":   "svc-1",
+			"tenant_id": "acme",
+			"scopes":    []string{"messages:read"},
+		},
+	}})
+	verifier, err := auth.NewAPIKeyVerifier(path)
+	require.NoError(t, err)
+
+	t.Run("should authenticate a known API key", func(t *testing.T) {
+		// Act
+		principal, err := verifier.Verify(context.Background(), auth.Credentials{APIKey: "secret-key"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "svc-1", principal.Subject)
+		assert.Equal(t, "acme", principal.TenantID)
+		assert.Equal(t, auth.MethodAPIKey, principal.Method)
+		assert.True(t, principal.HasScope("messages:read"))
+		assert.False(t, principal.HasScope("messages:write"))
+	})
+
+	t.Run("should reject an unknown API key", func(t *testing.T) {
+		// Act
+		_, err := verifier.Verify(context.Background(), auth.Credentials{APIKey: "other-key"})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("should skip requests without an API key", func(t *testing.T) {
+		// Act
+		_, err := verifier.Verify(context.Background(), auth.Credentials{BearerToken: "token"})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrNoCredentials)
+	})
+
+	t.Run("should refuse a keys file with clear text keys", func(t *testing.T) {
+		// Arrange
+		path := writeJSON(t, map[string]any{"keys": []map[string]any{{"hash": "secret-key", "subject": "svc-1"}}})
+
+		// Act
+		_, err := auth.NewAPIKeyVerifier(path)
+
+		// Assert
+		require.Error(t, err)
+	})
+}
+
+func TestJWTVerifier(t *testing.T) {
+	keys, path := newTestKeys(t)
+	keySet, err := auth.NewKeySet(path)
+	require.NoError(t, err)
+	verifier := auth.NewJWTVerifier(keySet, auth.JWTConfig{Issuer: "https://issuer.test", Audience: "fxf"})
+
+	for _, tc := range []struct {
+		method jwt.SigningMethod
+		key    any
+		name   string
+		kid    string
+	}{
+		{name: "HS256", method: jwt.SigningMethodHS256, kid: "hs", key: keys.secret},
+		{name: "RS256", method: jwt.SigningMethodRS256, kid: "rs", key: keys.rsa},
+		{name: "EdDSA", method: jwt.SigningMethodEdDSA, kid: "ed", key: keys.ed25519},
+	} {
+		t.Run("should authenticate a "+tc.name+" token", func(t *testing.T) {
+			// Arrange
+			token := sign(t, tc.method, tc.kid, tc.key, validClaims())
+
+			// Act
+			principal, err := verifier.Verify(context.Background(), auth.Credentials{BearerToken: token})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", principal.Subject)
+			assert.Equal(t, "acme", principal.TenantID)
+			assert.Equal(t, []string{"messages:read", "messages:write"}, principal.Scopes)
+			assert.Equal(t, auth.MethodJWT, principal.Method)
+		})
+	}
+
+	t.Run("should reject expired tokens and wrong audiences", func(t *testing.T) {
+		// Arrange
+		expired := validClaims()
+		expired["exp"] = time.Now().Add(-time.Minute).Unix()
+		audience := validClaims()
+		audience["aud"] = "other"
+
+		for _, claims := range []jwt.MapClaims{expired, audience} {
+			token := sign(t, jwt.SigningMethodHS256, "hs", keys.secret, claims)
+
+			// Act
+			_, err := verifier.Verify(context.Background(), auth.Credentials{BearerToken: token})
+
+			// Assert
+			require.ErrorIs(t, err, auth.ErrInvalidCredentials)
+		}
+	})
+
+	t.Run("should reject an HMAC token signed with a public key", func(t *testing.T) {
+		// Arrange
+		token := sign(t, jwt.SigningMethodHS256, "rs", keys.rsa.PublicKey.N.Bytes(), validClaims())
+
+		// Act
+		_, err := verifier.Verify(context.Background(), auth.Credentials{BearerToken: token})
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("should verify tokens of a rotated key after a reload", func(t *testing.T) {
+		// Arrange
+		rotated := []byte("fedcba9876543210fedcba9876543210")
+		token := sign(t, jwt.SigningMethodHS256, "hs-2", rotated, validClaims())
+		data, err := json.Marshal(
+			map[string]any{"keys": []map[string]string{{"kty": "oct", "kid": "hs-2", "k": encode(rotated)}}},
+		)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		// Act
+		_, errBefore := verifier.Verify(context.Background(), auth.Credentials{BearerToken: token})
+		require.NoError(t, keySet.Load())
+		_, errAfter := verifier.Verify(context.Background(), auth.Credentials{BearerToken: token})
+
+		// Assert
+		require.ErrorIs(t, errBefore, auth.ErrInvalidCredentials)
+		require.NoError(t, errAfter)
+	})
+}
+
+func TestClientCertificateVerifier(t *testing.T) {
+	t.Run("should authenticate the client certificate subject", func(t *testing.T) {
+		// Arrange
+		verifier := auth.NewClientCertificateVerifier("messages:read")
+		credentials := auth.Credentials{ClientIdentity: &fiberpkg.ClientIdentity{Subject: "svc-2"}}
+
+		// Act
+		principal, err := verifier.Verify(context.Background(), credentials)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "svc-2", principal.Subject)
+		assert.Equal(t, auth.MethodClientCertificate, principal.Method)
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	path := writeJSON(t, map[string]any{"keys": []map[string]any{
+		{"hash": auth.HashAPIKey("secret-key"), "subject": "svc-1"},
+	}})
+	verifier, err := auth.NewAPIKeyVerifier(path)
+	require.NoError(t, err)
+
+	newApp := func(required bool) *fiber.App {
+		app := fiber.New()
+		app.Use(auth.Authenticate(required, verifier))
+		app.Get("/whoami", func(c *fiber.Ctx) error {
+			subject, ok := auth.SubjectFromContext(c.UserContext())
+			if !ok {
+				subject = "anonymous"
+			}
+			return c.SendString(subject)
+		})
+		return app
+	}
+
+	t.Run("should store the principal in the request context", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set(fiberpkg.APIKeyHeader, "secret-key")
+
+		// Act
+		resp, err := newApp(true).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "svc-1", string(body))
+	})
+
+	t.Run("should reject invalid credentials with a problem response", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+		req.Header.Set(fiberpkg.APIKeyHeader, "wrong-key")
+
+		// Act
+		resp, err := newApp(false).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, "Bearer", resp.Header.Get(fiber.HeaderWWWAuthenticate))
+	})
+
+	t.Run("should let anonymous requests through unless authentication is required", func(t *testing.T) {
+		// Act
+		optional, err := newApp(false).Test(httptest.NewRequest(http.MethodGet, "/whoami", nil))
+		require.NoError(t, err)
+		required, err := newApp(true).Test(httptest.NewRequest(http.MethodGet, "/whoami", nil))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusOK, optional.StatusCode)
+		assert.Equal(t, fiber.StatusUnauthorized, required.StatusCode)
+	})
+}
+
+func TestDecorateRateLimitConfig(t *testing.T) {
+	t.Run("should rate limit by the subject of a verified API key", func(t *testing.T) {
+		// Arrange
+		t.Setenv("FXF_RATE_LIMIT_KEY", "api_key")
+		path := writeJSON(t, map[string]any{"keys": []map[string]any{
+			{"hash": auth.HashAPIKey("secret-key"), "subject": "svc-1"},
+		}})
+		verifier, err := auth.NewAPIKeyVerifier(path)
+		require.NoError(t, err)
+		cfg := auth.DecorateRateLimitConfig(fiberpkg.RateLimitConfig{
+			Enabled: true,
+			Rules: []fiberpkg.RateLimitRule{{
+				Method: fiber.MethodPost,
+				Path:   "/messages",
+				Key:    fiberpkg.KeyByIP,
+				Limit:  fiberpkg.RateLimit{Rate: 1, Burst: 1},
+			}},
+		})
+
+		app := fiber.New()
+		app.Use(auth.Authenticate(false, verifier))
+		fiberpkg.UseRateLimiter(app, cfg, fiberpkg.NewInMemoryRateLimitStore())
+		app.Post("/messages", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+		newRequest := func(apiKey string) *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/messages", nil)
+			if apiKey != "" {
+				req.Header.Set(fiberpkg.APIKeyHeader, apiKey)
+			}
+			return req
+		}
+
+		// Act
+		first, err := app.Test(newRequest("secret-key"))
+		require.NoError(t, err)
+		second, err := app.Test(newRequest("secret-key"))
+		require.NoError(t, err)
+		madeUp, err := app.Test(newRequest("made-up-key"))
+		require.NoError(t, err)
+		anonymous, err := app.Test(newRequest(""))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusCreated, first.StatusCode)
+		assert.Equal(t, fiber.StatusTooManyRequests, second.StatusCode)
+		assert.Equal(t, fiber.StatusUnauthorized, madeUp.StatusCode)
+		assert.Equal(t, fiber.StatusCreated, anonymous.StatusCode)
+	})
+}
+
+func TestRequireScopes(t *testing.T) {
+	t.Run("should reject principals missing a scope with a forbidden problem", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Use(func(c *fiber.Ctx) error {
+			c.SetUserContext(
+				auth.NewContext(c.UserContext(), &auth.Principal{Subject: "user-1", Scopes: []string{"messages:read"}}),
+			)
+			return c.Next()
+		})
+		app.Get(
+			"/read",
+			auth.RequireScopes("messages:read"),
+			func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+		)
+		app.Get(
+			"/write",
+			auth.RequireScopes("messages:write"),
+			func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) },
+		)
+
+		// Act
+		read, err := app.Test(httptest.NewRequest(http.MethodGet, "/read", nil))
+		require.NoError(t, err)
+		write, err := app.Test(httptest.NewRequest(http.MethodGet, "/write", nil))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusOK, read.StatusCode)
+		assert.Equal(t, fiber.StatusForbidden, write.StatusCode)
+		assert.Equal(t, problem.ContentType, write.Header.Get(fiber.HeaderContentType))
+	})
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+
+	"github.com/arielsrv/fxf/pkg/problem"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Authorization decisions.
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+var (
+	// ErrUnauthenticated is returned by Authorize when a scoped request has no principal.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned by Authorize when the principal lacks a required scope.
+	ErrForbidden = errors.New("forbidden")
+)
+
+var authorizationDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "fxf",
+	Subsystem: "auth",
+	Name:      "authorization_decisions_total",
+	Help:      "Number of authorization decisions, by request type and decision.",
+}, []string{"request", "decision"})
+
+// ScopedRequest is implemented by requests that can only be handled for a principal
+// holding all the returned scopes.
+type ScopedRequest interface {
+	RequiredScopes() []string
+}
+
+// Authorize checks that the principal of ctx holds the scopes required by request and
+// writes the decision to the audit log. Requests that do not implement ScopedRequest
+// are always allowed.
+func Authorize(ctx context.Context, request any) error {
+	scoped, ok := request.(ScopedRequest)
+	if !ok {
+		return nil
+	}
+	return authorize(ctx, requestName(request), scoped.RequiredScopes())
+}
+
+// RequireScopes returns a route middleware that rejects the principals lacking one of
+// scopes with a 401 or 403 problem.
+func RequireScopes(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := authorize(c.UserContext(), c.Method()+" "+c.Route().Path, scopes); err != nil {
+			return WriteError(c, err)
+		}
+		return c.Next()
+	}
+}
+
+// WriteError answers with the 401 or 403 problem matching an error returned by
+// Authorize. Other errors are returned unchanged.
+func WriteError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return problem.Write(c, fiber.StatusUnauthorized, "authentication required")
+	case errors.Is(err, ErrForbidden):
+		return problem.Write(c, fiber.StatusForbidden, err.Error())
+	default:
+		return err
+	}
+}
+
+func authorize(ctx context.Context, resource string, scopes []string) error {
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	principal, ok := FromContext(ctx)
+	if !ok {
+		audit(ctx, resource, DecisionDeny, "", scopes, scopes)
+		return fmt.Errorf("%w: %s requires an authenticated caller", ErrUnauthenticated, resource)
+	}
+
+	var missing []string
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		audit(ctx, resource, DecisionDeny, principal.Subject, scopes, missing)
+		return fmt.Errorf("%w: missing scopes %v", ErrForbidden, missing)
+	}
+
+	audit(ctx, resource, DecisionAllow, principal.Subject, scopes, nil)
+	return nil
+}
+
+// audit writes an authorization decision to the log, tagged audit=true so that it
+// can be routed to the audit trail. Denials are logged as warnings.
+func audit(ctx context.Context, resource, decision, subject string, required, missing []string) {
+	authorizationDecisions.WithLabelValues(resource, decision).Inc()
+
+	level := slog.LevelInfo
+	if decision == DecisionDeny {
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "authorization decision",
+		slog.Bool("audit", true),
+		slog.String("decision", decision),
+		slog.String("resource", resource),
+		slog.String("subject", subject),
+		slog.Any("required_scopes", required),
+		slog.Any("missing_scopes", missing),
+	)
+}
+
+// requestName returns the type name of request, e.g. "dtos.CreateMessageCommand".
+func requestName(request any) string {
+	t := reflect.TypeOf(request)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.String()
+}
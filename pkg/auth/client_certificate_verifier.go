@@ -0,0 +1,28 @@
+package auth
+
+import "context"
+
+// ClientCertificateVerifier authenticates callers by the verified mTLS client
+// certificate, using the certificate subject common name as the principal subject.
+type ClientCertificateVerifier struct {
+	scopes []string
+}
+
+// NewClientCertificateVerifier creates a ClientCertificateVerifier granting scopes.
+func NewClientCertificateVerifier(scopes ...string) *ClientCertificateVerifier {
+	return &ClientCertificateVerifier{scopes: scopes}
+}
+
+// Verify authenticates credentials.ClientIdentity.
+func (v *ClientCertificateVerifier) Verify(_ context.Context, credentials Credentials) (*Principal, error) {
+	identity := credentials.ClientIdentity
+	if identity == nil || identity.Subject == "" {
+		return nil, ErrNoCredentials
+	}
+
+	return &Principal{
+		Subject: identity.Subject,
+		Scopes:  v.scopes,
+		Method:  MethodClientCertificate,
+	}, nil
+}
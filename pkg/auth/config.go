@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/config"
+)
+
+// Config is the configuration of the authentication middleware. A verifier is
+// enabled when its file is set.
+type Config struct {
+	APIKeysFile             string
+	JWKSFile                string
+	JWT                     JWTConfig
+	ClientCertificateScopes []string
+	ReloadInterval          time.Duration
+	Required                bool
+	ClientCertificate       bool
+}
+
+// NewConfig reads the authentication configuration from FXF_AUTH_* environment variables.
+func NewConfig() Config {
+	return Config{
+		Required:    config.Bool("FXF_AUTH_REQUIRED", false),
+		APIKeysFile: config.String("FXF_AUTH_API_KEYS_FILE", ""),
+		JWKSFile:    config.String("FXF_AUTH_JWKS_FILE", ""),
+		JWT: JWTConfig{
+			Issuer:      config.String("FXF_AUTH_JWT_ISSUER", ""),
+			Audience:    config.String("FXF_AUTH_JWT_AUDIENCE", ""),
+			TenantClaim: config.String("FXF_AUTH_JWT_TENANT_CLAIM", DefaultTenantClaim),
+		},
+		ClientCertificate:       config.Bool("FXF_AUTH_CLIENT_CERTIFICATE", false),
+		ClientCertificateScopes: config.List("FXF_AUTH_CLIENT_CERTIFICATE_SCOPES", nil),
+		ReloadInterval:          config.Duration("FXF_AUTH_RELOAD_INTERVAL", 30*time.Second),
+	}
+}
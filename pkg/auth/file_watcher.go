@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// watchFile calls load every interval when the modification time of path changed,
+// until ctx is done. Load errors are logged and the previous content is kept.
+func watchFile(ctx context.Context, path string, interval time.Duration, load func() error) {
+	var lastModTime time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastModTime = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(lastModTime) {
+				continue
+			}
+			if err := load(); err != nil {
+				slog.ErrorContext(
+					ctx,
+					"failed to reload file",
+					slog.String("path", path),
+					slog.String("err", err.Error()),
+				)
+				continue
+			}
+			lastModTime = info.ModTime()
+			slog.InfoContext(ctx, "reloaded file", slog.String("path", path))
+		}
+	}
+}
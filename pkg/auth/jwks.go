@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwk is a JSON Web Key (RFC 7517) of type oct, RSA or OKP (Ed25519).
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+}
+
+// KeySet is a JSON Web Key Set loaded from a local file. Keys are looked up by kid,
+// and rotating keys only requires rewriting the file: it is reloaded on change.
+type KeySet struct {
+	keys map[string]any
+	path string
+	mu   sync.RWMutex
+}
+
+// NewKeySet creates a KeySet and loads the JWKS file at path.
+func NewKeySet(path string) (*KeySet, error) {
+	s := &KeySet{path: path}
+	if err := s.Load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Load reads the JWKS file again.
+func (s *KeySet) Load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("read JWKS file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("parse JWKS file: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Kid == "" {
+			return errors.New("JWKS key without kid")
+		}
+		parsed, err := key.parse()
+		if err != nil {
+			return fmt.Errorf("JWKS key %q: %w", key.Kid, err)
+		}
+		keys[key.Kid] = parsed
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the JWKS file when it changes, until ctx is done.
+func (s *KeySet) Watch(ctx context.Context, interval time.Duration) {
+	watchFile(ctx, s.path, interval, s.Load)
+}
+
+// Key returns the key identified by kid: a []byte for oct keys, an *rsa.PublicKey for
+// RSA keys and an ed25519.PublicKey for OKP keys.
+func (s *KeySet) Key(kid string) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+func (k jwk) parse() (any, error) {
+	switch k.Kty {
+	case "oct":
+		return decodeSegment(k.K)
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeSegment(value string) ([]byte, error) {
+	if value == "" {
+		return nil, errors.New("missing key material")
+	}
+	return base64.RawURLEncoding.DecodeString(value)
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultTenantClaim is the JWT claim holding the tenant of the principal.
+const DefaultTenantClaim = "tenant_id"
+
+// JWTConfig configures a JWTVerifier. Issuer and Audience are validated when set.
+type JWTConfig struct {
+	Issuer      string
+	Audience    string
+	TenantClaim string
+}
+
+// JWTVerifier authenticates bearer JWTs signed with HS256, RS256 or EdDSA by a key
+// of a KeySet, selected by the kid header.
+type JWTVerifier struct {
+	keys   *KeySet
+	parser *jwt.Parser
+	config JWTConfig
+}
+
+// NewJWTVerifier creates a JWTVerifier.
+func NewJWTVerifier(keys *KeySet, cfg JWTConfig) *JWTVerifier {
+	if cfg.TenantClaim == "" {
+		cfg.TenantClaim = DefaultTenantClaim
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &JWTVerifier{keys: keys, config: cfg, parser: jwt.NewParser(opts...)}
+}
+
+// Verify authenticates credentials.BearerToken.
+func (v *JWTVerifier) Verify(_ context.Context, credentials Credentials) (*Principal, error) {
+	if credentials.BearerToken == "" {
+		return nil, ErrNoCredentials
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(credentials.BearerToken, claims, v.key); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrInvalidCredentials)
+	}
+	tenantID, _ := claims[v.config.TenantClaim].(string)
+
+	return &Principal{
+		Subject:  subject,
+		TenantID: tenantID,
+		Scopes:   scopes(claims),
+		Method:   MethodJWT,
+	}, nil
+}
+
+// key returns the verification key of token, checking that its type matches the
+// signing method so that a public key can never be used as an HMAC secret.
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := v.keys.Key(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if _, ok := key.([]byte); ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if _, ok := key.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if _, ok := key.(ed25519.PublicKey); ok {
+			return key, nil
+		}
+	}
+	return nil, errors.New("signing method does not match the key type")
+}
+
+// scopes reads the space-separated "scope" claim (RFC 8693) or the "scp" array.
+func scopes(claims jwt.MapClaims) []string {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+
+	values, _ := claims["scp"].([]any)
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
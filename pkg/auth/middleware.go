@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/arielsrv/fxf/pkg/logger"
+	"github.com/arielsrv/fxf/pkg/problem"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var authenticationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "fxf",
+	Subsystem: "auth",
+	Name:      "failures_total",
+	Help:      "Number of requests rejected by the authentication middleware, by reason.",
+}, []string{"reason"})
+
+// Authenticate returns a middleware that authenticates the request with the first
+// verifier that accepts its credentials and stores the Principal in the request
+// context, where mediator handlers read it with FromContext.
+//
+// Rejected credentials get a 401 problem. Requests without credentials are let
+// through anonymously unless required is set.
+func Authenticate(required bool, verifiers ...Verifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		credentials := credentialsFrom(c)
+
+		for _, verifier := range verifiers {
+			principal, err := verifier.Verify(ctx, credentials)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			if err != nil {
+				slog.WarnContext(ctx, "authentication failed", slog.String("err", err.Error()))
+				return unauthorized(c, "invalid_credentials", "invalid credentials")
+			}
+
+			ctx = NewContext(ctx, principal)
+			ctx = logger.ContextWithAttrs(ctx, slog.String("subject", principal.Subject))
+			trace.SpanFromContext(ctx).SetAttributes(
+				attribute.String("enduser.id", principal.Subject),
+				attribute.String("auth.method", principal.Method),
+			)
+			c.SetUserContext(ctx)
+			return c.Next()
+		}
+
+		if required {
+			return unauthorized(c, "missing_credentials", "authentication required")
+		}
+		return c.Next()
+	}
+}
+
+func credentialsFrom(c *fiber.Ctx) Credentials {
+	credentials := Credentials{APIKey: c.Get(fiberpkg.APIKeyHeader)}
+	if scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " "); ok &&
+		strings.EqualFold(scheme, "Bearer") {
+		credentials.BearerToken = strings.TrimSpace(token)
+	}
+	if identity, ok := fiberpkg.ClientIdentityFromContext(c.UserContext()); ok {
+		credentials.ClientIdentity = identity
+	}
+	return credentials
+}
+
+func unauthorized(c *fiber.Ctx, reason, detail string) error {
+	authenticationFailures.WithLabelValues(reason).Inc()
+	c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+	return problem.Write(c, fiber.StatusUnauthorized, detail)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+// Authentication methods of a Principal.
+const (
+	MethodAPIKey            = "api_key"
+	MethodJWT               = "jwt"
+	MethodClientCertificate = "client_certificate"
+)
+
+// Principal is an authenticated caller.
+type Principal struct {
+	Subject  string
+	TenantID string
+	Method   string
+	Scopes   []string
+}
+
+// HasScope reports whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal.
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal carried by ctx, if the caller was authenticated.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// SubjectFromContext returns the subject of the principal carried by ctx, if any.
+func SubjectFromContext(ctx context.Context) (string, bool) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return principal.Subject, true
+}
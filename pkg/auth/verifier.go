@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+)
+
+var (
+	// ErrNoCredentials is returned by a Verifier when the request carries no
+	// credentials it understands, so that the next verifier is tried.
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is returned by a Verifier when the credentials are rejected.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Credentials are the credentials presented by a request.
+type Credentials struct {
+	ClientIdentity *fiberpkg.ClientIdentity
+	APIKey         string
+	BearerToken    string
+}
+
+// Verifier authenticates credentials. Implementations return ErrNoCredentials when
+// the credentials are not theirs to verify and wrap ErrInvalidCredentials when they
+// reject them.
+type Verifier interface {
+	Verify(ctx context.Context, credentials Credentials) (*Principal, error)
+}
@@ -0,0 +1,71 @@
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Module provides the system Clock.
+var Module = fx.Options(
+	fx.Provide(New),
+)
+
+// Clock tells the current time. Inject it instead of calling time.Now so that tests
+// control the timestamps.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+// New returns the system Clock, which reports UTC times.
+func New() Clock {
+	return systemClock{}
+}
+
+func (systemClock) Now() time.Time {
+	return time.Now().UTC()
+}
+
+// Fixed is a Clock that always reports the same time.
+type Fixed time.Time
+
+// Now returns the fixed time.
+func (f Fixed) Now() time.Time {
+	return time.Time(f)
+}
+
+// Manual is a Clock that reports the time it is set to, for the tests that move the
+// time forward.
+type Manual struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+// NewManual returns a Manual clock set to at.
+func NewManual(at time.Time) *Manual {
+	return &Manual{now: at}
+}
+
+// Now returns the time the clock is set to.
+func (m *Manual) Now() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.now
+}
+
+// Set sets the clock to at.
+func (m *Manual) Set(at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = at
+}
+
+// Advance moves the clock forward by d.
+func (m *Manual) Advance(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.now = m.now.Add(d)
+}
@@ -0,0 +1,52 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("should report the current UTC time", func(t *testing.T) {
+		// Arrange
+		c := clock.New()
+
+		// Act
+		now := c.Now()
+
+		// Assert
+		assert.Equal(t, time.UTC, now.Location())
+		assert.WithinDuration(t, time.Now(), now, time.Second)
+	})
+}
+
+func TestFixed(t *testing.T) {
+	t.Run("should always report the fixed time", func(t *testing.T) {
+		// Arrange
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		c := clock.Fixed(at)
+
+		// Act & Assert
+		assert.Equal(t, at, c.Now())
+		assert.Equal(t, at, c.Now())
+	})
+}
+
+func TestManual(t *testing.T) {
+	t.Run("should report the time it is set to", func(t *testing.T) {
+		// Arrange
+		at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		c := clock.NewManual(at)
+
+		// Act
+		c.Advance(time.Minute)
+		advanced := c.Now()
+		c.Set(at)
+
+		// Assert
+		assert.Equal(t, at.Add(time.Minute), advanced)
+		assert.Equal(t, at, c.Now())
+	})
+}
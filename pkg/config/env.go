@@ -0,0 +1,80 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String returns the value of the environment variable key, or def when it is unset or empty.
+func String(key, def string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return def
+}
+
+// Int returns the environment variable key parsed as an int, or def when it is unset or invalid.
+func Int(key string, def int) int {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("invalid integer in environment, using default", slog.String("key", key), slog.Int("default", def))
+		return def
+	}
+	return parsed
+}
+
+// Bool returns the environment variable key parsed as a bool, or def when it is unset or invalid.
+func Bool(key string, def bool) bool {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("invalid boolean in environment, using default", slog.String("key", key), slog.Bool("default", def))
+		return def
+	}
+	return parsed
+}
+
+// Duration returns the environment variable key parsed as a time.Duration, or def when
+// it is unset or invalid.
+func Duration(key string, def time.Duration) time.Duration {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn(
+			"invalid duration in environment, using default",
+			slog.String("key", key),
+			slog.Duration("default", def),
+		)
+		return def
+	}
+	return parsed
+}
+
+// List returns the comma-separated environment variable key as a slice of trimmed,
+// non-empty values, or def when it is unset or empty.
+func List(key string, def []string) []string {
+	value := String(key, "")
+	if value == "" {
+		return def
+	}
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
@@ -0,0 +1,40 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnv(t *testing.T) {
+	t.Run("should read values from the environment", func(t *testing.T) {
+		// Arrange
+		t.Setenv("FXF_TEST_STRING", "value")
+		t.Setenv("FXF_TEST_INT", "42")
+		t.Setenv("FXF_TEST_BOOL", "true")
+		t.Setenv("FXF_TEST_DURATION", "3s")
+		t.Setenv("FXF_TEST_LIST", " a, b,,c ")
+
+		// Act & Assert
+		assert.Equal(t, "value", config.String("FXF_TEST_STRING", "def"))
+		assert.Equal(t, 42, config.Int("FXF_TEST_INT", 1))
+		assert.True(t, config.Bool("FXF_TEST_BOOL", false))
+		assert.Equal(t, 3*time.Second, config.Duration("FXF_TEST_DURATION", time.Second))
+		assert.Equal(t, []string{"a", "b", "c"}, config.List("FXF_TEST_LIST", nil))
+	})
+
+	t.Run("should fall back to defaults when unset or invalid", func(t *testing.T) {
+		// Arrange
+		t.Setenv("FXF_TEST_INT", "forty-two")
+		t.Setenv("FXF_TEST_DURATION", "soon")
+
+		// Act & Assert
+		assert.Equal(t, "def", config.String("FXF_TEST_UNSET", "def"))
+		assert.Equal(t, 1, config.Int("FXF_TEST_INT", 1))
+		assert.False(t, config.Bool("FXF_TEST_UNSET", false))
+		assert.Equal(t, time.Second, config.Duration("FXF_TEST_DURATION", time.Second))
+		assert.Equal(t, []string{"x"}, config.List("FXF_TEST_UNSET", []string{"x"}))
+	})
+}
@@ -0,0 +1,50 @@
+package fiber
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ClientIdentity is the identity of a client authenticated by a verified TLS certificate.
+type ClientIdentity struct {
+	Certificate  *x509.Certificate
+	Subject      string
+	SerialNumber string
+	DNSNames     []string
+	URIs         []string
+}
+
+type clientIdentityKey struct{}
+
+// ClientIdentityFromContext returns the verified client certificate identity, if any.
+func ClientIdentityFromContext(ctx context.Context) (*ClientIdentity, bool) {
+	identity, ok := ctx.Value(clientIdentityKey{}).(*ClientIdentity)
+	return identity, ok
+}
+
+// ClientCertificate returns a middleware that stores the identity of the verified
+// client certificate of the TLS connection in the request context.
+func ClientCertificate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		state := c.Context().TLSConnectionState()
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+			return c.Next()
+		}
+
+		leaf := state.VerifiedChains[0][0]
+		identity := &ClientIdentity{
+			Certificate:  leaf,
+			Subject:      leaf.Subject.CommonName,
+			SerialNumber: leaf.SerialNumber.String(),
+			DNSNames:     leaf.DNSNames,
+		}
+		for _, uri := range leaf.URIs {
+			identity.URIs = append(identity.URIs, uri.String())
+		}
+
+		c.SetUserContext(context.WithValue(c.UserContext(), clientIdentityKey{}, identity))
+		return c.Next()
+	}
+}
@@ -0,0 +1,220 @@
+package fiber
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/config"
+	"github.com/arielsrv/fxf/pkg/problem"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	concurrencyLimit = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "concurrency",
+		Name:      "limit",
+		Help:      "Current adaptive concurrency limit.",
+	})
+
+	concurrencyInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "concurrency",
+		Name:      "in_flight",
+		Help:      "Number of requests currently admitted by the concurrency limiter.",
+	})
+
+	concurrencyShed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "fxf",
+		Subsystem: "concurrency",
+		Name:      "shed_total",
+		Help:      "Number of requests shed by the concurrency limiter, by priority.",
+	}, []string{"priority"})
+)
+
+// Priority is the admission priority of a request. Reads are admitted up to the full
+// limit, writes only up to the part of it that is not reserved for reads.
+type Priority int
+
+const (
+	PriorityRead Priority = iota
+	PriorityWrite
+)
+
+func (p Priority) String() string {
+	if p == PriorityRead {
+		return "read"
+	}
+	return "write"
+}
+
+// ConcurrencyLimitConfig is the configuration of the adaptive concurrency limiter.
+type ConcurrencyLimitConfig struct {
+	// TargetLatency is the latency above which the limit is decreased.
+	TargetLatency time.Duration
+	// RetryAfter is sent to shed clients.
+	RetryAfter time.Duration
+	Initial    int
+	Min        int
+	Max        int
+	// ReadReserve is the fraction of the limit that only reads can use.
+	ReadReserve float64
+	// Backoff is the factor applied to the limit when latency exceeds the target.
+	Backoff float64
+	Enabled bool
+}
+
+// NewConcurrencyLimitConfig reads the limiter configuration from FXF_CONCURRENCY_* environment
+// variables, and rejects it when Validate does.
+func NewConcurrencyLimitConfig() (ConcurrencyLimitConfig, error) {
+	cfg := ConcurrencyLimitConfig{
+		Enabled:       config.Bool("FXF_CONCURRENCY_LIMIT_ENABLED", true),
+		Initial:       config.Int("FXF_CONCURRENCY_LIMIT_INITIAL", 100),
+		Min:           config.Int("FXF_CONCURRENCY_LIMIT_MIN", 10),
+		Max:           config.Int("FXF_CONCURRENCY_LIMIT_MAX", 1000),
+		TargetLatency: config.Duration("FXF_CONCURRENCY_TARGET_LATENCY", 250*time.Millisecond),
+		ReadReserve:   float64(config.Int("FXF_CONCURRENCY_READ_RESERVE_PERCENT", 20)) / 100,
+		Backoff:       0.9,
+		RetryAfter:    time.Second,
+	}
+	if err := cfg.Validate(); err != nil {
+		return ConcurrencyLimitConfig{}, err
+	}
+	return cfg, nil
+}
+
+// Validate rejects the limits the limiter cannot adapt between: the minimum must be at
+// least 1 and the initial limit between the minimum and the maximum, the read reserve
+// must leave part of the limit to writes and the backoff must decrease the limit.
+func (c ConcurrencyLimitConfig) Validate() error {
+	switch {
+	case c.Min < 1:
+		return fmt.Errorf("concurrency limit minimum must be at least 1, got %d", c.Min)
+	case c.Max < c.Min:
+		return fmt.Errorf("concurrency limit maximum %d is below the minimum %d", c.Max, c.Min)
+	case c.Initial < c.Min || c.Initial > c.Max:
+		return fmt.Errorf("concurrency limit initial %d is not between %d and %d", c.Initial, c.Min, c.Max)
+	case c.ReadReserve < 0 || c.ReadReserve >= 1:
+		return fmt.Errorf("concurrency read reserve must be in [0, 1), got %v", c.ReadReserve)
+	case c.Backoff <= 0 || c.Backoff >= 1:
+		return fmt.Errorf("concurrency limit backoff must be in (0, 1), got %v", c.Backoff)
+	case c.TargetLatency <= 0:
+		return fmt.Errorf("concurrency target latency must be positive, got %s", c.TargetLatency)
+	}
+	return nil
+}
+
+// AdaptiveLimiter is an AIMD concurrency limiter: the limit grows by one per limit's
+// worth of requests completed under the target latency, and is multiplied by the
+// backoff factor when a request completes over it. Each decrease starts a new
+// generation, and only requests admitted in the current generation can decrease the
+// limit again, so a burst of slow requests backs off once instead of once per request.
+type AdaptiveLimiter struct {
+	config     ConcurrencyLimitConfig
+	limit      float64
+	inFlight   int
+	generation uint64
+	mu         sync.Mutex
+}
+
+// NewAdaptiveLimiter creates a new AdaptiveLimiter.
+func NewAdaptiveLimiter(cfg ConcurrencyLimitConfig) *AdaptiveLimiter {
+	l := &AdaptiveLimiter{config: cfg, limit: float64(cfg.Initial)}
+	concurrencyLimit.Set(l.limit)
+	return l
+}
+
+// Acquire admits a request of the given priority. When admitted, release must be
+// called with the observed latency once the request completed.
+func (l *AdaptiveLimiter) Acquire(priority Priority) (func(latency time.Duration), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	capacity := l.limit
+	if priority == PriorityWrite {
+		capacity = math.Max(1, capacity*(1-l.config.ReadReserve))
+	}
+	if float64(l.inFlight) >= capacity {
+		concurrencyShed.WithLabelValues(priority.String()).Inc()
+		return nil, false
+	}
+
+	l.inFlight++
+	concurrencyInFlight.Set(float64(l.inFlight))
+
+	generation := l.generation
+	var once sync.Once
+	return func(latency time.Duration) {
+		once.Do(func() { l.release(generation, latency) })
+	}, true
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of admitted requests that have not been released.
+func (l *AdaptiveLimiter) InFlight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight
+}
+
+func (l *AdaptiveLimiter) release(generation uint64, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if latency > l.config.TargetLatency {
+		if generation == l.generation {
+			l.limit = math.Max(float64(l.config.Min), l.limit*l.config.Backoff)
+			l.generation++
+		}
+	} else {
+		l.limit = math.Min(float64(l.config.Max), l.limit+1/l.limit)
+	}
+
+	concurrencyInFlight.Set(float64(l.inFlight))
+	concurrencyLimit.Set(l.limit)
+}
+
+// UseConcurrencyLimiter installs the concurrency limiter on app. It must run before
+// the routes are registered.
+func UseConcurrencyLimiter(app *fiber.App, cfg ConcurrencyLimitConfig) {
+	if !cfg.Enabled {
+		return
+	}
+	app.Use(ConcurrencyLimiter(NewAdaptiveLimiter(cfg), cfg.RetryAfter))
+}
+
+// ConcurrencyLimiter returns a middleware that admits requests through limiter, GET and
+// HEAD requests as reads and the others as writes, and sheds the rest with a 503
+// problem and a Retry-After header.
+func ConcurrencyLimiter(limiter *AdaptiveLimiter, retryAfter time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		priority := PriorityWrite
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			priority = PriorityRead
+		}
+
+		release, ok := limiter.Acquire(priority)
+		if !ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(retryAfter.Seconds())))
+			return problem.Write(c, fiber.StatusServiceUnavailable, "server overloaded, retry later")
+		}
+
+		start := time.Now()
+		defer func() { release(time.Since(start)) }()
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,184 @@
+package fiber_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimiterConfig() fiberpkg.ConcurrencyLimitConfig {
+	return fiberpkg.ConcurrencyLimitConfig{
+		Enabled:       true,
+		Initial:       10,
+		Min:           2,
+		Max:           20,
+		TargetLatency: 100 * time.Millisecond,
+		ReadReserve:   0.5,
+		Backoff:       0.5,
+		RetryAfter:    2 * time.Second,
+	}
+}
+
+func TestAdaptiveLimiter(t *testing.T) {
+	t.Run("should reserve part of the limit for reads", func(t *testing.T) {
+		// Arrange
+		limiter := fiberpkg.NewAdaptiveLimiter(newLimiterConfig())
+
+		// Act
+		var writes int
+		for range 10 {
+			if _, ok := limiter.Acquire(fiberpkg.PriorityWrite); ok {
+				writes++
+			}
+		}
+		_, readAdmitted := limiter.Acquire(fiberpkg.PriorityRead)
+
+		// Assert
+		assert.Equal(t, 5, writes)
+		assert.True(t, readAdmitted)
+		assert.Equal(t, 6, limiter.InFlight())
+	})
+
+	t.Run("should decrease the limit on slow requests and increase it on fast ones", func(t *testing.T) {
+		// Arrange
+		limiter := fiberpkg.NewAdaptiveLimiter(newLimiterConfig())
+
+		// Act
+		release, ok := limiter.Acquire(fiberpkg.PriorityRead)
+		require.True(t, ok)
+		release(time.Second)
+		decreased := limiter.Limit()
+
+		for range 20 {
+			release, ok = limiter.Acquire(fiberpkg.PriorityRead)
+			require.True(t, ok)
+			release(time.Millisecond)
+		}
+
+		// Assert
+		assert.Equal(t, 5, decreased)
+		assert.Greater(t, limiter.Limit(), decreased)
+		assert.Equal(t, 0, limiter.InFlight())
+	})
+
+	t.Run("should decrease the limit once for requests admitted before a decrease", func(t *testing.T) {
+		// Arrange
+		limiter := fiberpkg.NewAdaptiveLimiter(newLimiterConfig())
+		releases := make([]func(time.Duration), 0, 8)
+		for range 8 {
+			release, ok := limiter.Acquire(fiberpkg.PriorityRead)
+			require.True(t, ok)
+			releases = append(releases, release)
+		}
+
+		// Act
+		for _, release := range releases {
+			release(time.Second)
+		}
+		decreased := limiter.Limit()
+
+		release, ok := limiter.Acquire(fiberpkg.PriorityRead)
+		require.True(t, ok)
+		release(time.Second)
+
+		// Assert
+		assert.Equal(t, 5, decreased)
+		assert.Equal(t, 2, limiter.Limit())
+		assert.Equal(t, 0, limiter.InFlight())
+	})
+
+	t.Run("should never go below the minimum limit", func(t *testing.T) {
+		// Arrange
+		limiter := fiberpkg.NewAdaptiveLimiter(newLimiterConfig())
+
+		// Act
+		for range 10 {
+			release, ok := limiter.Acquire(fiberpkg.PriorityRead)
+			require.True(t, ok)
+			release(time.Second)
+		}
+
+		// Assert
+		assert.Equal(t, 2, limiter.Limit())
+	})
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	t.Run("should shed requests over the limit with a 503 and Retry-After", func(t *testing.T) {
+		// Arrange
+		cfg := newLimiterConfig()
+		cfg.Initial = 1
+		cfg.Min = 1
+		limiter := fiberpkg.NewAdaptiveLimiter(cfg)
+		release, ok := limiter.Acquire(fiberpkg.PriorityRead)
+		require.True(t, ok)
+		defer release(0)
+
+		app := fiber.New()
+		app.Use(fiberpkg.ConcurrencyLimiter(limiter, cfg.RetryAfter))
+		app.Get("/messages/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+		// Act
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/messages/1", nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("should release the slot once the request completed", func(t *testing.T) {
+		// Arrange
+		limiter := fiberpkg.NewAdaptiveLimiter(newLimiterConfig())
+		app := fiber.New()
+		app.Use(fiberpkg.ConcurrencyLimiter(limiter, time.Second))
+		app.Post("/messages", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+		// Act
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/messages", nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, 0, limiter.InFlight())
+	})
+}
+
+func TestNewConcurrencyLimitConfig(t *testing.T) {
+	t.Run("should read a valid default configuration", func(t *testing.T) {
+		// Act
+		cfg, err := fiberpkg.NewConcurrencyLimitConfig()
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 100, cfg.Initial)
+	})
+
+	for name, env := range map[string]map[string]string{
+		"an initial limit of zero":              {"FXF_CONCURRENCY_LIMIT_INITIAL": "0"},
+		"a minimum of zero":                     {"FXF_CONCURRENCY_LIMIT_MIN": "0"},
+		"a minimum above the maximum":           {"FXF_CONCURRENCY_LIMIT_MIN": "50", "FXF_CONCURRENCY_LIMIT_MAX": "20"},
+		"a read reserve of the whole limit":     {"FXF_CONCURRENCY_READ_RESERVE_PERCENT": "100"},
+		"a negative read reserve":               {"FXF_CONCURRENCY_READ_RESERVE_PERCENT": "-10"},
+		"a target latency that is not positive": {"FXF_CONCURRENCY_TARGET_LATENCY": "0s"},
+	} {
+		t.Run("should reject "+name, func(t *testing.T) {
+			// Arrange
+			for key, value := range env {
+				t.Setenv(key, value)
+			}
+
+			// Act
+			_, err := fiberpkg.NewConcurrencyLimitConfig()
+
+			// Assert
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package fiber
+
+import (
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/config"
+)
+
+// Config is the configuration of the Fiber listener.
+type Config struct {
+	Addr string
+	TLS  TLSConfig
+}
+
+// TLSConfig configures TLS on the listener. TLS is enabled when both CertFile and
+// KeyFile are set; client certificates are verified against ClientCAFile according
+// to ClientAuth ("none", "request" or "require").
+type TLSConfig struct {
+	CertFile       string
+	KeyFile        string
+	ClientCAFile   string
+	ClientAuth     string
+	MinVersion     string
+	CipherSuites   []string
+	ReloadInterval time.Duration
+}
+
+// Enabled reports whether the listener must serve TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" && c.KeyFile != ""
+}
+
+// NewConfig reads the listener configuration from FXF_HTTP_* and FXF_TLS_* environment variables.
+func NewConfig() Config {
+	return Config{
+		Addr: config.String("FXF_HTTP_ADDR", ":3000"),
+		TLS: TLSConfig{
+			CertFile:       config.String("FXF_TLS_CERT_FILE", ""),
+			KeyFile:        config.String("FXF_TLS_KEY_FILE", ""),
+			ClientCAFile:   config.String("FXF_TLS_CLIENT_CA_FILE", ""),
+			ClientAuth:     config.String("FXF_TLS_CLIENT_AUTH", ClientAuthNone),
+			MinVersion:     config.String("FXF_TLS_MIN_VERSION", "1.2"),
+			CipherSuites:   config.List("FXF_TLS_CIPHER_SUITES", nil),
+			ReloadInterval: config.Duration("FXF_TLS_RELOAD_INTERVAL", 30*time.Second),
+		},
+	}
+}
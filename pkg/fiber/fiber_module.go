@@ -2,7 +2,9 @@ package fiber
 
 import (
 	"context"
+	"crypto/tls"
 	"log/slog"
+	"net"
 
 	"github.com/ansrivas/fiberprometheus/v2"
 	"github.com/gofiber/contrib/otelfiber"
@@ -12,27 +14,79 @@ import (
 
 // Module exports the fiber server functionality.
 var Module = fx.Options(
+	fx.Provide(NewConfig),
+	fx.Provide(NewRateLimitConfig),
+	fx.Provide(NewConcurrencyLimitConfig),
+	fx.Provide(fx.Annotate(NewInMemoryRateLimitStore, fx.As(new(RateLimitStore)))),
 	fx.Provide(NewFiberServer),
-	fx.Invoke(func(lc fx.Lifecycle, app *fiber.App) {
-		lc.Append(fx.Hook{
-			OnStart: func(ctx context.Context) error {
-				slog.InfoContext(ctx, "Starting Fiber server on :3000")
-				// The server is started in a goroutine so that it doesn't
-				// block the application from starting.
+	fx.Invoke(UseConcurrencyLimiter),
+	fx.Invoke(UseRateLimiter),
+	fx.Invoke(RegisterServer),
+)
+
+// RegisterServer starts the Fiber server on cfg.Addr, with TLS when configured, and
+// stops it with the application. The TLS certificate is watched for changes while
+// the server runs.
+func RegisterServer(lc fx.Lifecycle, app *fiber.App, cfg Config) error {
+	var (
+		tlsConfig *tls.Config
+		reloader  *CertificateReloader
+	)
+	if cfg.TLS.Enabled() {
+		var err error
+		if reloader, err = NewCertificateReloader(cfg.TLS); err != nil {
+			return err
+		}
+		if tlsConfig, err = reloader.TLSConfig(); err != nil {
+			return err
+		}
+	}
+
+	stopWatch := func() {}
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", cfg.Addr)
+			if err != nil {
+				return err
+			}
+			if tlsConfig != nil {
+				listener = tls.NewListener(listener, tlsConfig)
+			}
+			if reloader != nil && cfg.TLS.ReloadInterval > 0 {
+				watchCtx, cancel := context.WithCancel(context.Background())
+				done := make(chan struct{})
 				go func() {
-					if err := app.Listen(":3000"); err != nil {
-						slog.ErrorContext(ctx, err.Error())
-					}
+					defer close(done)
+					reloader.Watch(watchCtx, cfg.TLS.ReloadInterval)
 				}()
-				return nil
-			},
-			OnStop: func(ctx context.Context) error {
-				slog.InfoContext(ctx, "Stopping Fiber server")
-				return app.ShutdownWithContext(ctx)
-			},
-		})
-	}),
-)
+				stopWatch = func() {
+					cancel()
+					<-done
+				}
+			}
+
+			slog.InfoContext(ctx, "Starting Fiber server",
+				slog.String("addr", cfg.Addr),
+				slog.Bool("tls", tlsConfig != nil),
+			)
+			// The server is started in a goroutine so that it doesn't
+			// block the application from starting.
+			go func() {
+				if err := app.Listener(listener); err != nil {
+					slog.ErrorContext(ctx, err.Error())
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			slog.InfoContext(ctx, "Stopping Fiber server")
+			stopWatch()
+			return app.ShutdownWithContext(ctx)
+		},
+	})
+
+	return nil
+}
 
 // NewFiberServer creates a new Fiber server instance.
 func NewFiberServer() *fiber.App {
@@ -42,10 +96,13 @@ func NewFiberServer() *fiber.App {
 	})
 
 	app.Use(otelfiber.Middleware())
+	app.Use(RequestID())
+	app.Use(ClientCertificate())
 
 	prometheus := fiberprometheus.NewWithDefaultRegistry("fxf")
 	prometheus.RegisterAt(app, "/metrics")
 	app.Use(prometheus.Middleware)
+	app.Use(Recover())
 
 	return app
 }
@@ -1,9 +1,13 @@
 package fiber_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/requestid"
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,3 +28,67 @@ func TestNewFiberServer(t *testing.T) {
 		assert.True(t, config.DisableStartupMessage)
 	})
 }
+
+func TestRequestID(t *testing.T) {
+	t.Run("should echo a valid inbound request ID and expose it in the context", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Use(fiberpkg.RequestID())
+		var seen string
+		app.Get("/ping", func(c *fiber.Ctx) error {
+			seen, _ = requestid.FromContext(c.UserContext())
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(requestid.Header, "client-id-1")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "client-id-1", resp.Header.Get(requestid.Header))
+		assert.Equal(t, "client-id-1", seen)
+	})
+
+	t.Run("should replace an invalid inbound request ID", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Use(fiberpkg.RequestID())
+		app.Get("/ping", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusNoContent)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(requestid.Header, "not valid!")
+
+		// Act
+		resp, err := app.Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		id := resp.Header.Get(requestid.Header)
+		assert.NotEqual(t, "not valid!", id)
+		assert.True(t, requestid.Valid(id))
+	})
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("should convert a panic into a problem response", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Use(fiberpkg.Recover())
+		app.Get("/panic", func(c *fiber.Ctx) error {
+			panic("boom")
+		})
+
+		// Act
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	})
+}
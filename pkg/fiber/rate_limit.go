@@ -0,0 +1,202 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/arielsrv/fxf/pkg/config"
+	"github.com/arielsrv/fxf/pkg/problem"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// APIKeyHeader is the header carrying the API key of a client.
+const APIKeyHeader = "X-API-Key"
+
+var rateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "fxf",
+	Subsystem: "rate_limit",
+	Name:      "rejections_total",
+	Help:      "Number of requests rejected by the rate limiter, by route.",
+}, []string{"route"})
+
+// KeyFunc returns the key a request is rate limited by.
+type KeyFunc func(c *fiber.Ctx) string
+
+// KeyByIP rate limits by client IP.
+func KeyByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// KeyByContext rate limits by a value of the request context, such as the
+// authenticated subject, falling back to the client IP.
+func KeyByContext(name string, lookup func(ctx context.Context) (string, bool)) KeyFunc {
+	return func(c *fiber.Ctx) string {
+		if value, ok := lookup(c.UserContext()); ok && value != "" {
+			return name + ":" + value
+		}
+		return KeyByIP(c)
+	}
+}
+
+// CostFunc returns the number of tokens a request takes.
+type CostFunc func(c *fiber.Ctx) int
+
+// CostByJSONArray weighs a request by the number of elements of the array field of
+// its JSON body, and at least one.
+func CostByJSONArray(field string) CostFunc {
+	return func(c *fiber.Ctx) int {
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(c.Body(), &body); err != nil {
+			return 1
+		}
+		var elements []json.RawMessage
+		if err := json.Unmarshal(body[field], &elements); err != nil {
+			return 1
+		}
+		return max(len(elements), 1)
+	}
+}
+
+// RateLimitRule limits the requests matching Method and Path. Path is a route pattern
+// such as "/messages/:id"; an empty Method matches every method. A request takes one
+// token, or the tokens returned by Cost up to the burst of Limit.
+type RateLimitRule struct {
+	Key    KeyFunc
+	Cost   CostFunc
+	Method string
+	Path   string
+	Limit  RateLimit
+}
+
+// RateLimitConfig is the configuration of the rate limiter.
+type RateLimitConfig struct {
+	Rules   []RateLimitRule
+	Enabled bool
+}
+
+// NewRateLimitConfig reads the rate limiter configuration from FXF_RATE_LIMIT_* environment
+// variables. POST /messages is limited per client IP, and POST /messages:batch per message
+// created; the auth module keys them by verified principal instead when FXF_RATE_LIMIT_KEY
+// asks for it. A rate that is not positive or a burst under one is rejected.
+func NewRateLimitConfig() (RateLimitConfig, error) {
+	cfg := RateLimitConfig{
+		Enabled: config.Bool("FXF_RATE_LIMIT_ENABLED", true),
+		Rules: []RateLimitRule{
+			{
+				Method: fiber.MethodPost,
+				Path:   "/messages",
+				Key:    KeyByIP,
+				Limit: RateLimit{
+					Rate:  float64(config.Int("FXF_RATE_LIMIT_MESSAGES_RATE", 10)),
+					Burst: config.Int("FXF_RATE_LIMIT_MESSAGES_BURST", 20),
+				},
+			},
+			{
+				Method: fiber.MethodPost,
+				Path:   "/messages:batch",
+				Key:    KeyByIP,
+				Cost:   CostByJSONArray("items"),
+				Limit: RateLimit{
+					Rate:  float64(config.Int("FXF_RATE_LIMIT_MESSAGES_BATCH_RATE", 10)),
+					Burst: config.Int("FXF_RATE_LIMIT_MESSAGES_BATCH_BURST", 100),
+				},
+			},
+		},
+	}
+	for _, rule := range cfg.Rules {
+		if err := rule.Limit.Validate(); err != nil {
+			return RateLimitConfig{}, fmt.Errorf("%s %s: %w", rule.Method, rule.Path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// UseRateLimiter installs the rate limiter on app. It must run before the routes are registered.
+func UseRateLimiter(app *fiber.App, cfg RateLimitConfig, store RateLimitStore) {
+	if !cfg.Enabled || len(cfg.Rules) == 0 {
+		return
+	}
+	app.Use(RateLimiter(store, cfg.Rules...))
+}
+
+// RateLimiter returns a middleware that applies the first matching rule to each request.
+// It sends the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers and
+// rejects exhausted clients with a 429 problem and a Retry-After header.
+// When the store fails the request is let through.
+func RateLimiter(store RateLimitStore, rules ...RateLimitRule) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rule, ok := matchRule(rules, c.Method(), c.Path())
+		if !ok {
+			return c.Next()
+		}
+
+		cost := 1
+		if rule.Cost != nil {
+			cost = min(max(rule.Cost(c), 1), rule.Limit.Burst)
+		}
+		key := rule.Method + " " + rule.Path + "|" + rule.Key(c)
+		result, err := store.Take(c.UserContext(), key, rule.Limit, cost)
+		if err != nil {
+			slog.ErrorContext(c.UserContext(), "rate limit store failed", slog.String("err", err.Error()))
+			return c.Next()
+		}
+
+		c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset.Seconds())))
+
+		if !result.Allowed {
+			rateLimitRejections.WithLabelValues(rule.Path).Inc()
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter.Seconds())))
+			return problem.Write(c, fiber.StatusTooManyRequests, "rate limit exceeded")
+		}
+
+		return c.Next()
+	}
+}
+
+func matchRule(rules []RateLimitRule, method, path string) (RateLimitRule, bool) {
+	for _, rule := range rules {
+		if rule.Method != "" && rule.Method != method {
+			continue
+		}
+		if matchPath(rule.Path, path) {
+			return rule, true
+		}
+	}
+	return RateLimitRule{}, false
+}
+
+// matchPath reports whether path matches pattern, where ":name" segments match any
+// single non-empty segment.
+func matchPath(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func ceilSeconds(seconds float64) int {
+	return int(math.Ceil(seconds))
+}
@@ -0,0 +1,131 @@
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket: Burst tokens at most, refilled at Rate tokens per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket.
+type RateLimitResult struct {
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until a token is available, when the request is rejected.
+	RetryAfter time.Duration
+	Limit      int
+	Remaining  int
+	Allowed    bool
+}
+
+// Validate rejects the limits that no request could ever pass, or whose delays cannot
+// be computed.
+func (l RateLimit) Validate() error {
+	switch {
+	case l.Rate <= 0:
+		return fmt.Errorf("rate limit rate must be positive, got %v", l.Rate)
+	case l.Burst < 1:
+		return fmt.Errorf("rate limit burst must be at least 1, got %d", l.Burst)
+	}
+	return nil
+}
+
+// RateLimitStore keeps the token buckets of the rate limiter.
+type RateLimitStore interface {
+	// Take takes cost tokens from the bucket identified by key.
+	Take(ctx context.Context, key string, limit RateLimit, cost int) (RateLimitResult, error)
+}
+
+// tokenBucket is the state of a single bucket.
+type tokenBucket struct {
+	last   time.Time
+	limit  RateLimit
+	tokens float64
+}
+
+// InMemoryRateLimitStore is an in-memory implementation of RateLimitStore. Buckets that
+// are full again are evicted periodically.
+type InMemoryRateLimitStore struct {
+	now       func() time.Time
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	mu        sync.Mutex
+}
+
+// sweepInterval is how often full buckets are evicted from the in-memory store.
+const sweepInterval = time.Minute
+
+// NewInMemoryRateLimitStore creates a new InMemoryRateLimitStore.
+func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
+	return NewInMemoryRateLimitStoreWithClock(time.Now)
+}
+
+// NewInMemoryRateLimitStoreWithClock creates a new InMemoryRateLimitStore using now as clock.
+func NewInMemoryRateLimitStoreWithClock(now func() time.Time) *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+// Take takes cost tokens from the bucket identified by key.
+func (s *InMemoryRateLimitStore) Take(
+	_ context.Context,
+	key string,
+	limit RateLimit,
+	cost int,
+) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = bucket
+	}
+	bucket.limit = limit
+
+	bucket.tokens = math.Min(float64(limit.Burst), bucket.tokens+now.Sub(bucket.last).Seconds()*limit.Rate)
+	bucket.last = now
+
+	result := RateLimitResult{Limit: limit.Burst}
+	if bucket.tokens >= float64(cost) {
+		bucket.tokens -= float64(cost)
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((float64(cost) - bucket.tokens) / limit.Rate)
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = secondsToDuration((float64(limit.Burst) - bucket.tokens) / limit.Rate)
+
+	return result, nil
+}
+
+// sweep evicts the buckets that have been refilled since their last use.
+func (s *InMemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.limit.Rate >= float64(bucket.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,184 @@
+package fiber_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRateLimitStore(t *testing.T) {
+	t.Run("should refill tokens over time", func(t *testing.T) {
+		// Arrange
+		now := time.Unix(0, 0)
+		store := fiberpkg.NewInMemoryRateLimitStoreWithClock(func() time.Time { return now })
+		limit := fiberpkg.RateLimit{Rate: 1, Burst: 2}
+		ctx := context.Background()
+
+		// Act
+		first, _ := store.Take(ctx, "k", limit, 1)
+		second, _ := store.Take(ctx, "k", limit, 1)
+		rejected, _ := store.Take(ctx, "k", limit, 1)
+		now = now.Add(time.Second)
+		refilled, err := store.Take(ctx, "k", limit, 1)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, first.Allowed)
+		assert.Equal(t, 1, first.Remaining)
+		assert.True(t, second.Allowed)
+		assert.False(t, rejected.Allowed)
+		assert.Equal(t, time.Second, rejected.RetryAfter)
+		assert.True(t, refilled.Allowed)
+	})
+
+	t.Run("should keep separate buckets per key", func(t *testing.T) {
+		// Arrange
+		store := fiberpkg.NewInMemoryRateLimitStore()
+		limit := fiberpkg.RateLimit{Rate: 1, Burst: 1}
+		ctx := context.Background()
+
+		// Act
+		a, _ := store.Take(ctx, "a", limit, 1)
+		b, _ := store.Take(ctx, "b", limit, 1)
+
+		// Assert
+		assert.True(t, a.Allowed)
+		assert.True(t, b.Allowed)
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	newApp := func() *fiber.App {
+		app := fiber.New()
+		app.Use(fiberpkg.RateLimiter(fiberpkg.NewInMemoryRateLimitStore(), fiberpkg.RateLimitRule{
+			Method: fiber.MethodPost,
+			Path:   "/messages",
+			Key:    func(c *fiber.Ctx) string { return c.Get("X-Client-ID") },
+			Limit:  fiberpkg.RateLimit{Rate: 1, Burst: 1},
+		}))
+		app.Post("/messages", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+		app.Get("/messages/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		return app
+	}
+
+	t.Run("should reject clients over their limit with a 429 problem", func(t *testing.T) {
+		// Arrange
+		app := newApp()
+		newRequest := func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/messages", nil)
+			req.Header.Set("X-Client-ID", "client-a")
+			return req
+		}
+
+		// Act
+		allowed, err := app.Test(newRequest())
+		require.NoError(t, err)
+		rejected, err := app.Test(newRequest())
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusCreated, allowed.StatusCode)
+		assert.Equal(t, "1", allowed.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, "0", allowed.Header.Get("RateLimit-Remaining"))
+
+		assert.Equal(t, fiber.StatusTooManyRequests, rejected.StatusCode)
+		assert.Equal(t, "1", rejected.Header.Get(fiber.HeaderRetryAfter))
+		assert.Equal(t, problem.ContentType, rejected.Header.Get(fiber.HeaderContentType))
+	})
+
+	t.Run("should not limit other keys and routes", func(t *testing.T) {
+		// Arrange
+		app := newApp()
+		first := httptest.NewRequest(http.MethodPost, "/messages", nil)
+		first.Header.Set("X-Client-ID", "client-a")
+		second := httptest.NewRequest(http.MethodPost, "/messages", nil)
+		second.Header.Set("X-Client-ID", "client-b")
+
+		// Act
+		_, err := app.Test(first)
+		require.NoError(t, err)
+		other, err := app.Test(second)
+		require.NoError(t, err)
+		read, err := app.Test(httptest.NewRequest(http.MethodGet, "/messages/1", nil))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusCreated, other.StatusCode)
+		assert.Equal(t, fiber.StatusOK, read.StatusCode)
+		assert.Empty(t, read.Header.Get("RateLimit-Limit"))
+	})
+	t.Run("should weigh a request by the items of its batch", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Use(fiberpkg.RateLimiter(fiberpkg.NewInMemoryRateLimitStore(), fiberpkg.RateLimitRule{
+			Method: fiber.MethodPost,
+			Path:   "/messages:batch",
+			Key:    fiberpkg.KeyByIP,
+			Cost:   fiberpkg.CostByJSONArray("items"),
+			Limit:  fiberpkg.RateLimit{Rate: 1, Burst: 3},
+		}))
+		app.Post("/messages\\:batch", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+		newRequest := func() *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/messages:batch", strings.NewReader(`{"items":[{},{}]}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return req
+		}
+
+		// Act
+		allowed, err := app.Test(newRequest())
+		require.NoError(t, err)
+		rejected, err := app.Test(newRequest())
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, fiber.StatusCreated, allowed.StatusCode)
+		assert.Equal(t, "1", allowed.Header.Get("RateLimit-Remaining"))
+		assert.Equal(t, fiber.StatusTooManyRequests, rejected.StatusCode)
+		assert.Equal(t, "1", rejected.Header.Get(fiber.HeaderRetryAfter))
+	})
+}
+
+func TestNewRateLimitConfig(t *testing.T) {
+	t.Run("should limit the single and the batch creation of messages", func(t *testing.T) {
+		// Act
+		cfg, err := fiberpkg.NewRateLimitConfig()
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, cfg.Rules, 2)
+		assert.Equal(t, "/messages", cfg.Rules[0].Path)
+		assert.Equal(t, "/messages:batch", cfg.Rules[1].Path)
+		assert.NotNil(t, cfg.Rules[1].Cost)
+	})
+
+	t.Run("should reject a rate that is not positive", func(t *testing.T) {
+		// Arrange
+		t.Setenv("FXF_RATE_LIMIT_MESSAGES_RATE", "0")
+
+		// Act
+		_, err := fiberpkg.NewRateLimitConfig()
+
+		// Assert
+		require.Error(t, err)
+	})
+
+	t.Run("should reject a burst under one", func(t *testing.T) {
+		// Arrange
+		t.Setenv("FXF_RATE_LIMIT_MESSAGES_BATCH_BURST", "-1")
+
+		// Act
+		_, err := fiberpkg.NewRateLimitConfig()
+
+		// Assert
+		require.Error(t, err)
+	})
+}
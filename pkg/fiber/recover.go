@@ -0,0 +1,38 @@
+package fiber
+
+import (
+	"reflect"
+	"runtime"
+
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/recovery"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Recover returns a middleware that converts panics raised by the next handlers into
+// a 500 problem details response, logging the stack and recording it on the span.
+func Recover() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) { //nolint:nonamedreturns // set by the deferred recover
+		defer func() {
+			if r := recover(); r != nil {
+				recovery.Report(c.UserContext(), r, c.Route().Path, handlerName(c.Route()))
+				err = problem.Write(c, fiber.StatusInternalServerError, "internal server error")
+			}
+		}()
+
+		return c.Next()
+	}
+}
+
+// handlerName returns the function name of the final handler of the route.
+func handlerName(route *fiber.Route) string {
+	if len(route.Handlers) == 0 {
+		return ""
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(route.Handlers[len(route.Handlers)-1]).Pointer())
+	if fn == nil {
+		return ""
+	}
+	return fn.Name()
+}
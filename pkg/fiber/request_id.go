@@ -0,0 +1,31 @@
+package fiber
+
+import (
+	"github.com/arielsrv/fxf/pkg/requestid"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDAttribute is the span attribute holding the request ID.
+const RequestIDAttribute = "http.request_id"
+
+// RequestID returns a middleware that accepts a valid inbound X-Request-ID or generates
+// a new one, stores it in the request context, echoes it in the response and attaches
+// it to the active span. It must be registered after the tracing middleware.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		ctx := requestid.NewContext(c.UserContext(), id)
+		c.SetUserContext(ctx)
+		c.Set(requestid.Header, id)
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String(RequestIDAttribute, id))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,214 @@
+package fiber
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Client authentication modes of TLSConfig.ClientAuth.
+const (
+	ClientAuthNone    = "none"
+	ClientAuthRequest = "request"
+	ClientAuthRequire = "require"
+)
+
+// CertificateReloader serves the certificate, key and client CA bundle of a TLSConfig
+// and reloads them when their modification time changes on disk.
+type CertificateReloader struct {
+	modTimes    map[string]time.Time
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	config      TLSConfig
+	mu          sync.RWMutex
+}
+
+// NewCertificateReloader loads the files of cfg.
+func NewCertificateReloader(cfg TLSConfig) (*CertificateReloader, error) {
+	r := &CertificateReloader{config: cfg, modTimes: make(map[string]time.Time)}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the files again if any of them changed, and reports whether it did.
+// On error the previous certificate and CA bundle are kept.
+func (r *CertificateReloader) Reload() (bool, error) {
+	modTimes, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	changed := r.certificate == nil || !equalModTimes(modTimes, r.modTimes)
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("load certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("read client CA bundle: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return false, errors.New("client CA bundle contains no certificate")
+		}
+	}
+
+	r.mu.Lock()
+	r.certificate = &certificate
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// Watch polls the files every interval and reloads them on change until ctx is done.
+func (r *CertificateReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to reload TLS certificate", slog.String("err", err.Error()))
+				continue
+			}
+			if reloaded {
+				slog.InfoContext(ctx, "reloaded TLS certificate", slog.String("cert", r.config.CertFile))
+			}
+		}
+	}
+}
+
+// TLSConfig builds the tls.Config of the listener. Each handshake uses the latest
+// certificate and client CA bundle loaded by the reloader.
+func (r *CertificateReloader) TLSConfig() (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(r.config.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := parseCipherSuites(r.config.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+	clientAuth, err := parseClientAuth(r.config.ClientAuth, r.config.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+		ClientAuth:   clientAuth,
+	}
+
+	return &tls.Config{
+		MinVersion: minVersion,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			config := base.Clone()
+			config.Certificates = []tls.Certificate{*r.certificate}
+			config.ClientCAs = r.clientCAs
+			return config, nil
+		},
+	}, nil
+}
+
+func (r *CertificateReloader) stat() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func equalModTimes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for file, modTime := range a {
+		if !b[file].Equal(modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS minimum version %q", version)
+	}
+}
+
+func parseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	byName := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		byName[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported or insecure cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func parseClientAuth(mode, clientCAFile string) (tls.ClientAuthType, error) {
+	switch mode {
+	case "", ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest, ClientAuthRequire:
+		if clientCAFile == "" {
+			return tls.NoClientCert, fmt.Errorf("client auth %q requires a client CA bundle", mode)
+		}
+		if mode == ClientAuthRequest {
+			return tls.VerifyClientCertIfGiven, nil
+		}
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unsupported client auth mode %q", mode)
+	}
+}
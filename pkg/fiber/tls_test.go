@@ -0,0 +1,202 @@
+package fiber_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	fiberpkg "github.com/arielsrv/fxf/pkg/fiber"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCertificate(t *testing.T, commonName string, parent *testCertificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+
+	issuer, signer := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestCertificateReloader(t *testing.T) {
+	t.Run("should reload the certificate when the files change", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		cfg := fiberpkg.TLSConfig{
+			CertFile: filepath.Join(dir, "tls.crt"),
+			KeyFile:  filepath.Join(dir, "tls.key"),
+		}
+		first := newTestCertificate(t, "first", nil)
+		writeFile(t, cfg.CertFile, first.certPEM, time.Now().Add(-time.Minute))
+		writeFile(t, cfg.KeyFile, first.keyPEM, time.Now().Add(-time.Minute))
+
+		reloader, err := fiberpkg.NewCertificateReloader(cfg)
+		require.NoError(t, err)
+
+		// Act
+		unchanged, err := reloader.Reload()
+		require.NoError(t, err)
+
+		second := newTestCertificate(t, "second", nil)
+		writeFile(t, cfg.CertFile, second.certPEM, time.Now())
+		writeFile(t, cfg.KeyFile, second.keyPEM, time.Now())
+		changed, err := reloader.Reload()
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, unchanged)
+		assert.True(t, changed)
+	})
+
+	t.Run("should reject an invalid configuration", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		ca := newTestCertificate(t, "ca", nil)
+		cfg := fiberpkg.TLSConfig{
+			CertFile:   filepath.Join(dir, "tls.crt"),
+			KeyFile:    filepath.Join(dir, "tls.key"),
+			ClientAuth: fiberpkg.ClientAuthRequire,
+		}
+		writeFile(t, cfg.CertFile, ca.certPEM, time.Now())
+		writeFile(t, cfg.KeyFile, ca.keyPEM, time.Now())
+
+		reloader, err := fiberpkg.NewCertificateReloader(cfg)
+		require.NoError(t, err)
+
+		// Act
+		_, err = reloader.TLSConfig()
+
+		// Assert
+		require.Error(t, err)
+	})
+}
+
+func TestMutualTLS(t *testing.T) {
+	t.Run("should expose the verified client identity", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		ca := newTestCertificate(t, "ca", nil)
+		server := newTestCertificate(t, "server", ca)
+		client := newTestCertificate(t, "client-1", ca)
+
+		cfg := fiberpkg.TLSConfig{
+			CertFile:     filepath.Join(dir, "tls.crt"),
+			KeyFile:      filepath.Join(dir, "tls.key"),
+			ClientCAFile: filepath.Join(dir, "ca.crt"),
+			ClientAuth:   fiberpkg.ClientAuthRequire,
+			MinVersion:   "1.3",
+		}
+		writeFile(t, cfg.CertFile, server.certPEM, time.Now())
+		writeFile(t, cfg.KeyFile, server.keyPEM, time.Now())
+		writeFile(t, cfg.ClientCAFile, ca.certPEM, time.Now())
+
+		reloader, err := fiberpkg.NewCertificateReloader(cfg)
+		require.NoError(t, err)
+		tlsConfig, err := reloader.TLSConfig()
+		require.NoError(t, err)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		app := fiber.New(fiber.Config{DisableStartupMessage: true})
+		app.Use(fiberpkg.ClientCertificate())
+		app.Get("/whoami", func(c *fiber.Ctx) error {
+			identity, ok := fiberpkg.ClientIdentityFromContext(c.UserContext())
+			if !ok {
+				return c.SendStatus(fiber.StatusUnauthorized)
+			}
+			return c.SendString(identity.Subject)
+		})
+		go func() { _ = app.Listener(tls.NewListener(listener, tlsConfig)) }()
+		t.Cleanup(func() { _ = app.Shutdown() })
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		clientCert, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+		require.NoError(t, err)
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{clientCert},
+			MinVersion:   tls.VersionTLS13,
+		}}}
+
+		// Act
+		resp, err := httpClient.Get("https://" + listener.Addr().String() + "/whoami")
+
+		// Assert
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "client-1", string(body))
+
+		anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:    roots,
+			MinVersion: tls.VersionTLS13,
+		}}}
+		resp, err = anonymous.Get("https://" + listener.Addr().String() + "/whoami")
+		if err == nil {
+			resp.Body.Close()
+		}
+		assert.Error(t, err)
+	})
+}
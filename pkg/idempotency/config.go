@@ -0,0 +1,42 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/config"
+)
+
+// Kinds of Store.
+const (
+	StoreMemory = "memory"
+	StoreFile   = "file"
+)
+
+// Config is the configuration of the idempotency middleware.
+type Config struct {
+	// Store is StoreMemory or StoreFile.
+	Store string
+	// Dir is the directory of the StoreFile records. It must not be shared by instances.
+	Dir string
+	// Methods are the HTTP methods whose requests honor the Idempotency-Key header.
+	Methods []string
+	// TTL is how long a response is replayed for its key.
+	TTL time.Duration
+	// LockTimeout is how long a request holds its key before another request with the
+	// same key may be processed, should the first one never complete.
+	LockTimeout time.Duration
+	// SweepInterval is how often the expired records are deleted. Zero disables it.
+	SweepInterval time.Duration
+}
+
+// NewConfig reads the idempotency configuration from FXF_IDEMPOTENCY_* environment variables.
+func NewConfig() Config {
+	return Config{
+		Store:         config.String("FXF_IDEMPOTENCY_STORE", StoreMemory),
+		Dir:           config.String("FXF_IDEMPOTENCY_DIR", "data/idempotency"),
+		Methods:       config.List("FXF_IDEMPOTENCY_METHODS", []string{"POST"}),
+		TTL:           config.Duration("FXF_IDEMPOTENCY_TTL", 24*time.Hour),
+		LockTimeout:   config.Duration("FXF_IDEMPOTENCY_LOCK_TIMEOUT", time.Minute),
+		SweepInterval: config.Duration("FXF_IDEMPOTENCY_SWEEP_INTERVAL", 10*time.Minute),
+	}
+}
@@ -0,0 +1,181 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/arielsrv/fxf/pkg/clock"
+)
+
+// FileStore is a Store that keeps each record in a JSON file of a directory, so
+// that the records survive restarts. The records are guarded by a lock of the
+// process: the directory must not be shared by several instances.
+type FileStore struct {
+	clock clock.Clock
+	dir   string
+	mu    sync.Mutex
+}
+
+// NewFileStore creates a new FileStore in dir, creating the directory if needed.
+func NewFileStore(dir string, clock clock.Clock) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create idempotency directory: %w", err)
+	}
+	return &FileStore{clock: clock, dir: dir}, nil
+}
+
+func (s *FileStore) Reserve(_ context.Context, key string, reservation *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := s.path(key)
+	temp, err := s.writeTemp(reservation)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(temp)
+
+	for {
+		err := os.Link(temp, path)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		existing, err := s.read(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			// Released or expired meanwhile: try again.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if s.clock.Now().Before(existing.ExpiresAt) {
+			return existing, nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to delete expired idempotency record: %w", err)
+		}
+	}
+}
+
+func (s *FileStore) Complete(_ context.Context, key, token string, response *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.holds(key, token); err != nil {
+		return err
+	}
+	temp, err := s.writeTemp(response)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(temp, s.path(key)); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to store idempotency record: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.holds(key, token); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (s *FileStore) DeleteExpired(context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	deleted := 0
+	for _, path := range paths {
+		record, err := s.read(path)
+		if err != nil || now.Before(record.ExpiresAt) {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// holds returns ErrReservationLost unless key is reserved with token.
+func (s *FileStore) holds(key, token string) error {
+	record, err := s.read(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrReservationLost
+	}
+	if err != nil {
+		return err
+	}
+	if record.Completed() || record.Token != token {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+// path returns the file of key. Keys are hashed because they hold arbitrary client
+// input.
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileStore) read(path string) (*Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	record := new(Record)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("failed to decode idempotency record %s: %w", filepath.Base(path), err)
+	}
+	return record, nil
+}
+
+// writeTemp writes record to a new temporary file of the directory and returns its
+// path. Temporary files do not end in .json, so they are never read as records.
+func (s *FileStore) writeTemp(record *Record) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".record-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to write idempotency record: %w", err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write idempotency record: %w", err)
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,70 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/clock"
+
+	"go.uber.org/fx"
+)
+
+// Module honors the Idempotency-Key header of the requests. It must be listed after
+// auth.Module and tenant.Module, whose principal and tenant scope the keys, and
+// before fiber.Module.
+var Module = fx.Options(
+	fx.Provide(NewConfig),
+	fx.Provide(NewStore),
+	fx.Invoke(UseMiddleware),
+)
+
+// NewStore creates the configured Store and deletes its expired records every sweep
+// interval while the application runs.
+func NewStore(lc fx.Lifecycle, clock clock.Clock, cfg Config) (Store, error) {
+	var store Store
+	switch cfg.Store {
+	case StoreMemory:
+		store = NewMemoryStore(clock)
+	case StoreFile:
+		fileStore, err := NewFileStore(cfg.Dir, clock)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	default:
+		return nil, fmt.Errorf("unknown idempotency store %q", cfg.Store)
+	}
+
+	if cfg.SweepInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		lc.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				go sweep(ctx, store, cfg.SweepInterval)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				cancel()
+				return nil
+			},
+		})
+	}
+	return store, nil
+}
+
+func sweep(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := store.DeleteExpired(ctx); err != nil {
+				slog.ErrorContext(ctx, "failed to delete expired idempotency records", slog.String("err", err.Error()))
+			}
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package idempotency_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/arielsrv/fxf/pkg/idempotency"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var cfg = idempotency.Config{Methods: []string{fiber.MethodPost}, TTL: time.Hour, LockTimeout: time.Minute}
+
+// newApp returns an app creating numbered resources, authenticated as the subject
+// of the X-Subject header, if any.
+func newApp(store idempotency.Store, handler fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{ProxyHeader: fiber.HeaderXForwardedFor})
+	app.Use(func(c *fiber.Ctx) error {
+		if subject := c.Get("X-Subject"); subject != "" {
+			c.SetUserContext(auth.NewContext(c.UserContext(), &auth.Principal{Subject: subject}))
+		}
+		return c.Next()
+	})
+	app.Use(idempotency.Middleware(store, clock.Fixed(now), cfg))
+	app.Post("/resources", handler)
+	return app
+}
+
+func counter() (fiber.Handler, *atomic.Int32) {
+	calls := new(atomic.Int32)
+	return func(c *fiber.Ctx) error {
+		n := calls.Add(1)
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"n": n})
+	}, calls
+}
+
+func post(t *testing.T, app *fiber.App, key, subject, body string) (*http.Response, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/resources", strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(idempotency.Header, key)
+	}
+	if subject != "" {
+		req.Header.Set("X-Subject", subject)
+	}
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(data)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("should replay the first response for a retry", func(t *testing.T) {
+		// Arrange
+		handler, calls := counter()
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), handler)
+		first, firstBody := post(t, app, "key-1", "", `{"text":"hello"}`)
+
+		// Act
+		retry, retryBody := post(t, app, "key-1", "", `{"text":"hello"}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusCreated, first.StatusCode)
+		assert.Equal(t, fiber.StatusCreated, retry.StatusCode)
+		assert.Equal(t, firstBody, retryBody)
+		assert.Equal(t, fiber.MIMEApplicationJSON, retry.Header.Get(fiber.HeaderContentType))
+		assert.Equal(t, "true", retry.Header.Get(idempotency.ReplayedHeader))
+		assert.Empty(t, first.Header.Get(idempotency.ReplayedHeader))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should process requests without a key every time", func(t *testing.T) {
+		// Arrange
+		handler, calls := counter()
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), handler)
+
+		// Act
+		post(t, app, "", "", `{}`)
+		post(t, app, "", "", `{}`)
+
+		// Assert
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should scope the keys by principal", func(t *testing.T) {
+		// Arrange
+		handler, calls := counter()
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), handler)
+
+		// Act
+		_, alice := post(t, app, "key-1", "alice", `{}`)
+		_, bob := post(t, app, "key-1", "bob", `{}`)
+
+		// Assert
+		assert.NotEqual(t, alice, bob)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should scope the keys of anonymous requests by client IP", func(t *testing.T) {
+		// Arrange
+		handler, calls := counter()
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), handler)
+		newRequest := func(ip string) *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/resources", strings.NewReader(`{}`))
+			req.Header.Set(idempotency.Header, "key-1")
+			req.Header.Set(fiber.HeaderXForwardedFor, ip)
+			return req
+		}
+
+		// Act
+		first, err := app.Test(newRequest("10.0.0.1"))
+		require.NoError(t, err)
+		other, err := app.Test(newRequest("10.0.0.2"))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Empty(t, first.Header.Get(idempotency.ReplayedHeader))
+		assert.Empty(t, other.Header.Get(idempotency.ReplayedHeader))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("should replay the Location and ETag headers of the first response", func(t *testing.T) {
+		// Arrange
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), func(c *fiber.Ctx) error {
+			c.Location("/resources/1")
+			c.Set(fiber.HeaderETag, `"1"`)
+			c.Set("X-Other", "not replayed")
+			return c.SendStatus(fiber.StatusCreated)
+		})
+		post(t, app, "key-1", "", `{}`)
+
+		// Act
+		retry, _ := post(t, app, "key-1", "", `{}`)
+
+		// Assert
+		assert.Equal(t, "true", retry.Header.Get(idempotency.ReplayedHeader))
+		assert.Equal(t, "/resources/1", retry.Header.Get(fiber.HeaderLocation))
+		assert.Equal(t, `"1"`, retry.Header.Get(fiber.HeaderETag))
+		assert.Empty(t, retry.Header.Get("X-Other"))
+	})
+
+	t.Run("should reject a key reused with a different payload", func(t *testing.T) {
+		// Arrange
+		handler, calls := counter()
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), handler)
+		post(t, app, "key-1", "", `{"text":"hello"}`)
+
+		// Act
+		resp, _ := post(t, app, "key-1", "", `{"text":"bye"}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("should answer conflict while the first request is processed", func(t *testing.T) {
+		// Arrange
+		started, release := make(chan struct{}), make(chan struct{})
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), func(c *fiber.Ctx) error {
+			close(started)
+			<-release
+			return c.SendStatus(fiber.StatusCreated)
+		})
+		done := make(chan *http.Response)
+		go func() {
+			resp, _ := post(t, app, "key-1", "", `{}`)
+			done <- resp
+		}()
+		<-started
+
+		// Act
+		resp, _ := post(t, app, "key-1", "", `{}`)
+		close(release)
+
+		// Assert
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+		assert.Equal(t, fiber.StatusCreated, (<-done).StatusCode)
+	})
+
+	t.Run("should not store server errors", func(t *testing.T) {
+		// Arrange
+		calls := new(atomic.Int32)
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), func(c *fiber.Ctx) error {
+			if calls.Add(1) == 1 {
+				return c.SendStatus(fiber.StatusServiceUnavailable)
+			}
+			return c.SendStatus(fiber.StatusCreated)
+		})
+		first, _ := post(t, app, "key-1", "", `{}`)
+
+		// Act
+		retry, _ := post(t, app, "key-1", "", `{}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusServiceUnavailable, first.StatusCode)
+		assert.Equal(t, fiber.StatusCreated, retry.StatusCode)
+	})
+
+	t.Run("should reject keys that are too long", func(t *testing.T) {
+		// Arrange
+		handler, _ := counter()
+		app := newApp(idempotency.NewMemoryStore(clock.Fixed(now)), handler)
+
+		// Act
+		resp, _ := post(t, app, strings.Repeat("k", idempotency.MaxKeyLength+1), "", `{}`)
+
+		// Assert
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T, c clock.Clock) idempotency.Store{
+		"memory": func(_ *testing.T, c clock.Clock) idempotency.Store {
+			return idempotency.NewMemoryStore(c)
+		},
+		"file": func(t *testing.T, c clock.Clock) idempotency.Store {
+			store, err := idempotency.NewFileStore(t.TempDir(), c)
+			require.NoError(t, err)
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name+" should reserve a key once", func(t *testing.T) {
+			// Arrange
+			store := newStore(t, clock.Fixed(now))
+			reservation := &idempotency.Record{Fingerprint: "a", ExpiresAt: now.Add(time.Minute)}
+
+			// Act
+			first, errFirst := store.Reserve(t.Context(), "key", reservation)
+			second, errSecond := store.Reserve(t.Context(), "key", &idempotency.Record{Fingerprint: "b"})
+
+			// Assert
+			require.NoError(t, errFirst)
+			require.NoError(t, errSecond)
+			assert.Nil(t, first)
+			require.NotNil(t, second)
+			assert.Equal(t, "a", second.Fingerprint)
+			assert.False(t, second.Completed())
+		})
+
+		t.Run(name+" should return the completed response", func(t *testing.T) {
+			// Arrange
+			store := newStore(t, clock.Fixed(now))
+			_, err := store.Reserve(t.Context(), "key", &idempotency.Record{
+				Token: "first", Fingerprint: "a", ExpiresAt: now.Add(time.Minute),
+			})
+			require.NoError(t, err)
+
+			// Act
+			err = store.Complete(t.Context(), "key", "first", &idempotency.Record{
+				Fingerprint: "a",
+				Status:      fiber.StatusCreated,
+				Headers:     map[string]string{fiber.HeaderLocation: "/resources/1"},
+				Body:        []byte(`{"id":1}`),
+				ExpiresAt:   now.Add(time.Hour),
+			})
+			require.NoError(t, err)
+			existing, err := store.Reserve(t.Context(), "key", &idempotency.Record{Fingerprint: "a"})
+
+			// Assert
+			require.NoError(t, err)
+			require.NotNil(t, existing)
+			assert.True(t, existing.Completed())
+			assert.JSONEq(t, `{"id":1}`, string(existing.Body))
+			assert.Equal(t, "/resources/1", existing.Headers[fiber.HeaderLocation])
+		})
+
+		t.Run(name+" should forget released and expired keys", func(t *testing.T) {
+			// Arrange
+			store := newStore(t, clock.Fixed(now))
+			_, err := store.Reserve(
+				t.Context(),
+				"released",
+				&idempotency.Record{Token: "first", ExpiresAt: now.Add(time.Minute)},
+			)
+			require.NoError(t, err)
+			_, err = store.Reserve(t.Context(), "expired", &idempotency.Record{ExpiresAt: now})
+			require.NoError(t, err)
+			_, err = store.Reserve(t.Context(), "live", &idempotency.Record{ExpiresAt: now.Add(time.Minute)})
+			require.NoError(t, err)
+
+			// Act
+			require.NoError(t, store.Release(t.Context(), "released", "first"))
+			released, errReleased := store.Reserve(
+				t.Context(),
+				"released",
+				&idempotency.Record{ExpiresAt: now.Add(time.Minute)},
+			)
+			deleted, errDeleted := store.DeleteExpired(t.Context())
+
+			// Assert
+			require.NoError(t, errReleased)
+			require.NoError(t, errDeleted)
+			assert.Nil(t, released)
+			assert.Equal(t, 1, deleted)
+		})
+
+		t.Run(name+" should refuse a request whose reservation was taken over", func(t *testing.T) {
+			// Arrange
+			manual := clock.NewManual(now)
+			store := newStore(t, manual)
+			_, err := store.Reserve(
+				t.Context(),
+				"key",
+				&idempotency.Record{Token: "first", ExpiresAt: now.Add(time.Minute)},
+			)
+			require.NoError(t, err)
+			manual.Advance(time.Minute)
+			_, err = store.Reserve(
+				t.Context(),
+				"key",
+				&idempotency.Record{Token: "second", ExpiresAt: now.Add(2 * time.Minute)},
+			)
+			require.NoError(t, err)
+
+			// Act
+			errComplete := store.Complete(t.Context(), "key", "first", &idempotency.Record{Status: fiber.StatusCreated})
+			errRelease := store.Release(t.Context(), "key", "first")
+
+			// Assert
+			require.ErrorIs(t, errComplete, idempotency.ErrReservationLost)
+			require.ErrorIs(t, errRelease, idempotency.ErrReservationLost)
+			existing, err := store.Reserve(t.Context(), "key", &idempotency.Record{Token: "third"})
+			require.NoError(t, err)
+			require.NotNil(t, existing)
+			assert.Equal(t, "second", existing.Token)
+			assert.False(t, existing.Completed())
+		})
+	}
+}
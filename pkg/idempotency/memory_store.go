@@ -0,0 +1,75 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+
+	"github.com/arielsrv/fxf/pkg/clock"
+)
+
+// MemoryStore is a Store that keeps the records in memory. Records are lost on
+// restart and are not shared between instances.
+type MemoryStore struct {
+	clock   clock.Clock
+	records map[string]Record
+	mu      sync.Mutex
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore(clock clock.Clock) *MemoryStore {
+	return &MemoryStore{clock: clock, records: make(map[string]Record)}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key string, reservation *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok && s.clock.Now().Before(existing.ExpiresAt) {
+		return &existing, nil
+	}
+	s.records[key] = *reservation
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key, token string, response *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.holds(key, token) {
+		return ErrReservationLost
+	}
+	s.records[key] = *response
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.holds(key, token) {
+		return ErrReservationLost
+	}
+	delete(s.records, key)
+	return nil
+}
+
+// holds reports whether key is reserved with token.
+func (s *MemoryStore) holds(key, token string) bool {
+	record, ok := s.records[key]
+	return ok && !record.Completed() && record.Token == token
+}
+
+func (s *MemoryStore) DeleteExpired(context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	deleted := 0
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
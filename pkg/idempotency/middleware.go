@@ -0,0 +1,164 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/clock"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// Header is the request header holding the idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on the responses replayed from the store.
+	ReplayedHeader = "Idempotent-Replayed"
+	// MaxKeyLength is the maximum length of an idempotency key.
+	MaxKeyLength = 255
+)
+
+// replayedHeaders are the response headers stored with a response and replayed with it.
+var replayedHeaders = []string{
+	fiber.HeaderLocation,
+	fiber.HeaderContentLocation,
+	fiber.HeaderETag,
+	fiber.HeaderLastModified,
+}
+
+var requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "fxf",
+	Subsystem: "idempotency",
+	Name:      "requests_total",
+	Help:      "Number of requests with an idempotency key, by outcome.",
+}, []string{"outcome"})
+
+// UseMiddleware installs the idempotency middleware on app. It must run after the
+// authentication and tenant middlewares and before the routes are registered.
+func UseMiddleware(app *fiber.App, store Store, clock clock.Clock, cfg Config) {
+	app.Use(Middleware(store, clock, cfg))
+}
+
+// Middleware returns a middleware that stores the first response to a request with
+// an Idempotency-Key header and replays it for the retries of the request, until it
+// expires. Keys are scoped by tenant and principal, or client IP for anonymous requests.
+//
+// A retry that arrives while the first request is processed gets a 409 problem, and
+// a key reused for a different request gets a 422 problem. Server errors are not
+// stored, so that the request can be retried.
+func Middleware(store Store, clock clock.Clock, cfg Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(Header)
+		if key == "" || !slices.Contains(cfg.Methods, c.Method()) {
+			return c.Next()
+		}
+		if len(key) > MaxKeyLength {
+			return problem.Write(c, fiber.StatusBadRequest, "Idempotency-Key is too long")
+		}
+
+		ctx := c.UserContext()
+		scoped := scope(c, key)
+		fingerprint := fingerprint(c)
+
+		token := uuid.NewString()
+		existing, err := store.Reserve(ctx, scoped, &Record{
+			Token:       token,
+			Fingerprint: fingerprint,
+			ExpiresAt:   clock.Now().Add(cfg.LockTimeout),
+		})
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				requestsTotal.WithLabelValues("mismatch").Inc()
+				return problem.Write(c, fiber.StatusUnprocessableEntity,
+					"Idempotency-Key was already used for a different request")
+			case !existing.Completed():
+				requestsTotal.WithLabelValues("in_progress").Inc()
+				return problem.Write(c, fiber.StatusConflict,
+					"a request with this Idempotency-Key is being processed")
+			}
+
+			requestsTotal.WithLabelValues("replayed").Inc()
+			c.Set(ReplayedHeader, "true")
+			if existing.ContentType != "" {
+				c.Set(fiber.HeaderContentType, existing.ContentType)
+			}
+			for name, value := range existing.Headers {
+				c.Set(name, value)
+			}
+			return c.Status(existing.Status).Send(existing.Body)
+		}
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError {
+			if releaseErr := store.Release(ctx, scoped, token); releaseErr != nil {
+				slog.ErrorContext(ctx, "failed to release idempotency key", slog.String("err", releaseErr.Error()))
+			}
+			requestsTotal.WithLabelValues("released").Inc()
+			return err
+		}
+
+		response := &Record{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Headers:     responseHeaders(c),
+			Body:        slices.Clone(c.Response().Body()),
+			ExpiresAt:   clock.Now().Add(cfg.TTL),
+		}
+		if err := store.Complete(ctx, scoped, token, response); err != nil {
+			slog.ErrorContext(ctx, "failed to store idempotent response", slog.String("err", err.Error()))
+		}
+		requestsTotal.WithLabelValues("stored").Inc()
+		return nil
+	}
+}
+
+// scope qualifies key with the tenant and the principal of the request, or the client
+// IP of anonymous requests, so that clients cannot read each other's responses by
+// guessing keys.
+func scope(c *fiber.Ctx, key string) string {
+	caller := "ip:" + c.IP()
+	if subject, ok := auth.SubjectFromContext(c.UserContext()); ok {
+		caller = "subject:" + subject
+	}
+	return strings.Join([]string{tenant.IDFromContext(c.UserContext()), caller, key}, "\x00")
+}
+
+// responseHeaders returns the replayed headers set on the response.
+func responseHeaders(c *fiber.Ctx) map[string]string {
+	var headers map[string]string
+	for _, name := range replayedHeaders {
+		if value := c.Response().Header.Peek(name); len(value) > 0 {
+			if headers == nil {
+				headers = make(map[string]string)
+			}
+			headers[name] = string(value)
+		}
+	}
+	return headers
+}
+
+// fingerprint hashes the method, path and body of the request.
+func fingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,48 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrReservationLost is returned when a key is completed or released by a request
+// that no longer holds its reservation, because it expired and another request
+// reserved the key.
+var ErrReservationLost = errors.New("idempotency key reservation lost")
+
+// Record is the response stored for an idempotency key. While the first request
+// with the key is processed, the record is a reservation without a status.
+type Record struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	// Fingerprint identifies the request the key was first used with.
+	Fingerprint string `json:"fingerprint"`
+	// Headers holds the response headers that are replayed, such as Location and ETag.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Token identifies the request holding a reservation.
+	Token       string `json:"token,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+	Status      int    `json:"status,omitempty"`
+}
+
+// Completed reports whether the record holds a response rather than a reservation.
+func (r *Record) Completed() bool {
+	return r.Status != 0
+}
+
+// Store keeps the records of the idempotency keys until they expire. Expired records
+// are treated as absent.
+type Store interface {
+	// Reserve stores reservation under key unless a record exists, in which case the
+	// existing record is returned instead and nothing is stored.
+	Reserve(ctx context.Context, key string, reservation *Record) (*Record, error)
+	// Complete replaces the reservation of key with the response, if the reservation
+	// has the given token. Otherwise it fails with ErrReservationLost.
+	Complete(ctx context.Context, key, token string, response *Record) error
+	// Release deletes the reservation of key, so that the request can be retried, if
+	// it has the given token. Otherwise it fails with ErrReservationLost.
+	Release(ctx context.Context, key, token string) error
+	// DeleteExpired deletes the expired records and returns how many it deleted.
+	DeleteExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/arielsrv/fxf/pkg/requestid"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextHandler is a slog.Handler that adds the request ID, the active trace and
+// span IDs and the attributes set with ContextWithAttrs found in the record context,
+// so that every log line of a request can be correlated with its response and its trace.
+type ContextHandler struct {
+	slog.Handler
+}
+
+type attrsKey struct{}
+
+// ContextWithAttrs returns a copy of ctx carrying attrs, which ContextHandler adds to
+// every record logged with that context.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsKey{}, merged)
+}
+
+// NewContextHandler wraps handler with a ContextHandler.
+func NewContextHandler(handler slog.Handler) slog.Handler {
+	return &ContextHandler{Handler: handler}
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := requestid.FromContext(ctx); ok {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if attrs, ok := ctx.Value(attrsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{Handler: h.Handler.WithGroup(name)}
+}
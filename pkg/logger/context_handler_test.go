@@ -0,0 +1,47 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/arielsrv/fxf/pkg/logger"
+	"github.com/arielsrv/fxf/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestContextHandler(t *testing.T) {
+	t.Run("should add request and trace IDs from the context", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		log := slog.New(logger.NewContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+		ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+		defer span.End()
+		ctx = requestid.NewContext(ctx, "req-1")
+		ctx = logger.ContextWithAttrs(ctx, slog.String("subject", "user-1"))
+
+		// Act
+		log.InfoContext(ctx, "hello")
+
+		// Assert
+		assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+		assert.Contains(t, buf.String(), `"trace_id":"`+span.SpanContext().TraceID().String()+`"`)
+		assert.Contains(t, buf.String(), `"component":"test"`)
+		assert.Contains(t, buf.String(), `"subject":"user-1"`)
+	})
+
+	t.Run("should leave records without context values untouched", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		log := slog.New(logger.NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+		// Act
+		log.InfoContext(context.Background(), "hello")
+
+		// Assert
+		assert.NotContains(t, buf.String(), "request_id")
+		assert.NotContains(t, buf.String(), "trace_id")
+	})
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	providedTypes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "fx",
+		Name:      "provided_types",
+		Help:      "Number of types provided to the fx container, by module.",
+	}, []string{"module"})
+
+	hookDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "fx",
+		Name:      "hook_duration_seconds",
+		Help:      "Runtime of the last execution of each fx lifecycle hook.",
+	}, []string{"kind", "function"})
+
+	startupDuration = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "fx",
+		Name:      "startup_duration_seconds",
+		Help:      "Time from logger initialization until the fx application started.",
+	})
+
+	shutdownDuration = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "fxf",
+		Subsystem: "fx",
+		Name:      "shutdown_duration_seconds",
+		Help:      "Time from the stop signal until the fx application stopped.",
+	})
+)
@@ -0,0 +1,16 @@
+package logger
+
+import "go.uber.org/fx/fxevent"
+
+type multiLogger []fxevent.Logger
+
+// Combine returns an fxevent.Logger that forwards every event to all the given loggers.
+func Combine(loggers ...fxevent.Logger) fxevent.Logger {
+	return multiLogger(loggers)
+}
+
+func (m multiLogger) LogEvent(e fxevent.Event) {
+	for _, l := range m {
+		l.LogEvent(e)
+	}
+}
@@ -1,8 +1,14 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
@@ -10,69 +16,130 @@ import (
 
 var Module = fx.WithLogger(func() fxevent.Logger {
 	handler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})
-	logger := slog.New(handler)
-	return New(logger)
+	logger := slog.New(NewContextHandler(handler))
+	slog.SetDefault(logger)
+	return Combine(New(logger), NewTracingLogger())
 })
 
+// DefaultSlowHookThreshold is the runtime above which a lifecycle hook is reported as slow.
+const DefaultSlowHookThreshold = 500 * time.Millisecond
+
+// defaultLevels lowers the per-constructor and per-hook events to Debug because
+// they are summarized in the startup and shutdown reports.
+var defaultLevels = map[string]slog.Level{
+	"Provided":         slog.LevelDebug,
+	"Decorated":        slog.LevelDebug,
+	"Supplied":         slog.LevelDebug,
+	"Invoking":         slog.LevelDebug,
+	"OnStartExecuting": slog.LevelDebug,
+	"OnStartExecuted":  slog.LevelDebug,
+	"OnStopExecuting":  slog.LevelDebug,
+	"OnStopExecuted":   slog.LevelDebug,
+}
+
+// Option configures a SlogLogger.
+type Option func(*SlogLogger)
+
+// WithEventLevel sets the level used for successful events of the given fxevent type,
+// e.g. WithEventLevel("Provided", slog.LevelInfo). Failures are always logged as errors.
+func WithEventLevel(event string, level slog.Level) Option {
+	return func(l *SlogLogger) {
+		l.levels[event] = level
+	}
+}
+
+// WithSlowHookThreshold sets the runtime above which a lifecycle hook is logged as a warning.
+func WithSlowHookThreshold(threshold time.Duration) Option {
+	return func(l *SlogLogger) {
+		l.slowHookThreshold = threshold
+	}
+}
+
+// HookRuntime is the measured runtime of a single OnStart or OnStop hook.
+type HookRuntime struct {
+	Function string        `json:"function"`
+	Caller   string        `json:"caller"`
+	Runtime  time.Duration `json:"runtime"`
+}
+
 // SlogLogger adapta slog a fxevent.Logger.
 type SlogLogger struct {
-	logger *slog.Logger
+	startedAt         time.Time
+	stoppingAt        time.Time
+	logger            *slog.Logger
+	levels            map[string]slog.Level
+	provided          map[string]int
+	startHooks        []HookRuntime
+	stopHooks         []HookRuntime
+	slowHookThreshold time.Duration
+	mu                sync.Mutex
 }
 
-func New(logger *slog.Logger) fxevent.Logger {
-	return &SlogLogger{logger: logger}
+func New(logger *slog.Logger, opts ...Option) fxevent.Logger {
+	l := &SlogLogger{
+		logger:            logger,
+		levels:            make(map[string]slog.Level, len(defaultLevels)),
+		provided:          make(map[string]int),
+		slowHookThreshold: DefaultSlowHookThreshold,
+		startedAt:         time.Now(),
+	}
+	for event, level := range defaultLevels {
+		l.levels[event] = level
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 func (l *SlogLogger) LogEvent(e fxevent.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
 	switch evt := e.(type) {
 	case *fxevent.OnStartExecuting:
-		l.logger.Info("OnStart hook executing", "callee", evt.FunctionName, "caller", evt.CallerName)
+		l.log(e, "OnStart hook executing", "callee", evt.FunctionName, "caller", evt.CallerName)
 	case *fxevent.OnStartExecuted:
 		if evt.Err != nil {
 			l.logger.Error("OnStart hook failed", "callee", evt.FunctionName, "caller", evt.CallerName, "err", evt.Err)
 		} else {
-			l.logger.Info(
-				"OnStart hook executed",
-				"callee",
-				evt.FunctionName,
-				"caller",
-				evt.CallerName,
-				"runtime",
-				evt.Runtime,
-			)
+			l.recordHook(e, "OnStart", &l.startHooks, evt.FunctionName, evt.CallerName, evt.Runtime)
 		}
 	case *fxevent.OnStopExecuting:
-		l.logger.Info("OnStop hook executing", "callee", evt.FunctionName, "caller", evt.CallerName)
+		l.log(e, "OnStop hook executing", "callee", evt.FunctionName, "caller", evt.CallerName)
 	case *fxevent.OnStopExecuted:
 		if evt.Err != nil {
 			l.logger.Error("OnStop hook failed", "callee", evt.FunctionName, "caller", evt.CallerName, "err", evt.Err)
 		} else {
-			l.logger.Info(
-				"OnStop hook executed",
-				"callee",
-				evt.FunctionName,
-				"caller",
-				evt.CallerName,
-				"runtime",
-				evt.Runtime,
-			)
+			l.recordHook(e, "OnStop", &l.stopHooks, evt.FunctionName, evt.CallerName, evt.Runtime)
 		}
 	case *fxevent.Supplied:
 		if evt.Err != nil {
 			l.logger.Error("Supplied failed", "type", evt.TypeName, "err", evt.Err)
 		} else {
-			l.logger.Info("Supplied", "type", evt.TypeName)
+			l.provided[moduleName(evt.ModuleName, "")]++
+			l.log(e, "Supplied", "type", evt.TypeName)
 		}
 	case *fxevent.Provided:
+		if evt.Err != nil {
+			l.logger.Error("Provide failed", "constructor", evt.ConstructorName, "err", evt.Err)
+			return
+		}
+		module := moduleName(evt.ModuleName, evt.ConstructorName)
 		for _, rtype := range evt.OutputTypeNames {
-			l.logger.Info("Provided", "constructor", evt.ConstructorName, "type", rtype)
+			l.provided[module]++
+			l.log(e, "Provided", "constructor", evt.ConstructorName, "type", rtype, "module", module)
 		}
 	case *fxevent.Decorated:
+		if evt.Err != nil {
+			l.logger.Error("Decorate failed", "decorator", evt.DecoratorName, "err", evt.Err)
+			return
+		}
 		for _, rtype := range evt.OutputTypeNames {
-			l.logger.Info("Decorated", "decorator", evt.DecoratorName, "type", rtype)
+			l.log(e, "Decorated", "decorator", evt.DecoratorName, "type", rtype)
 		}
 	case *fxevent.Invoking:
-		l.logger.Info("Invoking", "function", evt.FunctionName)
+		l.log(e, "Invoking", "function", evt.FunctionName)
 	case *fxevent.Invoked:
 		if evt.Err != nil {
 			l.logger.Error("Invocation failed", "function", evt.FunctionName, "err", evt.Err)
@@ -81,15 +148,16 @@ func (l *SlogLogger) LogEvent(e fxevent.Event) {
 		if evt.Err != nil {
 			l.logger.Error("Start failed", "err", evt.Err)
 		} else {
-			l.logger.Info("Started")
+			l.reportStartup(e)
 		}
 	case *fxevent.Stopping:
-		l.logger.Info("Stopping", "signal", evt.Signal.String())
+		l.stoppingAt = time.Now()
+		l.log(e, "Stopping", "signal", evt.Signal.String())
 	case *fxevent.Stopped:
 		if evt.Err != nil {
 			l.logger.Error("Stop failed", "err", evt.Err)
 		} else {
-			l.logger.Info("Stopped")
+			l.reportShutdown(e)
 		}
 	case *fxevent.RollingBack:
 		l.logger.Error("Rolling back", "startErr", evt.StartErr)
@@ -99,7 +167,104 @@ func (l *SlogLogger) LogEvent(e fxevent.Event) {
 		if evt.Err != nil {
 			l.logger.Error("Custom logger initialization failed", "err", evt.Err)
 		} else {
-			l.logger.Info("Logger initialized", "constructor", evt.ConstructorName)
+			l.log(e, "Logger initialized", "constructor", evt.ConstructorName)
 		}
 	}
 }
+
+// log writes msg at the level configured for the event type, Info by default.
+func (l *SlogLogger) log(e fxevent.Event, msg string, args ...any) {
+	level, ok := l.levels[eventName(e)]
+	if !ok {
+		level = slog.LevelInfo
+	}
+	l.logger.Log(context.Background(), level, msg, args...)
+}
+
+// recordHook keeps the hook runtime for the next report, exports it as a metric and
+// warns when it exceeds the slow hook threshold.
+func (l *SlogLogger) recordHook(
+	e fxevent.Event,
+	kind string,
+	hooks *[]HookRuntime,
+	function, caller string,
+	runtime time.Duration,
+) {
+	*hooks = append(*hooks, HookRuntime{Function: function, Caller: caller, Runtime: runtime})
+	hookDuration.WithLabelValues(kind, function).Set(runtime.Seconds())
+
+	if l.slowHookThreshold > 0 && runtime > l.slowHookThreshold {
+		l.logger.Warn(
+			kind+" hook is slow",
+			"callee", function,
+			"caller", caller,
+			"runtime", runtime,
+			"threshold", l.slowHookThreshold,
+		)
+		return
+	}
+
+	l.log(e, kind+" hook executed", "callee", function, "caller", caller, "runtime", runtime)
+}
+
+func (l *SlogLogger) reportStartup(e fxevent.Event) {
+	duration := time.Since(l.startedAt)
+	startupDuration.Set(duration.Seconds())
+	for module, count := range l.provided {
+		providedTypes.WithLabelValues(module).Set(float64(count))
+	}
+
+	l.log(e, "Started",
+		"duration", duration,
+		"provided", l.provided,
+		"hooks", sortedByRuntime(l.startHooks),
+	)
+}
+
+func (l *SlogLogger) reportShutdown(e fxevent.Event) {
+	var duration time.Duration
+	if !l.stoppingAt.IsZero() {
+		duration = time.Since(l.stoppingAt)
+		shutdownDuration.Set(duration.Seconds())
+	}
+
+	l.log(e, "Stopped",
+		"duration", duration,
+		"hooks", sortedByRuntime(l.stopHooks),
+	)
+}
+
+// sortedByRuntime returns a copy of hooks, slowest first.
+func sortedByRuntime(hooks []HookRuntime) []HookRuntime {
+	sorted := make([]HookRuntime, len(hooks))
+	copy(sorted, hooks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Runtime > sorted[j].Runtime
+	})
+	return sorted
+}
+
+// eventName returns the fxevent type name of e, e.g. "Provided".
+func eventName(e fxevent.Event) string {
+	t := reflect.TypeOf(e)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// moduleName returns the fx module name or, for anonymous fx.Options, the package
+// path of the constructor (e.g. "github.com/arielsrv/fxf/pkg/fiber").
+func moduleName(module, constructor string) string {
+	if module != "" {
+		return module
+	}
+	if constructor == "" {
+		return "root"
+	}
+	slash := strings.LastIndex(constructor, "/")
+	if dot := strings.Index(constructor[slash+1:], "."); dot >= 0 {
+		return constructor[:slash+1+dot]
+	}
+	return constructor
+}
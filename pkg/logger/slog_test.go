@@ -2,8 +2,11 @@ package logger_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"log/slog"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/arielsrv/fxf/pkg/logger"
 	"github.com/stretchr/testify/assert"
@@ -175,3 +178,69 @@ func TestSlogLogger_ImplementsInterface(t *testing.T) {
 		assert.NotNil(t, adapter)
 	})
 }
+
+func TestSlogLogger_StartupReport(t *testing.T) {
+	t.Run("should summarize provided types per module and sort hooks by runtime", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+		adapter := logger.New(slog.New(handler))
+
+		// Act
+		adapter.LogEvent(&fxevent.Provided{
+			ConstructorName: "github.com/arielsrv/fxf/pkg/fiber.NewFiberServer()",
+			OutputTypeNames: []string{"*fiber.App"},
+		})
+		adapter.LogEvent(&fxevent.OnStartExecuted{FunctionName: "fast", Runtime: time.Millisecond})
+		adapter.LogEvent(&fxevent.OnStartExecuted{FunctionName: "slow", Runtime: 10 * time.Millisecond})
+		adapter.LogEvent(&fxevent.Started{})
+
+		// Assert
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1)
+
+		var report struct {
+			Provided map[string]int `json:"provided"`
+			Msg      string         `json:"msg"`
+			Hooks    []struct {
+				Function string `json:"function"`
+			} `json:"hooks"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &report))
+		assert.Equal(t, "Started", report.Msg)
+		assert.Equal(t, 1, report.Provided["github.com/arielsrv/fxf/pkg/fiber"])
+		require.Len(t, report.Hooks, 2)
+		assert.Equal(t, "slow", report.Hooks[0].Function)
+		assert.Equal(t, "fast", report.Hooks[1].Function)
+	})
+
+	t.Run("should warn when a hook exceeds the slow hook threshold", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+		adapter := logger.New(slog.New(handler), logger.WithSlowHookThreshold(time.Millisecond))
+
+		// Act
+		adapter.LogEvent(&fxevent.OnStartExecuted{FunctionName: "slow", Runtime: time.Second})
+
+		// Assert
+		assert.Contains(t, buf.String(), `"level":"WARN"`)
+		assert.Contains(t, buf.String(), "OnStart hook is slow")
+	})
+
+	t.Run("should honour per event type levels", func(t *testing.T) {
+		// Arrange
+		var buf bytes.Buffer
+		handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+		quiet := logger.New(slog.New(handler))
+		verbose := logger.New(slog.New(handler), logger.WithEventLevel("Invoking", slog.LevelInfo))
+
+		// Act
+		quiet.LogEvent(&fxevent.Invoking{FunctionName: "quiet"})
+		verbose.LogEvent(&fxevent.Invoking{FunctionName: "verbose"})
+
+		// Assert
+		assert.NotContains(t, buf.String(), "quiet")
+		assert.Contains(t, buf.String(), "verbose")
+	})
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx/fxevent"
+)
+
+const tracerName = "github.com/arielsrv/fxf/pkg/logger"
+
+// TracingOption configures a TracingLogger.
+type TracingOption func(*TracingLogger)
+
+// WithTracerProvider sets the provider used to export the traces. By default the
+// global provider is looked up when a trace is exported, so that it can be
+// registered by an fx.Invoke after the logger has been created.
+func WithTracerProvider(provider trace.TracerProvider) TracingOption {
+	return func(l *TracingLogger) {
+		l.provider = provider
+	}
+}
+
+// spanRecord is a finished fx operation waiting to be exported as a child span.
+type spanRecord struct {
+	start time.Time
+	end   time.Time
+	err   error
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// TracingLogger is an fxevent.Logger that exports one trace for the application
+// startup and one for the shutdown, with a child span per constructor, invoke and
+// lifecycle hook.
+//
+// Spans are buffered and exported with their original timestamps once the phase
+// completes, because the tracer provider is usually registered by an fx.Invoke
+// that runs after most constructors.
+type TracingLogger struct {
+	startBegin time.Time
+	stopBegin  time.Time
+	provider   trace.TracerProvider
+	rollback   error
+	invoking   map[string]time.Time
+	startSpans []spanRecord
+	stopSpans  []spanRecord
+	mu         sync.Mutex
+}
+
+// NewTracingLogger creates a new TracingLogger. The startup trace begins now.
+func NewTracingLogger(opts ...TracingOption) fxevent.Logger {
+	l := &TracingLogger{
+		startBegin: time.Now(),
+		invoking:   make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+func (l *TracingLogger) LogEvent(e fxevent.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	switch evt := e.(type) {
+	case *fxevent.Run:
+		l.startSpans = append(l.startSpans, spanRecord{
+			name:  "fx." + evt.Kind,
+			start: now.Add(-evt.Runtime),
+			end:   now,
+			err:   evt.Err,
+			attrs: []attribute.KeyValue{
+				attribute.String("fx.function", evt.Name),
+				attribute.String("fx.module", evt.ModuleName),
+			},
+		})
+	case *fxevent.Invoking:
+		l.invoking[evt.FunctionName] = now
+	case *fxevent.Invoked:
+		start, ok := l.invoking[evt.FunctionName]
+		if !ok {
+			start = now
+		}
+		delete(l.invoking, evt.FunctionName)
+		l.startSpans = append(l.startSpans, spanRecord{
+			name:  "fx.invoke",
+			start: start,
+			end:   now,
+			err:   evt.Err,
+			attrs: []attribute.KeyValue{
+				attribute.String("fx.function", evt.FunctionName),
+				attribute.String("fx.module", evt.ModuleName),
+			},
+		})
+	case *fxevent.OnStartExecuted:
+		span := hookSpan("fx.onstart", evt.FunctionName, evt.CallerName, evt.Runtime, evt.Err, now)
+		l.startSpans = append(l.startSpans, span)
+	case *fxevent.OnStopExecuted:
+		span := hookSpan("fx.onstop", evt.FunctionName, evt.CallerName, evt.Runtime, evt.Err, now)
+		if l.stopBegin.IsZero() {
+			// OnStop hooks also run while rolling back a failed start.
+			l.startSpans = append(l.startSpans, span)
+			return
+		}
+		l.stopSpans = append(l.stopSpans, span)
+	case *fxevent.RollingBack:
+		l.rollback = evt.StartErr
+	case *fxevent.RolledBack:
+		if evt.Err != nil {
+			l.startSpans = append(l.startSpans, spanRecord{name: "fx.rollback", start: now, end: now, err: evt.Err})
+		}
+	case *fxevent.Started:
+		err := evt.Err
+		if err == nil {
+			err = l.rollback
+		}
+		l.export("fx.start", l.startBegin, now, err, l.startSpans)
+		l.startSpans = nil
+	case *fxevent.Stopping:
+		l.stopBegin = now
+	case *fxevent.Stopped:
+		begin := l.stopBegin
+		if begin.IsZero() {
+			begin = now
+		}
+		l.export("fx.stop", begin, now, evt.Err, l.stopSpans)
+		l.stopSpans = nil
+	}
+}
+
+// export emits a root span covering [begin, end] with one child span per record.
+func (l *TracingLogger) export(name string, begin, end time.Time, err error, records []spanRecord) {
+	provider := l.provider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	tracer := provider.Tracer(tracerName)
+
+	ctx, root := tracer.Start(context.Background(), name, trace.WithTimestamp(begin))
+	for _, record := range records {
+		_, span := tracer.Start(ctx, record.name,
+			trace.WithTimestamp(record.start),
+			trace.WithAttributes(record.attrs...),
+		)
+		recordError(span, record.err)
+		span.End(trace.WithTimestamp(record.end))
+	}
+	recordError(root, err)
+	root.End(trace.WithTimestamp(end))
+}
+
+func hookSpan(name, function, caller string, runtime time.Duration, err error, end time.Time) spanRecord {
+	return spanRecord{
+		name:  name,
+		start: end.Add(-runtime),
+		end:   end,
+		err:   err,
+		attrs: []attribute.KeyValue{
+			attribute.String("fx.function", function),
+			attribute.String("fx.caller", caller),
+		},
+	}
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
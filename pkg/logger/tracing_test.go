@@ -0,0 +1,85 @@
+package logger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arielsrv/fxf/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/fx/fxevent"
+)
+
+func TestTracingLogger_Startup(t *testing.T) {
+	t.Run("should export a startup trace with a child span per operation", func(t *testing.T) {
+		// Arrange
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		adapter := logger.NewTracingLogger(logger.WithTracerProvider(provider))
+
+		// Act
+		adapter.LogEvent(&fxevent.Run{Name: "NewFiberServer()", Kind: "provide", Runtime: time.Millisecond})
+		adapter.LogEvent(&fxevent.Invoking{FunctionName: "RegisterRoutes()"})
+		adapter.LogEvent(&fxevent.Invoked{FunctionName: "RegisterRoutes()"})
+		adapter.LogEvent(&fxevent.OnStartExecuted{FunctionName: "start", Runtime: time.Millisecond})
+		adapter.LogEvent(&fxevent.Started{})
+
+		// Assert
+		spans := recorder.Ended()
+		require.Len(t, spans, 4)
+
+		root := spans[3]
+		assert.Equal(t, "fx.start", root.Name())
+		assert.False(t, root.Parent().IsValid())
+		for i, name := range []string{"fx.provide", "fx.invoke", "fx.onstart"} {
+			assert.Equal(t, name, spans[i].Name())
+			assert.Equal(t, root.SpanContext().SpanID(), spans[i].Parent().SpanID())
+		}
+	})
+
+	t.Run("should record failures and rollbacks as span errors", func(t *testing.T) {
+		// Arrange
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		adapter := logger.NewTracingLogger(logger.WithTracerProvider(provider))
+
+		// Act
+		adapter.LogEvent(&fxevent.OnStartExecuted{FunctionName: "start", Err: assert.AnError})
+		adapter.LogEvent(&fxevent.RollingBack{StartErr: assert.AnError})
+		adapter.LogEvent(&fxevent.RolledBack{})
+		adapter.LogEvent(&fxevent.Started{})
+
+		// Assert
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, "fx.start", spans[1].Name())
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+	})
+}
+
+func TestTracingLogger_Shutdown(t *testing.T) {
+	t.Run("should export a shutdown trace with a child span per hook", func(t *testing.T) {
+		// Arrange
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		adapter := logger.NewTracingLogger(logger.WithTracerProvider(provider))
+		adapter.LogEvent(&fxevent.Started{})
+
+		// Act
+		adapter.LogEvent(&fxevent.Stopping{})
+		adapter.LogEvent(&fxevent.OnStopExecuted{FunctionName: "stop", Runtime: time.Millisecond})
+		adapter.LogEvent(&fxevent.Stopped{})
+
+		// Assert
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+		assert.Equal(t, "fx.onstop", spans[1].Name())
+		assert.Equal(t, "fx.stop", spans[2].Name())
+		assert.Equal(t, spans[2].SpanContext().SpanID(), spans[1].Parent().SpanID())
+		assert.NotEqual(t, spans[0].SpanContext().TraceID(), spans[2].SpanContext().TraceID())
+	})
+}
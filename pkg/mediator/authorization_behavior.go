@@ -0,0 +1,37 @@
+package mediator
+
+import (
+	"context"
+
+	"github.com/arielsrv/fxf/pkg/auth"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// AuthorizationBehavior rejects the requests implementing auth.ScopedRequest whose
+// principal lacks one of the required scopes, whatever the transport they came from.
+// Anonymous callers are only rejected when requireAuthentication is set. It returns
+// errors wrapping auth.ErrUnauthenticated or auth.ErrForbidden.
+type AuthorizationBehavior struct {
+	requireAuthentication bool
+}
+
+// NewAuthorizationBehavior creates a new AuthorizationBehavior.
+func NewAuthorizationBehavior(requireAuthentication bool) *AuthorizationBehavior {
+	return &AuthorizationBehavior{requireAuthentication: requireAuthentication}
+}
+
+// Handle authorizes the request and passes it to the next behavior.
+func (b *AuthorizationBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (interface{}, error) {
+	if _, ok := auth.FromContext(ctx); !ok && !b.requireAuthentication {
+		return next(ctx)
+	}
+	if err := auth.Authorize(ctx, request); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
@@ -1,9 +1,41 @@
 package mediator
 
 import (
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/config"
+
+	"github.com/mehdihadeli/go-mediatr"
 	"go.uber.org/fx"
 )
 
-// Module could be used to set up MediatR pipelines or other configurations.
-// For now, it's a placeholder to show where MediatR-specific setup would go.
-var Module = fx.Options(fx.Options())
+// Module registers the MediatR pipeline behaviors shared by every request.
+var Module = fx.Options(
+	fx.Provide(NewConfig),
+	fx.Invoke(RegisterBehaviors),
+)
+
+// Config is the configuration of the mediator pipeline.
+type Config struct {
+	// Authorization rejects the auth.ScopedRequest requests of anonymous callers. The
+	// scopes of an authenticated principal are checked either way.
+	Authorization bool
+}
+
+// NewConfig reads the pipeline configuration from FXF_AUTHZ_* environment variables.
+// Anonymous callers are rejected by default when the authentication is required.
+func NewConfig(authCfg auth.Config) Config {
+	return Config{
+		Authorization: config.Bool("FXF_AUTHZ_ENABLED", authCfg.Required),
+	}
+}
+
+// RegisterBehaviors registers the pipeline behaviors. They run in the order given,
+// the first one being the outermost.
+func RegisterBehaviors(cfg Config) error {
+	behaviors := []mediatr.PipelineBehavior{
+		NewRequestIDBehavior(),
+		NewRecoveryBehavior(),
+		NewAuthorizationBehavior(cfg.Authorization),
+	}
+	return mediatr.RegisterRequestPipelineBehaviors(behaviors...)
+}
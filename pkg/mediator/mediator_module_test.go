@@ -1,9 +1,14 @@
 package mediator_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/arielsrv/fxf/pkg/auth"
 	"github.com/arielsrv/fxf/pkg/mediator"
+	"github.com/arielsrv/fxf/pkg/recovery"
+	"github.com/arielsrv/fxf/pkg/requestid"
+	"github.com/mehdihadeli/go-mediatr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,21 +20,206 @@ func TestMediatorModule(t *testing.T) {
 
 		// Assert
 		require.NotNil(t, module)
-		// The module is an empty fx.Options, which is valid
-		assert.NotNil(t, module)
 	})
 }
 
-func TestMediatorModuleStructure(t *testing.T) {
-	t.Run("should have empty module options", func(t *testing.T) {
-		// Arrange & Act
-		module := mediator.Module
+func TestNewConfig(t *testing.T) {
+	t.Run("should reject anonymous callers when the authentication is required", func(t *testing.T) {
+		// Act
+		required := mediator.NewConfig(auth.Config{Required: true})
+		optional := mediator.NewConfig(auth.Config{})
 
 		// Assert
-		// The module is currently empty (placeholder)
-		// This test ensures the module can be created without issues
-		require.NotNil(t, module)
-		// Even empty fx.Options is not nil
-		assert.NotNil(t, module)
+		assert.True(t, required.Authorization)
+		assert.False(t, optional.Authorization)
+	})
+
+	t.Run("should let the environment override the default", func(t *testing.T) {
+		// Arrange
+		t.Setenv("FXF_AUTHZ_ENABLED", "false")
+
+		// Act
+		cfg := mediator.NewConfig(auth.Config{Required: true})
+
+		// Assert
+		assert.False(t, cfg.Authorization)
+	})
+}
+
+func TestRegisterBehaviors(t *testing.T) {
+	t.Run("should register the pipeline behaviors once", func(t *testing.T) {
+		// Arrange
+		mediatr.ClearPipelineBehaviors()
+		t.Cleanup(mediatr.ClearPipelineBehaviors)
+
+		// Act
+		err := mediator.RegisterBehaviors(mediator.Config{Authorization: true})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Error(t, mediator.RegisterBehaviors(mediator.Config{}))
+	})
+}
+
+type scopedRequest struct{}
+
+func (scopedRequest) RequiredScopes() []string {
+	return []string{"messages:write"}
+}
+
+func TestAuthorizationBehavior(t *testing.T) {
+	next := func(context.Context) (interface{}, error) {
+		return "ok", nil
+	}
+
+	t.Run("should pass requests of a principal holding the required scopes", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewAuthorizationBehavior(true)
+		ctx := auth.NewContext(
+			context.Background(),
+			&auth.Principal{Subject: "user-1", Scopes: []string{"messages:write"}},
+		)
+
+		// Act
+		result, err := behavior.Handle(ctx, scopedRequest{}, next)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result)
+	})
+
+	t.Run("should deny a principal missing a required scope", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewAuthorizationBehavior(true)
+		ctx := auth.NewContext(
+			context.Background(),
+			&auth.Principal{Subject: "user-1", Scopes: []string{"messages:read"}},
+		)
+
+		// Act
+		result, err := behavior.Handle(ctx, scopedRequest{}, next)
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrForbidden)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should deny anonymous callers", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewAuthorizationBehavior(true)
+
+		// Act
+		_, err := behavior.Handle(context.Background(), scopedRequest{}, next)
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrUnauthenticated)
+	})
+
+	t.Run("should pass anonymous callers when the authentication is optional", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewAuthorizationBehavior(false)
+
+		// Act
+		result, err := behavior.Handle(context.Background(), scopedRequest{}, next)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result)
+	})
+
+	t.Run("should deny a principal missing a required scope when the authentication is optional", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewAuthorizationBehavior(false)
+		ctx := auth.NewContext(
+			context.Background(),
+			&auth.Principal{Subject: "user-1", Scopes: []string{"messages:read"}},
+		)
+
+		// Act
+		_, err := behavior.Handle(ctx, scopedRequest{}, next)
+
+		// Assert
+		require.ErrorIs(t, err, auth.ErrForbidden)
+	})
+
+	t.Run("should pass requests without declared scopes", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewAuthorizationBehavior(true)
+
+		// Act
+		result, err := behavior.Handle(context.Background(), struct{}{}, next)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result)
+	})
+}
+
+func TestRequestIDBehavior(t *testing.T) {
+	t.Run("should keep the request ID from the context", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewRequestIDBehavior()
+		ctx := requestid.NewContext(context.Background(), "req-1")
+
+		// Act
+		result, err := behavior.Handle(ctx, struct{}{}, func(ctx context.Context) (interface{}, error) {
+			id, _ := requestid.FromContext(ctx)
+			return id, nil
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "req-1", result)
+	})
+
+	t.Run("should generate a request ID when missing", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewRequestIDBehavior()
+
+		// Act
+		result, err := behavior.Handle(
+			context.Background(),
+			struct{}{},
+			func(ctx context.Context) (interface{}, error) {
+				id, ok := requestid.FromContext(ctx)
+				return ok && requestid.Valid(id), nil
+			},
+		)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, true, result)
+	})
+}
+
+func TestRecoveryBehavior(t *testing.T) {
+	t.Run("should convert a handler panic into an error", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewRecoveryBehavior()
+
+		// Act
+		result, err := behavior.Handle(context.Background(), struct{}{}, func(context.Context) (interface{}, error) {
+			panic("boom")
+		})
+
+		// Assert
+		var panicErr *recovery.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.Nil(t, result)
+	})
+
+	t.Run("should pass through the handler result", func(t *testing.T) {
+		// Arrange
+		behavior := mediator.NewRecoveryBehavior()
+
+		// Act
+		result, err := behavior.Handle(context.Background(), struct{}{}, func(context.Context) (interface{}, error) {
+			return "ok", nil
+		})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "ok", result)
 	})
 }
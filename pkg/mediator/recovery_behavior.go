@@ -0,0 +1,36 @@
+package mediator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arielsrv/fxf/pkg/recovery"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// RecoveryBehavior converts a panic raised by a request handler into a
+// *recovery.PanicError, so that every transport can answer with a 500 instead of
+// crashing the worker.
+type RecoveryBehavior struct{}
+
+// NewRecoveryBehavior creates a new RecoveryBehavior.
+func NewRecoveryBehavior() *RecoveryBehavior {
+	return &RecoveryBehavior{}
+}
+
+// Handle calls the next behavior and recovers from its panics.
+func (b *RecoveryBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (response interface{}, err error) { //nolint:nonamedreturns // set by the deferred recover
+	defer func() {
+		if r := recover(); r != nil {
+			response = nil
+			err = recovery.Report(ctx, r, "mediator", fmt.Sprintf("%T", request))
+		}
+	}()
+
+	return next(ctx)
+}
@@ -0,0 +1,35 @@
+package mediator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/arielsrv/fxf/pkg/requestid"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// RequestIDBehavior makes sure every request sent through the mediator carries a
+// request ID, so that handlers and the domain events they publish with the same
+// context can be correlated. Transports that do not set one get a generated ID.
+type RequestIDBehavior struct{}
+
+// NewRequestIDBehavior creates a new RequestIDBehavior.
+func NewRequestIDBehavior() *RequestIDBehavior {
+	return &RequestIDBehavior{}
+}
+
+// Handle ensures the request ID and passes the request to the next behavior.
+func (b *RequestIDBehavior) Handle(
+	ctx context.Context,
+	request interface{},
+	next mediatr.RequestHandlerFunc,
+) (interface{}, error) {
+	if _, ok := requestid.FromContext(ctx); !ok {
+		ctx = requestid.NewContext(ctx, requestid.New())
+	}
+
+	slog.DebugContext(ctx, "dispatching request", slog.String("request", fmt.Sprintf("%T", request)))
+	return next(ctx)
+}
@@ -0,0 +1,48 @@
+package problem
+
+import (
+	"net/http"
+
+	"github.com/arielsrv/fxf/pkg/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContentType is the media type of RFC 9457 problem details.
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details response.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Status    int    `json:"status"`
+}
+
+// New creates a Problem for the HTTP status, titled with its standard status text.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Write sends a problem details response for the HTTP status.
+func Write(c *fiber.Ctx, status int, detail string) error {
+	return WriteProblem(c, New(status, detail))
+}
+
+// WriteProblem sends p as the response, filling in the instance and the request ID.
+func WriteProblem(c *fiber.Ctx, p *Problem) error {
+	if p.Instance == "" {
+		p.Instance = c.OriginalURL()
+	}
+	if id, ok := requestid.FromContext(c.UserContext()); ok {
+		p.RequestID = id
+	}
+	return c.Status(p.Status).JSON(p, ContentType)
+}
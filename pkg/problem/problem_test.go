@@ -0,0 +1,41 @@
+package problem_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/requestid"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("should write a problem details response", func(t *testing.T) {
+		// Arrange
+		app := fiber.New()
+		app.Get("/boom", func(c *fiber.Ctx) error {
+			c.SetUserContext(requestid.NewContext(c.UserContext(), "req-1"))
+			return problem.Write(c, fiber.StatusConflict, "version mismatch")
+		})
+
+		// Act
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+
+		var body problem.Problem
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "Conflict", body.Title)
+		assert.Equal(t, fiber.StatusConflict, body.Status)
+		assert.Equal(t, "version mismatch", body.Detail)
+		assert.Equal(t, "/boom", body.Instance)
+		assert.Equal(t, "req-1", body.RequestID)
+	})
+}
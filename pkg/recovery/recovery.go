@@ -0,0 +1,52 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var panicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "fxf",
+	Name:      "panics_total",
+	Help:      "Number of recovered panics, by route and handler.",
+}, []string{"route", "handler"})
+
+// PanicError is the error a recovered panic is converted to.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Report converts a recovered panic value into a *PanicError: it logs the stack,
+// records the exception on the current span and increments the panic counter.
+// It must be called from the deferred function that recovered.
+func Report(ctx context.Context, recovered any, route, handler string) *PanicError {
+	err := &PanicError{Value: recovered, Stack: debug.Stack()}
+
+	slog.ErrorContext(ctx, "panic recovered",
+		slog.String("route", route),
+		slog.String("handler", handler),
+		slog.Any("panic", recovered),
+		slog.String("stack", string(err.Stack)),
+	)
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithAttributes(semconv.ExceptionStacktrace(string(err.Stack))))
+	span.SetStatus(codes.Error, err.Error())
+
+	panicsTotal.WithLabelValues(route, handler).Inc()
+
+	return err
+}
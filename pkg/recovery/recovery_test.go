@@ -0,0 +1,43 @@
+package recovery_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arielsrv/fxf/pkg/recovery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestReport(t *testing.T) {
+	t.Run("should convert the panic and record it on the span", func(t *testing.T) {
+		// Arrange
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		ctx, span := provider.Tracer("test").Start(context.Background(), "op")
+
+		// Act
+		var err *recovery.PanicError
+		func() {
+			defer func() {
+				err = recovery.Report(ctx, recover(), "/messages", "handler")
+			}()
+			panic("boom")
+		}()
+		span.End()
+
+		// Assert
+		require.NotNil(t, err)
+		assert.Equal(t, "panic: boom", err.Error())
+		assert.NotEmpty(t, err.Stack)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		require.Len(t, spans[0].Events(), 1)
+		assert.Equal(t, "exception", spans[0].Events()[0].Name)
+	})
+}
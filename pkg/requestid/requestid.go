@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header used to receive and echo the request ID.
+const Header = "X-Request-ID"
+
+// MaxLength is the maximum length accepted for an inbound request ID.
+const MaxLength = 128
+
+type contextKey struct{}
+
+// New generates a new request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Valid reports whether an inbound request ID can be trusted: it must be non-empty,
+// at most MaxLength characters and only contain letters, digits, '-', '_', '.' or ':'.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package requestid_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/arielsrv/fxf/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContext(t *testing.T) {
+	t.Run("should carry the request ID in the context", func(t *testing.T) {
+		// Arrange
+		ctx := requestid.NewContext(context.Background(), "abc-123")
+
+		// Act
+		id, ok := requestid.FromContext(ctx)
+
+		// Assert
+		require.True(t, ok)
+		assert.Equal(t, "abc-123", id)
+	})
+
+	t.Run("should report a missing request ID", func(t *testing.T) {
+		// Act
+		_, ok := requestid.FromContext(context.Background())
+
+		// Assert
+		assert.False(t, ok)
+	})
+}
+
+func TestValid(t *testing.T) {
+	t.Run("should accept generated and well-formed IDs", func(t *testing.T) {
+		assert.True(t, requestid.Valid(requestid.New()))
+		assert.True(t, requestid.Valid("client.trace:42_a"))
+	})
+
+	t.Run("should reject empty, oversized and unsafe IDs", func(t *testing.T) {
+		assert.False(t, requestid.Valid(""))
+		assert.False(t, requestid.Valid(strings.Repeat("a", requestid.MaxLength+1)))
+		assert.False(t, requestid.Valid("id with spaces"))
+		assert.False(t, requestid.Valid("id\nX-Injected: 1"))
+	})
+}
@@ -49,10 +49,23 @@ func RegisterTracer(lc fx.Lifecycle) {
 	otel.SetTracerProvider(tracerProvider)
 	otel.SetTextMapPropagator(propagation.TraceContext{})
 
+	// The provider is only flushed here: it is shut down by Shutdown once the fx app
+	// has stopped, so that the spans of the shutdown itself are exported too.
 	lc.Append(fx.Hook{
 		OnStop: func(ctx context.Context) error {
-			slog.InfoContext(ctx, "shutting down tracer provider")
-			return tracerProvider.Shutdown(ctx)
+			slog.InfoContext(ctx, "flushing tracer provider")
+			return tracerProvider.ForceFlush(ctx)
 		},
 	})
 }
+
+// Shutdown shuts down the global tracer provider registered by RegisterTracer, if any.
+func Shutdown(ctx context.Context) error {
+	tracerProvider, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if !ok {
+		return nil
+	}
+
+	slog.InfoContext(ctx, "shutting down tracer provider")
+	return tracerProvider.Shutdown(ctx)
+}
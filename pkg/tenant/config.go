@@ -0,0 +1,30 @@
+package tenant
+
+import "github.com/arielsrv/fxf/pkg/config"
+
+// Sources a tenant can be resolved from.
+const (
+	SourceClaim     = "claim"
+	SourceHeader    = "header"
+	SourceSubdomain = "subdomain"
+)
+
+// Config is the configuration of the tenant resolver.
+type Config struct {
+	// BaseDomain is the domain under which tenants get a subdomain, e.g. "example.com"
+	// for "acme.example.com".
+	BaseDomain string
+	// Sources are tried in order until one yields a tenant.
+	Sources []string
+	// Required rejects the requests that resolve to no tenant instead of using DefaultID.
+	Required bool
+}
+
+// NewConfig reads the tenant resolver configuration from FXF_TENANT_* environment variables.
+func NewConfig() Config {
+	return Config{
+		Sources:    config.List("FXF_TENANT_SOURCES", []string{SourceClaim, SourceHeader}),
+		BaseDomain: config.String("FXF_TENANT_BASE_DOMAIN", ""),
+		Required:   config.Bool("FXF_TENANT_REQUIRED", false),
+	}
+}
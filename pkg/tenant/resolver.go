@@ -0,0 +1,134 @@
+package tenant
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/logger"
+	"github.com/arielsrv/fxf/pkg/problem"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute is the span attribute and log key holding the tenant ID.
+const Attribute = "tenant.id"
+
+// unverifiedLabel is the metric label of the tenants not verified against a claim,
+// which the clients could otherwise use to create unbounded series.
+const unverifiedLabel = "unverified"
+
+var requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "fxf",
+	Subsystem: "tenant",
+	Name:      "requests_total",
+	Help:      "Number of HTTP requests, by tenant and status code.",
+}, []string{"tenant", "status"})
+
+// UseResolver installs the tenant resolver on app. It must run after the
+// authentication middleware and before the routes are registered.
+func UseResolver(app *fiber.App, cfg Config) {
+	app.Use(Resolver(cfg))
+}
+
+// Resolver returns a middleware that resolves the tenant of each request from the
+// configured sources, stores it in the request context and adds it to the logs, the
+// active span and the request metrics.
+//
+// A header or subdomain may only name DefaultID or the tenant claimed by the
+// authenticated principal: anonymous requests naming another tenant get a 401
+// problem and principals claiming another or no tenant a 403 problem. Malformed
+// tenants get a 400 problem.
+func Resolver(cfg Config) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		id, claimed, rejection := resolve(c, cfg)
+		if rejection != nil {
+			return problem.WriteProblem(c, rejection)
+		}
+		if id == "" {
+			if cfg.Required {
+				return problem.Write(c, fiber.StatusBadRequest, "tenant required")
+			}
+			id = DefaultID
+		}
+
+		ctx = NewContext(ctx, id)
+		ctx = logger.ContextWithAttrs(ctx, slog.String("tenant_id", id))
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String(Attribute, id))
+		c.SetUserContext(ctx)
+
+		label := id
+		if id != claimed && id != DefaultID {
+			label = unverifiedLabel
+		}
+		err := c.Next()
+		requestsTotal.WithLabelValues(label, strconv.Itoa(c.Response().StatusCode())).Inc()
+		return err
+	}
+}
+
+// resolve returns the first tenant found in the sources, or "" when there is none,
+// and the tenant claimed by the principal.
+func resolve(c *fiber.Ctx, cfg Config) (string, string, *problem.Problem) {
+	var claimed string
+	principal, authenticated := auth.FromContext(c.UserContext())
+	if authenticated {
+		claimed = principal.TenantID
+	}
+
+	var resolved string
+	for _, source := range cfg.Sources {
+		var candidate string
+		switch source {
+		case SourceClaim:
+			candidate = claimed
+		case SourceHeader:
+			candidate = c.Get(Header)
+		case SourceSubdomain:
+			candidate = subdomain(c.Hostname(), cfg.BaseDomain)
+		}
+		if candidate == "" {
+			continue
+		}
+
+		if !Valid(candidate) {
+			return "", claimed, problem.New(fiber.StatusBadRequest, "invalid tenant")
+		}
+		if candidate != claimed && (claimed != "" || candidate != DefaultID) {
+			if !authenticated {
+				c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+				return "", claimed, problem.New(fiber.StatusUnauthorized, "authentication required to select a tenant")
+			}
+			slog.WarnContext(c.UserContext(), "tenant mismatch",
+				slog.String("source", source),
+				slog.String("requested", candidate),
+				slog.String("claimed", claimed),
+			)
+			return "", claimed, problem.New(fiber.StatusForbidden, "tenant does not match the credentials")
+		}
+		if resolved == "" {
+			resolved = candidate
+		}
+	}
+	return resolved, claimed, nil
+}
+
+// subdomain returns the label of host directly under baseDomain, e.g. "acme" for
+// "acme.example.com" and "example.com".
+func subdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !ok || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -0,0 +1,50 @@
+package tenant
+
+import "context"
+
+// Header is the HTTP header carrying the tenant of a request.
+const Header = "X-Tenant-ID"
+
+// DefaultID is the tenant of the requests that do not resolve to any tenant.
+const DefaultID = "default"
+
+// MaxLength is the maximum length of a tenant ID, that of a DNS label.
+const MaxLength = 63
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the tenant ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the tenant ID carried by ctx, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok && id != ""
+}
+
+// IDFromContext returns the tenant ID carried by ctx, or DefaultID. Storage must
+// partition by this ID so that a tenant can never reach the data of another.
+func IDFromContext(ctx context.Context) string {
+	if id, ok := FromContext(ctx); ok {
+		return id
+	}
+	return DefaultID
+}
+
+// Valid reports whether id can be used as a tenant ID: it must be a non-empty DNS
+// label made of lowercase letters, digits and '-', so that it is also a valid subdomain.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength || id[0] == '-' || id[len(id)-1] == '-' {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,10 @@
+package tenant
+
+import "go.uber.org/fx"
+
+// Module resolves the tenant of the requests. It must be listed after auth.Module,
+// whose principals carry a tenant claim, and before fiber.Module.
+var Module = fx.Options(
+	fx.Provide(NewConfig),
+	fx.Invoke(UseResolver),
+)
@@ -0,0 +1,210 @@
+package tenant_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arielsrv/fxf/pkg/auth"
+	"github.com/arielsrv/fxf/pkg/problem"
+	"github.com/arielsrv/fxf/pkg/tenant"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValid(t *testing.T) {
+	t.Run("should accept DNS labels", func(t *testing.T) {
+		// Act & Assert
+		assert.True(t, tenant.Valid("acme"))
+		assert.True(t, tenant.Valid("acme-2"))
+	})
+
+	t.Run("should reject anything else", func(t *testing.T) {
+		// Act & Assert
+		for _, id := range []string{
+			"", "Acme", "-acme", "acme-", "acme.corp", "acme/../globex", strings.Repeat("a", tenant.MaxLength+1),
+		} {
+			assert.False(t, tenant.Valid(id), id)
+		}
+	})
+}
+
+func TestIDFromContext(t *testing.T) {
+	t.Run("should fall back to the default tenant", func(t *testing.T) {
+		// Act & Assert
+		assert.Equal(t, tenant.DefaultID, tenant.IDFromContext(context.Background()))
+		assert.Equal(t, "acme", tenant.IDFromContext(tenant.NewContext(context.Background(), "acme")))
+	})
+}
+
+// newApp returns an app answering with the resolved tenant, authenticated as principal if set.
+func newApp(cfg tenant.Config, principal *auth.Principal) *fiber.App {
+	app := fiber.New()
+	if principal != nil {
+		app.Use(func(c *fiber.Ctx) error {
+			c.SetUserContext(auth.NewContext(c.UserContext(), principal))
+			return c.Next()
+		})
+	}
+	app.Use(tenant.Resolver(cfg))
+	app.Get("/tenant", func(c *fiber.Ctx) error {
+		id, _ := tenant.FromContext(c.UserContext())
+		return c.SendString(id)
+	})
+	return app
+}
+
+func body(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestResolver(t *testing.T) {
+	cfg := tenant.Config{
+		Sources:    []string{tenant.SourceClaim, tenant.SourceHeader, tenant.SourceSubdomain},
+		BaseDomain: "example.com",
+	}
+
+	t.Run("should resolve the tenant from the header", func(t *testing.T) {
+		// Arrange
+		headerOnly := tenant.Config{Sources: []string{tenant.SourceHeader}}
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Header.Set(tenant.Header, "acme")
+
+		// Act
+		resp, err := newApp(headerOnly, &auth.Principal{Subject: "user-1", TenantID: "acme"}).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "acme", body(t, resp))
+	})
+
+	t.Run("should resolve the tenant from the subdomain", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Host = "globex.example.com"
+		subdomainOnly := tenant.Config{Sources: []string{tenant.SourceSubdomain}, BaseDomain: cfg.BaseDomain}
+
+		// Act
+		resp, err := newApp(subdomainOnly, &auth.Principal{Subject: "user-1", TenantID: "globex"}).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "globex", body(t, resp))
+	})
+
+	t.Run("should let anonymous requests name the default tenant", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Header.Set(tenant.Header, tenant.DefaultID)
+
+		// Act
+		resp, err := newApp(cfg, nil).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, tenant.DefaultID, body(t, resp))
+	})
+
+	t.Run("should require anonymous requests to authenticate to reach a tenant", func(t *testing.T) {
+		for _, set := range []func(*http.Request){
+			func(req *http.Request) { req.Header.Set(tenant.Header, "other") },
+			func(req *http.Request) { req.Host = "other.example.com" },
+		} {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+			set(req)
+
+			// Act
+			resp, err := newApp(cfg, nil).Test(req)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+			assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+		}
+	})
+
+	t.Run("should forbid a principal without a tenant claim to reach a tenant", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Header.Set(tenant.Header, "other")
+
+		// Act
+		resp, err := newApp(cfg, &auth.Principal{Subject: "user-1"}).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("should resolve the tenant from the principal claim", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+
+		// Act
+		resp, err := newApp(cfg, &auth.Principal{Subject: "user-1", TenantID: "initech"}).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "initech", body(t, resp))
+	})
+
+	t.Run("should use the default tenant when none is resolved", func(t *testing.T) {
+		// Act
+		resp, err := newApp(cfg, nil).Test(httptest.NewRequest(http.MethodGet, "/tenant", nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, tenant.DefaultID, body(t, resp))
+	})
+
+	t.Run("should forbid a principal to reach another tenant", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Host = "globex.example.com"
+		req.Header.Set(tenant.Header, "globex")
+
+		// Act
+		resp, err := newApp(cfg, &auth.Principal{Subject: "user-1", TenantID: "acme"}).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, problem.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	})
+
+	t.Run("should reject malformed tenants", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest(http.MethodGet, "/tenant", nil)
+		req.Header.Set(tenant.Header, "../globex")
+
+		// Act
+		resp, err := newApp(cfg, nil).Test(req)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should require a tenant when configured to", func(t *testing.T) {
+		// Arrange
+		required := cfg
+		required.Required = true
+
+		// Act
+		resp, err := newApp(required, nil).Test(httptest.NewRequest(http.MethodGet, "/tenant", nil))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}